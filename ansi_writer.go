@@ -0,0 +1,95 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+)
+
+// NewANSIStripWriter creates and returns a writer that strips the ANSI escape sequences (such as
+// the color codes written by the console formatter) from the written logs before writing them to
+// the given writer, so that the colored console logs can be teed to files and collectors.
+// Each log is expected to be written with a single write call, the escape sequences split across
+// the write calls are not recognized.
+func NewANSIStripWriter(w io.Writer) io.Writer {
+	return &ansiStripWriter{w: w}
+}
+
+// This writer strips the ANSI escape sequences.
+type ansiStripWriter struct {
+	w io.Writer
+}
+
+// Write is the implementation of io.Writer interface.
+// The returned number of bytes is relative to the given data, and it is 0 if the stripped data
+// is not completely written.
+func (w *ansiStripWriter) Write(p []byte) (int, error) {
+	b := stripANSI(p)
+	n, err := w.w.Write(b)
+	if err == nil && n != len(b) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Strips the ANSI escape sequences from the given data.
+// The given data is returned directly if it does not contain any escape sequence.
+func stripANSI(p []byte) []byte {
+	i := 0
+	for i < len(p) && p[i] != 0x1b {
+		i++
+	}
+	if i == len(p) {
+		return p
+	}
+	b := make([]byte, i, len(p))
+	copy(b, p[:i])
+	for i < len(p) {
+		if p[i] != 0x1b {
+			b = append(b, p[i])
+			i++
+			continue
+		}
+		if i++; i == len(p) {
+			break
+		}
+		switch p[i] {
+		case '[':
+			// The control sequence ends with a byte in the range 0x40-0x7e.
+			for i++; i < len(p) && (p[i] < 0x40 || p[i] > 0x7e); i++ {
+			}
+			i++
+		case ']':
+			// The operating system command ends with BEL or ST (ESC \).
+			for i++; i < len(p); i++ {
+				if p[i] == 0x07 {
+					i++
+					break
+				}
+				if p[i] == 0x1b && i+1 < len(p) && p[i+1] == '\\' {
+					i += 2
+					break
+				}
+			}
+		default:
+			// The other escape sequences are two bytes.
+			i++
+		}
+	}
+	return b
+}
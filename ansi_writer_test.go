@@ -0,0 +1,76 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewANSIStripWriter(t *testing.T) {
+	if NewANSIStripWriter(new(bytes.Buffer)) == nil {
+		t.Fatal("NewANSIStripWriter(): nil")
+	}
+}
+
+func TestANSIStripWriter_Write(t *testing.T) {
+	items := []struct {
+		Given string
+		Want  string
+	}{
+		{"test", "test"},
+		{"\x1b[31mERR\x1b[0m test", "ERR test"},
+		{"\x1b[1;32;40mINF\x1b[m", "INF"},
+		{"\x1b]0;title\x07test", "test"},
+		{"\x1b]8;;url\x1b\\link", "link"},
+		{"a\x1bcb", "ab"},
+		{"test\x1b", "test"},
+		{"test\x1b[31", "test"},
+	}
+
+	for _, item := range items {
+		buf := new(bytes.Buffer)
+		w := NewANSIStripWriter(buf)
+		n, err := w.Write([]byte(item.Given))
+		if err != nil {
+			t.Fatalf("ANSIStripWriter.Write(): error %s", err)
+		}
+		if n != len(item.Given) {
+			t.Fatalf("ANSIStripWriter.Write(): %d", n)
+		}
+		if got := buf.String(); got != item.Want {
+			t.Fatalf("ANSIStripWriter.Write(): want %q, got %q", item.Want, got)
+		}
+	}
+}
+
+func TestANSIStripWriter_Write_WithConsoleFormatter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New("test")
+	l.SetFormatter(NewConsoleFormatter())
+	l.SetDefaultTimeFormat("test")
+	l.SetOutput(NewANSIStripWriter(buf))
+
+	l.Error("test")
+	if got, want := buf.String(), "test [test][ERR] test\n"; got != want {
+		t.Fatalf("ANSIStripWriter.Write(): want %q, got %q", want, got)
+	}
+}
+
+func TestANSIStripWriter_Write_WithErrorWriter(t *testing.T) {
+	if n, err := NewANSIStripWriter(testErrorWriter("test")).Write([]byte("test")); err == nil || n != 0 {
+		t.Fatalf("ANSIStripWriter.Write(): %d %v", n, err)
+	}
+}
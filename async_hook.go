@@ -0,0 +1,158 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+const (
+	// DefaultAsyncHookSize is the default queue size of the async hook.
+	DefaultAsyncHookSize = 1024
+
+	// DefaultAsyncHookWorkers is the default number of the workers of the async hook.
+	DefaultAsyncHookWorkers = 4
+)
+
+// AsyncHook interface defines the log hook that fires the given hook in a pool of
+// background workers, so the slow hooks (such as shipping the logs over HTTP or writing
+// them to a database) do not block the logging goroutines.
+type AsyncHook interface {
+	Hook
+	io.Closer
+
+	// Flush blocks until all log summaries queued before the call are fired.
+	Flush() error
+
+	// Dropped returns the number of the log summaries dropped because the queue is full.
+	Dropped() uint64
+}
+
+// NewAsyncHook creates and returns a log hook that queues the clones of the log summaries
+// into a bounded queue of the given size, and fires the given hook with them in the given
+// number of background workers. If the size is less than or equal to 0, DefaultAsyncHookSize
+// is used, and if the workers is less than or equal to 0, DefaultAsyncHookWorkers is used.
+// When the queue is full, the log summaries are dropped, so the logging is never blocked.
+// With more than one worker, the given hook is called concurrently and the log summaries
+// may be fired out of order. Since the hook is fired asynchronously, its errors are reported
+// by the internal error handler. When the returned hook is closed, the queued log summaries
+// are drained and the given hook will also be closed if it implements the io.Closer interface.
+func NewAsyncHook(hook Hook, size, workers int) AsyncHook {
+	if size <= 0 {
+		size = DefaultAsyncHookSize
+	}
+	if workers <= 0 {
+		workers = DefaultAsyncHookWorkers
+	}
+	h := &asyncHook{hook: hook, queue: make(chan Summary, size)}
+	h.cond = sync.NewCond(&h.mu)
+	h.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go h.worker()
+	}
+	return h
+}
+
+// The built-in async hook.
+type asyncHook struct {
+	dropped uint64
+	hook    Hook
+	queue   chan Summary
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending int
+	closed  bool
+}
+
+// Levels returns the log levels associated with the current log hook.
+func (h *asyncHook) Levels() []Level {
+	return h.hook.Levels()
+}
+
+// Fire queues the clone of the given log summary.
+func (h *asyncHook) Fire(s Summary) error {
+	var c Summary
+	if s.HasContext() {
+		c = s.CloneWithContext(s.Context())
+	} else {
+		c = s.Clone()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		atomic.AddUint64(&h.dropped, 1)
+		return nil
+	}
+	select {
+	case h.queue <- c:
+		h.pending++
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+	return nil
+}
+
+// Flush blocks until all log summaries queued before the call are fired.
+func (h *asyncHook) Flush() error {
+	h.mu.Lock()
+	for h.pending > 0 {
+		h.cond.Wait()
+	}
+	h.mu.Unlock()
+	return nil
+}
+
+// Dropped returns the number of the log summaries dropped because the queue is full.
+func (h *asyncHook) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Close is the implementation of io.Closer interface.
+func (h *asyncHook) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.queue)
+	h.mu.Unlock()
+
+	h.wg.Wait()
+	if c, ok := h.hook.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Fires the queued log summaries until the queue is closed and drained.
+func (h *asyncHook) worker() {
+	defer h.wg.Done()
+	for s := range h.queue {
+		if err := h.hook.Fire(s); err != nil {
+			internal.EchoError("Failed to fire async log hook: %s", err)
+		}
+		h.mu.Lock()
+		if h.pending--; h.pending == 0 {
+			h.cond.Broadcast()
+		}
+		h.mu.Unlock()
+	}
+}
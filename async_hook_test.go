@@ -0,0 +1,143 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+type testClosableHook struct {
+	mu       sync.Mutex
+	messages []string
+	closed   bool
+	block    chan struct{}
+}
+
+func (h *testClosableHook) Levels() []Level {
+	return GetAllLevels()
+}
+
+func (h *testClosableHook) Fire(s Summary) error {
+	if h.block != nil {
+		<-h.block
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, s.Message())
+	if s.Message() == "error" {
+		return errors.New("test")
+	}
+	return nil
+}
+
+func (h *testClosableHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	return nil
+}
+
+func (h *testClosableHook) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.messages)
+}
+
+func TestNewAsyncHook(t *testing.T) {
+	hook := new(testClosableHook)
+	h := NewAsyncHook(hook, 0, 0)
+	if h == nil {
+		t.Fatal("NewAsyncHook(): nil")
+	}
+	if len(h.Levels()) != len(GetAllLevels()) {
+		t.Fatalf("AsyncHook.Levels(): %v", h.Levels())
+	}
+
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	o.AddHook(h)
+	for i := 0; i < 10; i++ {
+		o.WithField("i", i).Info("test")
+	}
+	o.Info("error")
+	if err := h.Flush(); err != nil {
+		t.Fatalf("AsyncHook.Flush(): error %s", err)
+	}
+	if n := hook.Len(); n != 11 {
+		t.Fatalf("AsyncHook.Flush(): %d", n)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("AsyncHook.Close(): error %s", err)
+	}
+	if !hook.closed {
+		t.Fatal("AsyncHook.Close(): not closed")
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("AsyncHook.Close(): error %s", err)
+	}
+
+	// The log summaries are dropped after closing.
+	o.Info("test")
+	if h.Dropped() != 1 {
+		t.Fatalf("AsyncHook.Dropped(): %d", h.Dropped())
+	}
+}
+
+func TestNewAsyncHook_Full(t *testing.T) {
+	hook := &testClosableHook{block: make(chan struct{})}
+	h := NewAsyncHook(hook, 1, 1)
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	o.AddHook(h)
+	// The first one is taken by the worker, the second one is queued.
+	o.Info("test")
+	for len(h.(*asyncHook).queue) != 0 {
+		runtime.Gosched()
+	}
+	o.Info("test")
+	o.Info("test")
+	o.Info("test")
+	if h.Dropped() != 2 {
+		t.Fatalf("AsyncHook.Dropped(): %d", h.Dropped())
+	}
+	close(hook.block)
+	if err := h.Close(); err != nil {
+		t.Fatalf("AsyncHook.Close(): error %s", err)
+	}
+	if n := hook.Len(); n != 2 {
+		t.Fatalf("AsyncHook.Close(): %d", n)
+	}
+}
+
+func TestLogger_AddAsyncHook(t *testing.T) {
+	hook := new(testClosableHook)
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	if o.AddAsyncHook(hook, 10) == nil {
+		t.Fatal("Logger.AddAsyncHook(): nil")
+	}
+	for i := 0; i < 5; i++ {
+		o.Info("test")
+	}
+	if err := o.Close(); err != nil {
+		t.Fatalf("Logger.Close(): error %s", err)
+	}
+	if n := hook.Len(); n != 5 || !hook.closed {
+		t.Fatalf("Logger.AddAsyncHook(): %d %v", n, hook.closed)
+	}
+}
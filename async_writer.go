@@ -0,0 +1,165 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// DefaultAsyncWriterSize is the default queue size of the async writer.
+const DefaultAsyncWriterSize = 1024
+
+// AsyncWriter interface defines the writer that writes the logs in a background goroutine.
+type AsyncWriter interface {
+	io.WriteCloser
+	QueueStatsProvider
+
+	// Flush blocks until all logs queued before the call are written.
+	Flush() error
+}
+
+// NewAsyncWriter creates and returns a writer that queues the written logs into a bounded
+// queue of the given size, and writes them to the given writer in a dedicated background
+// goroutine, so the logging goroutines do not block on the slow I/O of the given writer.
+// If the size is less than or equal to 0, DefaultAsyncWriterSize is used. When the queue is
+// full, the Write blocks until there is room in the queue.
+// Since the logs are written asynchronously, the write errors of the given writer are
+// reported by the internal error handler. When the returned writer is closed, the queued
+// logs are drained and the given writer will also be closed if it implements the io.Closer
+// interface. The returned writer is safe for concurrent use.
+func NewAsyncWriter(w io.Writer, size int) AsyncWriter {
+	if size <= 0 {
+		size = DefaultAsyncWriterSize
+	}
+	aw := &asyncWriter{w: w, queue: make(chan asyncRecord, size), done: make(chan struct{})}
+	go aw.worker()
+	return aw
+}
+
+// The asyncRecord type is the record of the async writer queue, a record with a non-nil
+// flushed channel is a flush marker.
+type asyncRecord struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// The built-in async writer.
+type asyncWriter struct {
+	mu     sync.RWMutex
+	w      io.Writer
+	queue  chan asyncRecord
+	done   chan struct{}
+	closed bool
+
+	statsMu sync.Mutex
+	stats   QueueStats
+	pending []time.Time // The enqueue times of the queued logs, from oldest to newest.
+}
+
+// Write is the implementation of io.Writer interface.
+// The given data is copied, so the caller can reuse it after the call.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	data := make([]byte, len(p))
+	copy(data, p)
+	w.statsMu.Lock()
+	w.pending = append(w.pending, time.Now())
+	w.statsMu.Unlock()
+	w.queue <- asyncRecord{data: data}
+	return len(p), nil
+}
+
+// Flush blocks until all logs queued before the call are written.
+func (w *asyncWriter) Flush() error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return io.ErrClosedPipe
+	}
+	flushed := make(chan struct{})
+	w.queue <- asyncRecord{flushed: flushed}
+	w.mu.RUnlock()
+	<-flushed
+	return nil
+}
+
+// Close is the implementation of io.Closer interface.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	if c, ok := w.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Stats returns the current queue statistics.
+// The flushes of the async writer are the writes to the underlying writer.
+func (w *asyncWriter) Stats() QueueStats {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+	stats := w.stats
+	stats.Depth = len(w.queue)
+	if stats.Depth > 0 && len(w.pending) > 0 {
+		stats.OldestAge = time.Since(w.pending[0])
+	}
+	return stats
+}
+
+// Writes the queued logs until the queue is closed and drained.
+func (w *asyncWriter) worker() {
+	defer close(w.done)
+	for r := range w.queue {
+		if r.flushed != nil {
+			close(r.flushed)
+			continue
+		}
+		w.statsMu.Lock()
+		w.pending = w.pending[1:]
+		w.statsMu.Unlock()
+		start := time.Now()
+		n, err := w.w.Write(r.data)
+		if err == nil && n != len(r.data) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			internal.EchoError("Failed to write async log: %s", err)
+		}
+		latency := time.Since(start)
+		w.statsMu.Lock()
+		w.stats.Flushes++
+		w.stats.LastFlushLatency = latency
+		if latency > w.stats.MaxFlushLatency {
+			w.stats.MaxFlushLatency = latency
+		}
+		w.statsMu.Unlock()
+	}
+}
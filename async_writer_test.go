@@ -0,0 +1,131 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestNewAsyncWriter(t *testing.T) {
+	w := NewAsyncWriter(new(testSyncBuffer), 0)
+	if w == nil {
+		t.Fatal("NewAsyncWriter(): nil")
+	}
+	if got := cap(w.(*asyncWriter).queue); got != DefaultAsyncWriterSize {
+		t.Fatalf("NewAsyncWriter(): queue size %d", got)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("AsyncWriter.Close(): %s", err)
+	}
+}
+
+func TestAsyncWriter_Write(t *testing.T) {
+	b := new(testSyncBuffer)
+	w := NewAsyncWriter(b, 2)
+
+	p := []byte("a\n")
+	for i := 0; i < 10; i++ {
+		if n, err := w.Write(p); err != nil || n != 2 {
+			t.Fatalf("AsyncWriter.Write(): %d %v", n, err)
+		}
+	}
+	// The written data can be reused by the caller.
+	p[0] = 'b'
+	if err := w.Flush(); err != nil {
+		t.Fatalf("AsyncWriter.Flush(): %s", err)
+	}
+	if got := string(b.Bytes()); got != strings.Repeat("a\n", 10) {
+		t.Fatalf("AsyncWriter.Flush(): %q", got)
+	}
+	if s := w.Stats(); s.Depth != 0 || s.Flushes != 10 || s.MaxFlushLatency < s.LastFlushLatency {
+		t.Fatalf("AsyncWriter.Stats(): %+v", s)
+	}
+
+	_, _ = w.Write([]byte("c\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("AsyncWriter.Close(): %s", err)
+	}
+	if got := string(b.Bytes()); !strings.HasSuffix(got, "c\n") || !b.closed {
+		t.Fatalf("AsyncWriter.Close(): %q", got)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("AsyncWriter.Close(): %s", err)
+	}
+	if _, err := w.Write([]byte("d\n")); err != io.ErrClosedPipe {
+		t.Fatalf("AsyncWriter.Write(): %v", err)
+	}
+	if err := w.Flush(); err != io.ErrClosedPipe {
+		t.Fatalf("AsyncWriter.Flush(): %v", err)
+	}
+}
+
+type testBlockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *testBlockingWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+	return len(p), nil
+}
+
+func TestAsyncWriter_Stats(t *testing.T) {
+	b := &testBlockingWriter{started: make(chan struct{}, 2), release: make(chan struct{})}
+	w := NewAsyncWriter(b, 2)
+
+	if s := w.Stats(); s.Depth != 0 || s.OldestAge != 0 {
+		t.Fatalf("AsyncWriter.Stats(): %+v", s)
+	}
+	_, _ = w.Write([]byte("a\n"))
+	// The first log is being written, and the second log is queued.
+	<-b.started
+	_, _ = w.Write([]byte("b\n"))
+	time.Sleep(time.Millisecond)
+	if s := w.Stats(); s.Depth != 1 || s.OldestAge <= 0 {
+		t.Fatalf("AsyncWriter.Stats(): %+v", s)
+	}
+	close(b.release)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("AsyncWriter.Flush(): %s", err)
+	}
+	if s := w.Stats(); s.Depth != 0 || s.OldestAge != 0 || s.Flushes != 2 {
+		t.Fatalf("AsyncWriter.Stats(): %+v", s)
+	}
+}
+
+func TestAsyncWriter_Write_Error(t *testing.T) {
+	buf := new(bytes.Buffer)
+	internal.ErrorWriter = buf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	w := NewAsyncWriter(testErrorWriter("test"), 1)
+	if _, err := w.Write([]byte("a\n")); err != nil {
+		t.Fatalf("AsyncWriter.Write(): %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("AsyncWriter.Close(): %s", err)
+	}
+	if got := buf.String(); got != "Failed to write async log: test\n" {
+		t.Fatalf("AsyncWriter.Write(): %q", got)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// NewBatchWriter creates and returns a writer that accumulates the written logs and writes
+// them to the given writer in a single write call, which can greatly reduce the number of
+// system calls and network round-trips.
+// The accumulated logs are written when the number of logs reaches the given size, or when
+// the given interval has elapsed since the last write. If the size is less than or equal to
+// 0, the number of logs is not limited. If the interval is less than or equal to 0, the
+// accumulated logs are only written when the size is reached or the writer is closed.
+// When the returned writer is closed, the accumulated logs are written and the given writer
+// will also be closed if it implements the io.Closer interface.
+// The returned writer is safe for concurrent use, and it implements the QueueStatsProvider
+// interface.
+func NewBatchWriter(w io.Writer, size int, interval time.Duration) io.WriteCloser {
+	bw := &batchWriter{w: w, size: size}
+	if interval > 0 {
+		bw.done = make(chan struct{})
+		go bw.flusher(interval)
+	}
+	return bw
+}
+
+// The built-in batch writer.
+type batchWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	size   int
+	buffer bytes.Buffer
+	count  int
+	done   chan struct{}
+	closed bool
+	oldest time.Time
+	stats  QueueStats
+}
+
+// Write is the implementation of io.Writer interface.
+// If the accumulated logs fail to write, the error is returned and the accumulated logs
+// (including the given log) are discarded.
+func (w *batchWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if w.count == 0 {
+		w.oldest = time.Now()
+	}
+	w.buffer.Write(p)
+	w.count++
+	if w.size > 0 && w.count >= w.size {
+		if err := w.flush(); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Close is the implementation of io.Closer interface.
+func (w *batchWriter) Close() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.done != nil {
+		close(w.done)
+	}
+	err = w.flush()
+	if c, ok := w.w.(io.Closer); ok {
+		if err2 := c.Close(); err == nil {
+			err = err2
+		}
+	}
+	return
+}
+
+// Stats returns the current queue statistics.
+func (w *batchWriter) Stats() QueueStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats := w.stats
+	if stats.Depth = w.count; w.count > 0 {
+		stats.OldestAge = time.Since(w.oldest)
+	}
+	return stats
+}
+
+// Writes the accumulated logs to the writer, the caller must hold the lock.
+func (w *batchWriter) flush() error {
+	if w.count == 0 {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		w.buffer.Reset()
+		w.count = 0
+		w.stats.Flushes++
+		w.stats.LastFlushLatency = time.Since(start)
+		if w.stats.LastFlushLatency > w.stats.MaxFlushLatency {
+			w.stats.MaxFlushLatency = w.stats.LastFlushLatency
+		}
+	}()
+	n, err := w.w.Write(w.buffer.Bytes())
+	if err == nil && n != w.buffer.Len() {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// Writes the accumulated logs periodically until the writer is closed.
+func (w *batchWriter) flusher(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if !w.closed {
+				if err := w.flush(); err != nil {
+					internal.EchoError("Failed to write batched logs: %s.", err)
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
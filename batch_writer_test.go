@@ -0,0 +1,79 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewBatchWriter(t *testing.T) {
+	w := NewBatchWriter(new(testChunkWriter), 10, time.Second)
+	if w == nil {
+		t.Fatal("NewBatchWriter(): nil")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("BatchWriter.Close(): %s", err)
+	}
+}
+
+func TestBatchWriter_Write(t *testing.T) {
+	cw := new(testChunkWriter)
+	w := NewBatchWriter(cw, 3, 0)
+
+	for _, s := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		if n, err := w.Write([]byte(s)); err != nil || n != 2 {
+			t.Fatalf("BatchWriter.Write(): %d %v", n, err)
+		}
+	}
+	if len(cw.chunks) != 1 || cw.chunks[0] != "a\nb\nc\n" {
+		t.Fatalf("BatchWriter.Write(): %q", cw.chunks)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("BatchWriter.Close(): %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("BatchWriter.Close(): %s", err)
+	}
+	if len(cw.chunks) != 2 || cw.chunks[1] != "d\n" {
+		t.Fatalf("BatchWriter.Close(): %q", cw.chunks)
+	}
+	if _, err := w.Write([]byte("e\n")); err == nil {
+		t.Fatal("BatchWriter.Write(): nil error after closed")
+	}
+}
+
+func TestBatchWriter_WriteInterval(t *testing.T) {
+	b := new(testSyncBuffer)
+	w := NewBatchWriter(b, 0, time.Millisecond*10)
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Write([]byte("a\n")); err != nil {
+		t.Fatalf("BatchWriter.Write(): %s", err)
+	}
+	for i := 0; i < 100 && b.Len() == 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	if got := string(b.Bytes()); got != "a\n" {
+		t.Fatalf("BatchWriter.Write(): %q", got)
+	}
+}
+
+func TestBatchWriter_WriteError(t *testing.T) {
+	w := NewBatchWriter(testErrorWriter("test"), 1, 0)
+	if _, err := w.Write([]byte("a\n")); err == nil {
+		t.Fatal("BatchWriter.Write(): nil error")
+	}
+}
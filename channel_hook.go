@@ -0,0 +1,86 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// ChannelHookPolicy defines the delivery policy of the channel hook when the channel is full.
+type ChannelHookPolicy int
+
+const (
+	// ChannelHookDrop indicates that the log summary is dropped when the channel is full,
+	// so that the logging is never blocked by the slow consumers.
+	ChannelHookDrop ChannelHookPolicy = iota
+
+	// ChannelHookBlock indicates that the logging is blocked until the log summary is
+	// received by the consumers.
+	ChannelHookBlock
+)
+
+// ChannelHook interface defines the log hook that delivers the log summaries to a channel,
+// which is a simple way for the applications to build their own async consumers.
+type ChannelHook interface {
+	Hook
+
+	// Dropped returns the number of the log summaries dropped because the channel is full.
+	Dropped() uint64
+}
+
+// NewChannelHook creates and returns a log hook that delivers the clones of the log summaries
+// of the given levels to the given channel with the given policy.
+// The channel must not be closed before the hook is removed from the logger.
+func NewChannelHook(levels []Level, ch chan<- Summary, policy ChannelHookPolicy) ChannelHook {
+	return &channelHook{levels: levels, ch: ch, policy: policy}
+}
+
+// The built-in channel hook.
+type channelHook struct {
+	dropped uint64
+	levels  []Level
+	ch      chan<- Summary
+	policy  ChannelHookPolicy
+}
+
+// Levels returns the log levels associated with the current log hook.
+func (h *channelHook) Levels() []Level {
+	return h.levels
+}
+
+// Fire delivers the clone of the given log summary to the channel.
+func (h *channelHook) Fire(s Summary) error {
+	var c Summary
+	if s.HasContext() {
+		c = s.CloneWithContext(s.Context())
+	} else {
+		c = s.Clone()
+	}
+	if h.policy == ChannelHookBlock {
+		h.ch <- c
+		return nil
+	}
+	select {
+	case h.ch <- c:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+	return nil
+}
+
+// Dropped returns the number of the log summaries dropped because the channel is full.
+func (h *channelHook) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
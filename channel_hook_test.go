@@ -0,0 +1,77 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewChannelHook(t *testing.T) {
+	ch := make(chan Summary, 1)
+	h := NewChannelHook([]Level{InfoLevel}, ch, ChannelHookDrop)
+	if h == nil {
+		t.Fatal("NewChannelHook(): nil")
+	}
+	if got := h.Levels(); len(got) != 1 || got[0] != InfoLevel {
+		t.Fatalf("ChannelHook.Levels(): %v", got)
+	}
+}
+
+func TestChannelHook_Fire(t *testing.T) {
+	ch := make(chan Summary, 1)
+	h := NewChannelHook(GetAllLevels(), ch, ChannelHookDrop)
+
+	l := New("test")
+	l.SetOutput(new(bytes.Buffer))
+	l.AddHook(h)
+
+	l.Info("foo")
+	l.Info("bar")
+
+	if got := h.Dropped(); got != 1 {
+		t.Fatalf("ChannelHook.Dropped(): %d", got)
+	}
+	// The delivered summary is a clone, it is not recycled by the logger.
+	if s := <-ch; s.Message() != "foo" || s.String() == "" {
+		t.Fatalf("ChannelHook.Fire(): %q %q", s.Message(), s.String())
+	}
+}
+
+func TestChannelHook_Fire_WithBlock(t *testing.T) {
+	ch := make(chan Summary)
+	h := NewChannelHook(GetAllLevels(), ch, ChannelHookBlock)
+
+	l := New("test")
+	l.SetOutput(new(bytes.Buffer))
+	l.AddHook(h)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Info("foo")
+		l.Info("bar")
+	}()
+
+	for _, want := range []string{"foo", "bar"} {
+		if s := <-ch; s.Message() != want {
+			t.Fatalf("ChannelHook.Fire(): want %q, got %q", want, s.Message())
+		}
+	}
+	<-done
+	if got := h.Dropped(); got != 0 {
+		t.Fatalf("ChannelHook.Dropped(): %d", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+// CheckedEntry interface defines the log entry whose level has been checked by Log.Check.
+// The checked entry is not safe for concurrent use, and it should be written only once.
+type CheckedEntry interface {
+	// With adds the given key-value pairs to the current entry.
+	With(pairs ...interface{}) CheckedEntry
+
+	// WithField adds the given extended data to the current entry.
+	WithField(string, interface{}) CheckedEntry
+
+	// WithError adds the given error to the current entry.
+	WithError(error) CheckedEntry
+
+	// Write records the current entry.
+	// If the level of the entry is PanicLevel or FatalLevel, the panic function or the
+	// exit function is called after the entry is recorded.
+	Write()
+}
+
+// The built-in checked entry.
+type checkedEntry struct {
+	log     *log
+	level   Level
+	message string
+}
+
+// With adds the given key-value pairs to the current entry.
+func (e *checkedEntry) With(pairs ...interface{}) CheckedEntry {
+	e.log = e.log.withFieldPairs(pairs...)
+	return e
+}
+
+// WithField adds the given extended data to the current entry.
+func (e *checkedEntry) WithField(key string, value interface{}) CheckedEntry {
+	e.log = e.log.withField(key, value)
+	return e
+}
+
+// WithError adds the given error to the current entry.
+func (e *checkedEntry) WithError(err error) CheckedEntry {
+	e.log = e.log.withField("error", err)
+	return e
+}
+
+// Write records the current entry.
+func (e *checkedEntry) Write() {
+	e.write()
+}
+
+// Records the current entry, this keeps the call depth the same as Log.Info.
+func (e *checkedEntry) write() {
+	e.log.record(e.level, e.message, true)
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLog_Check(t *testing.T) {
+	buf := new(bytes.Buffer)
+	o := New("test").SetOutput(buf).SetFormatter(DefaultJSONFormatter()).SetLevel(InfoLevel).EnableCaller()
+
+	if ce := o.Check(DebugLevel, "test"); ce != nil {
+		t.Fatal("Log.Check(): disabled level returns entry")
+	}
+	ce := o.WithField("foo", 1).Check(InfoLevel, "test")
+	if ce == nil {
+		t.Fatal("Log.Check(): nil")
+	}
+	ce.With("bar", 2).WithField("baz", 3).WithError(errors.New("err")).Write()
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("CheckedEntry.Write(): %s", err)
+	}
+	fields, _ := m["fields"].(map[string]interface{})
+	if m["level"] != "info" || m["message"] != "test" || fields["foo"] != float64(1) ||
+		fields["bar"] != float64(2) || fields["baz"] != float64(3) || fields["error"] != "err" {
+		t.Fatalf("CheckedEntry.Write(): %v", m)
+	}
+	if caller, _ := m["caller"].(string); !strings.Contains(caller, "checked_entry_test.go") {
+		t.Fatalf("CheckedEntry.Write(): caller %q", caller)
+	}
+
+	var code int
+	o.SetExitFunc(func(c int) { code = c })
+	o.Check(FatalLevel, "test").Write()
+	if code != 1 {
+		t.Fatalf("CheckedEntry.Write(): exit code %d", code)
+	}
+}
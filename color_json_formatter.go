@@ -0,0 +1,122 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// NewColorJSONFormatter creates and returns an instance of the log colorful JSON formatter.
+// The colorful JSON formatter is designed for the development consoles, it emits the same
+// JSON as the default JSON formatter, with the keys and the log level highlighted, which
+// combines the machine-parseable structure with the human-friendly terminal reading.
+// If the colorful parameter is false, the colors are not emitted, the IsTerminal function
+// can be used to determine it, such as NewColorJSONFormatter(IsTerminal(os.Stdout)).
+func NewColorJSONFormatter(colorful bool) Formatter {
+	return &colorJSONFormatter{json: DefaultJSONFormatter(), colorful: colorful}
+}
+
+// IsTerminal determines whether the given writer is a terminal (character device).
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// The colorJSONFormatter type is the built-in colorful JSON formatter.
+type colorJSONFormatter struct {
+	json     Formatter
+	colorful bool
+}
+
+// The buffer pool of the colorful JSON formatter.
+var colorJSONBufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// Format formats the given log entity into character data and writes it to the given buffer.
+func (f *colorJSONFormatter) Format(e Entity, b *bytes.Buffer) error {
+	if !f.colorful {
+		return f.json.Format(e, b)
+	}
+	buf := colorJSONBufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		colorJSONBufferPool.Put(buf)
+	}()
+	if err := f.json.Format(e, buf); err != nil {
+		return err
+	}
+	colorizeJSON(b, buf.Bytes(), e.Level())
+	return nil
+}
+
+// Writes the given JSON data to the given buffer with the keys and the value of the top
+// level "level" key highlighted.
+func colorizeJSON(b *bytes.Buffer, data []byte, level Level) {
+	var depth int
+	var isLevel bool
+	for i, n := 0, len(data); i < n; {
+		switch c := data[i]; c {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		case '"':
+			j := scanJSONString(data, i)
+			k := j
+			for k < n && (data[k] == ' ' || data[k] == '\t') {
+				k++
+			}
+			switch {
+			case k < n && data[k] == ':':
+				b.WriteString(internal.KEY)
+				b.Write(data[i:j])
+				b.WriteString(internal.RST)
+				isLevel = depth == 1 && string(data[i:j]) == `"level"`
+			case isLevel:
+				b.WriteByte('"')
+				b.WriteString(level.ColorfulString())
+				b.WriteByte('"')
+				isLevel = false
+			default:
+				b.Write(data[i:j])
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(data[i])
+		i++
+	}
+}
+
+// Returns the index after the end of the JSON string starting at the given index.
+func scanJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestNewColorJSONFormatter(t *testing.T) {
+	w := new(bytes.Buffer)
+	o := New("test")
+	o.SetOutput(w)
+	o.SetFormatter(NewColorJSONFormatter(false))
+
+	o.WithField("key", `va"lue`).Info("test")
+	var m map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &m); err != nil {
+		t.Fatalf("NewColorJSONFormatter(): %s %q", err, w.String())
+	}
+	if m["level"] != "info" || m["message"] != "test" {
+		t.Fatalf("NewColorJSONFormatter(): %v", m)
+	}
+
+	w.Reset()
+	o.SetFormatter(NewColorJSONFormatter(true))
+	o.WithField("level", "x").WithField("key", `va"lue`).Error("test")
+	got := w.String()
+	for _, s := range []string{
+		internal.KEY + `"level"` + internal.RST + `:"` + ErrorLevel.ColorfulString() + `"`,
+		internal.KEY + `"message"` + internal.RST + `:"test"`,
+		internal.KEY + `"key"` + internal.RST + `:"va\"lue"`,
+		internal.KEY + `"level"` + internal.RST + `:"x"`,
+	} {
+		if !strings.Contains(got, s) {
+			t.Fatalf("NewColorJSONFormatter(): missing %q in %q", s, got)
+		}
+	}
+	if !strings.HasSuffix(got, "}\n") {
+		t.Fatalf("NewColorJSONFormatter(): %q", got)
+	}
+	if stripped := stripANSI([]byte(got)); !json.Valid(stripped) {
+		t.Fatalf("NewColorJSONFormatter(): invalid JSON %q", stripped)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if IsTerminal(new(bytes.Buffer)) {
+		t.Fatal("IsTerminal(): true")
+	}
+	f, err := os.CreateTemp(t.TempDir(), "*.log")
+	if err != nil {
+		t.Fatalf("os.CreateTemp(): %s", err)
+	}
+	defer f.Close()
+	if IsTerminal(f) {
+		t.Fatal("IsTerminal(): true")
+	}
+}
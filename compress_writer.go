@@ -0,0 +1,113 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// NewCompressWriter creates and returns a writer that compresses the written data in gzip
+// format and writes it to the given writer.
+// The level parameter is the gzip compression level, such as gzip.DefaultCompression.
+// The compressed data is flushed to the given writer periodically at the given interval, if
+// the interval is less than or equal to 0, the data is only flushed when the writer is closed.
+// When the returned writer is closed, the given writer will also be closed if it implements
+// the io.Closer interface.
+// The returned writer is safe for concurrent use.
+func NewCompressWriter(w io.Writer, level int, interval time.Duration) (io.WriteCloser, error) {
+	gw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	cw := &compressWriter{w: w, gw: gw}
+	if interval > 0 {
+		cw.done = make(chan struct{})
+		go cw.flusher(interval)
+	}
+	return cw, nil
+}
+
+// MustNewCompressWriter is like NewCompressWriter, but triggers a panic when an error occurs.
+func MustNewCompressWriter(w io.Writer, level int, interval time.Duration) io.WriteCloser {
+	cw, err := NewCompressWriter(w, level, interval)
+	if err != nil {
+		panic(err)
+	}
+	return cw
+}
+
+// The built-in gzip compress writer.
+type compressWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	gw     *gzip.Writer
+	done   chan struct{}
+	closed bool
+}
+
+// Write is the implementation of io.Writer interface.
+func (w *compressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	return w.gw.Write(p)
+}
+
+// Close is the implementation of io.Closer interface.
+func (w *compressWriter) Close() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.done != nil {
+		close(w.done)
+	}
+	err = w.gw.Close()
+	if c, ok := w.w.(io.Closer); ok {
+		if err2 := c.Close(); err == nil {
+			err = err2
+		}
+	}
+	return
+}
+
+// Flushes the compressed data periodically until the writer is closed.
+func (w *compressWriter) flusher(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if !w.closed {
+				if err := w.gw.Flush(); err != nil {
+					internal.EchoError("Failed to flush compressed data: %s.", err)
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSyncBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (b *testSyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *testSyncBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
+
+func (b *testSyncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func (b *testSyncBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+func TestNewCompressWriter(t *testing.T) {
+	if _, err := NewCompressWriter(new(bytes.Buffer), 100, 0); err == nil {
+		t.Fatal("NewCompressWriter(): nil error")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustNewCompressWriter(): no panic")
+		}
+	}()
+	MustNewCompressWriter(new(bytes.Buffer), 100, 0)
+}
+
+func TestCompressWriter(t *testing.T) {
+	b := new(testSyncBuffer)
+	w := MustNewCompressWriter(b, gzip.BestSpeed, time.Millisecond*10)
+
+	if n, err := w.Write([]byte("foo\n")); err != nil || n != 4 {
+		t.Fatalf("CompressWriter.Write(): %d %v", n, err)
+	}
+	for i := 0; i < 100 && b.Len() == 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	if b.Len() == 0 {
+		t.Fatal("CompressWriter.Write(): not flushed")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("CompressWriter.Close(): %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("CompressWriter.Close(): %v", err)
+	}
+	if !b.closed {
+		t.Fatal("CompressWriter.Close(): writer not closed")
+	}
+	if _, err := w.Write([]byte("foo\n")); err == nil {
+		t.Fatal("CompressWriter.Write(): nil error after closed")
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(r); err != nil || string(got) != "foo\n" {
+		t.Fatalf("CompressWriter: %q %v", got, err)
+	}
+}
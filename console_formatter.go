@@ -28,8 +28,24 @@ func NewConsoleFormatter() Formatter {
 	return new(consoleFormatter)
 }
 
+// ConsoleFormatterOptions defines the options of the log console formatter.
+type ConsoleFormatterOptions struct {
+	// MultilineIndent is used to indent the continuation lines of the multi-line messages
+	// and field values, such as "\t", so the wrapped messages remain visually grouped and
+	// are not mistaken for separate logs. If it is empty, the messages are kept as is.
+	MultilineIndent string
+}
+
+// NewConsoleFormatterWithOptions creates and returns an instance of the log console formatter
+// with the given options.
+func NewConsoleFormatterWithOptions(opts ConsoleFormatterOptions) Formatter {
+	return &consoleFormatter{indent: opts.MultilineIndent}
+}
+
 // The built-in console formatter.
-type consoleFormatter struct{}
+type consoleFormatter struct {
+	indent string
+}
 
 // Format formats the given log entity into character data and writes it to the given buffer.
 func (f *consoleFormatter) Format(e Entity, b *bytes.Buffer) (err error) {
@@ -40,12 +56,12 @@ func (f *consoleFormatter) Format(e Entity, b *bytes.Buffer) (err error) {
 		b.WriteString("[" + tm + "]")
 	}
 	b.WriteString("[" + e.Level().ColorfulShortCapitalString() + "] ")
-	b.WriteString(e.Message())
+	b.WriteString(internal.IndentLines(e.Message(), f.indent))
 	if caller := e.Caller(); caller != "" {
 		b.WriteString(" " + caller)
 	}
 	if fields := e.Fields(); len(fields) > 0 {
-		b.WriteString(" " + internal.FormatFieldsToText(e.Fields()))
+		b.WriteString(" " + internal.IndentLines(internal.FormatFieldsToText(e.Fields()), f.indent))
 	}
 	if stack := e.Stack(); len(stack) > 0 {
 		// In the console, in order to be able to display the stack information better,
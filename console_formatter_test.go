@@ -28,6 +28,22 @@ func TestNewConsoleFormatter(t *testing.T) {
 	}
 }
 
+func TestNewConsoleFormatterWithOptions(t *testing.T) {
+	f := NewConsoleFormatterWithOptions(ConsoleFormatterOptions{MultilineIndent: "\t"})
+	if f == nil {
+		t.Fatal("NewConsoleFormatterWithOptions(): return nil.")
+	}
+	e := &logEntity{level: InfoLevel, message: "foo\nbar", fields: map[string]interface{}{"a": "x\ny"}}
+	buf := new(bytes.Buffer)
+	if err := f.Format(e, buf); err != nil {
+		t.Fatalf("ConsoleFormatter.Format(): error %s", err)
+	}
+	want := "[" + InfoLevel.ColorfulShortCapitalString() + "] foo\n\tbar a=x\n\ty\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("ConsoleFormatter.Format(): want %q, got %q", want, got)
+	}
+}
+
 func TestConsoleFormatter_Format(t *testing.T) {
 	l := New("CONSOLE")
 	l.SetFormatter(NewConsoleFormatter())
@@ -0,0 +1,106 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// The key type of the log stored in the context.
+type logContextKey struct{}
+
+// The key type of the minimum log level stored in the context.
+type minLevelContextKey struct{}
+
+// The fallback log returned by FromContext, it stores the logHolder.
+var contextFallback atomic.Value
+
+// The atomic.Value requires that all stored values have the same concrete type.
+type logHolder struct {
+	log Log
+}
+
+func init() {
+	contextFallback.Store(logHolder{New("").AsLog()})
+}
+
+// NewContext returns a copy of the given context that carries the given log.
+// This is used to pass request-scoped logs through the call stack.
+func NewContext(ctx context.Context, l Log) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, logContextKey{}, l)
+}
+
+// FromContext returns the log carried by the given context.
+// If the given context does not carry a log, the fallback log is returned.
+func FromContext(ctx context.Context) Log {
+	if l, found := LookupContext(ctx); found {
+		return l
+	}
+	return contextFallback.Load().(logHolder).log
+}
+
+// LookupContext returns the log carried by the given context and whether it was found.
+func LookupContext(ctx context.Context) (Log, bool) {
+	if ctx != nil {
+		if l, ok := ctx.Value(logContextKey{}).(Log); ok && l != nil {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// SetContextFallback sets the fallback log returned by FromContext.
+// By default, the fallback log is a logger with an empty name and default settings.
+// If the given log is nil, the default fallback log is used.
+func SetContextFallback(l Log) {
+	if l == nil {
+		l = New("").AsLog()
+	}
+	contextFallback.Store(logHolder{l})
+}
+
+// WithMinLevel returns a copy of the given context that carries the given minimum log level.
+// The logs carrying the returned context (see Log.WithContext) bypass the logger level check,
+// and all logs included in the given level are recorded, which enables the per-request debug
+// logs without changing the logger level:
+//
+//	ctx = logger.WithMinLevel(ctx, logger.DebugLevel)
+//	log.WithContext(ctx).Debug("recorded even if the logger level is InfoLevel")
+//
+// The minimum level can only make more logs recorded, it never hides the logs enabled by the
+// logger level. If the given level is invalid, the given context is returned unchanged.
+func WithMinLevel(ctx context.Context, level Level) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !level.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, minLevelContextKey{}, level)
+}
+
+// GetMinLevel returns the minimum log level carried by the given context and whether it was found.
+func GetMinLevel(ctx context.Context) (Level, bool) {
+	if ctx != nil {
+		if level, ok := ctx.Value(minLevelContextKey{}).(Level); ok {
+			return level, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewContext(t *testing.T) {
+	l := New("test").WithField("foo", "bar")
+	ctx := NewContext(context.Background(), l)
+
+	if got := FromContext(ctx); got != l {
+		t.Fatalf("FromContext(): %v", got)
+	}
+	if got, found := LookupContext(ctx); !found || got != l {
+		t.Fatalf("LookupContext(): %v %v", got, found)
+	}
+	if got := FromContext(NewContext(nil, l)); got != l {
+		t.Fatalf("FromContext(): %v", got)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got == nil {
+		t.Fatal("FromContext(): nil")
+	}
+	if _, found := LookupContext(nil); found {
+		t.Fatal("LookupContext(nil): found")
+	}
+
+	l := New("fallback").AsLog()
+	SetContextFallback(l)
+	defer SetContextFallback(nil)
+
+	if got := FromContext(context.Background()); got != l {
+		t.Fatalf("FromContext(): %v", got)
+	}
+	SetContextFallback(nil)
+	if got := FromContext(context.Background()); got == l || got.Name() != "" {
+		t.Fatalf("FromContext(): %v", got)
+	}
+}
+
+func TestWithMinLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetLevel(InfoLevel)
+
+	ctx := WithMinLevel(context.Background(), DebugLevel)
+	if level, found := GetMinLevel(ctx); !found || level != DebugLevel {
+		t.Fatalf("GetMinLevel(): %s, %v", level, found)
+	}
+	l.WithContext(ctx).Debug("debug")
+	l.WithContext(ctx).WithField("key", "value").Trace("trace")
+	l.Debug("hidden")
+	if got := buf.String(); !strings.Contains(got, "debug") || strings.Contains(got, "trace") || strings.Contains(got, "hidden") {
+		t.Fatalf("WithMinLevel(): %q", got)
+	}
+	if !l.WithContext(ctx).IsDebugLevelEnabled() || l.IsDebugLevelEnabled() {
+		t.Fatal("WithMinLevel(): IsDebugLevelEnabled")
+	}
+
+	// The minimum level never hides the logs enabled by the logger level.
+	buf.Reset()
+	l.WithContext(WithMinLevel(context.Background(), ErrorLevel)).Info("info")
+	if buf.Len() == 0 {
+		t.Fatal("WithMinLevel(): info log hidden")
+	}
+
+	if _, found := GetMinLevel(WithMinLevel(nil, Level(0))); found {
+		t.Fatal("GetMinLevel(): invalid level found")
+	}
+	if _, found := GetMinLevel(nil); found {
+		t.Fatal("GetMinLevel(): nil context found")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// DefaultCorrelationIDField is the default log field name of the correlation id.
+const DefaultCorrelationIDField = "correlation_id"
+
+// The key type of the correlation id stored in the context.
+type correlationIDContextKey struct{}
+
+// NewCorrelationID generates and returns a new correlation id in UUID version 7 format.
+// The UUID version 7 is time-ordered, which is friendly to log storage and sorting.
+func NewCorrelationID() string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	// The crypto/rand.Read never fails on the supported platforms.
+	_, _ = rand.Read(b[6:])
+	b[6] = b[6]&0x0f | 0x70 // Version 7.
+	b[8] = b[8]&0x3f | 0x80 // Variant RFC 4122.
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// WithCorrelationID returns a copy of the given context that carries the given correlation id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, correlationIDContextKey{}, id)
+}
+
+// GetCorrelationID returns the correlation id carried by the given context.
+// If the given context does not carry a correlation id, an empty string is returned.
+func GetCorrelationID(ctx context.Context) string {
+	if ctx != nil {
+		if id, ok := ctx.Value(correlationIDContextKey{}).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// EnsureCorrelationID ensures that the given context carries a correlation id, and returns
+// the context and the correlation id.
+// If the given context does not carry a correlation id, a new correlation id is generated by
+// the given function and installed into the returned context. If the given function is nil,
+// NewCorrelationID is used.
+func EnsureCorrelationID(ctx context.Context, generate func() string) (context.Context, string) {
+	if id := GetCorrelationID(ctx); id != "" {
+		return ctx, id
+	}
+	if generate == nil {
+		generate = NewCorrelationID
+	}
+	id := generate()
+	return WithCorrelationID(ctx, id), id
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestNewCorrelationID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := NewCorrelationID(), NewCorrelationID()
+	if !re.MatchString(a) || !re.MatchString(b) {
+		t.Fatalf("NewCorrelationID(): %s %s", a, b)
+	}
+	if a == b {
+		t.Fatalf("NewCorrelationID(): duplicate %s", a)
+	}
+}
+
+func TestEnsureCorrelationID(t *testing.T) {
+	ctx, id := EnsureCorrelationID(context.Background(), func() string { return "foo" })
+	if id != "foo" || GetCorrelationID(ctx) != "foo" {
+		t.Fatalf("EnsureCorrelationID(): %s %s", id, GetCorrelationID(ctx))
+	}
+	if ctx2, id2 := EnsureCorrelationID(ctx, nil); ctx2 != ctx || id2 != "foo" {
+		t.Fatalf("EnsureCorrelationID(): %s", id2)
+	}
+	if _, id = EnsureCorrelationID(nil, nil); len(id) != 36 {
+		t.Fatalf("EnsureCorrelationID(): %s", id)
+	}
+	if got := GetCorrelationID(nil); got != "" {
+		t.Fatalf("GetCorrelationID(nil): %s", got)
+	}
+}
+
+func TestLogger_EnableCorrelationID(t *testing.T) {
+	o := New("test").SetOutput(io.Discard)
+
+	if o.EnableCorrelationID("") == nil {
+		t.Fatal("Logger.EnableCorrelationID(): nil")
+	}
+
+	var fields map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		fields = s.Fields()
+		return nil
+	})
+
+	o.WithContext(WithCorrelationID(context.Background(), "foo")).Info("test")
+	if len(fields) != 1 || fields[DefaultCorrelationIDField] != "foo" {
+		t.Fatalf("Logger.EnableCorrelationID(): %v", fields)
+	}
+	o.WithContext(context.Background()).Info("test")
+	if len(fields) != 0 {
+		t.Fatalf("Logger.EnableCorrelationID(): %v", fields)
+	}
+}
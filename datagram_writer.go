@@ -0,0 +1,121 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultDatagramSize is the default maximum datagram size of the datagram writer.
+// This is the maximum UDP payload size that does not need to be fragmented on Ethernet.
+const DefaultDatagramSize = 1472
+
+// NewDatagramWriter creates a writer that ensures each written datagram does not exceed the
+// given maximum size, this writer is usually used to wrap the UDP connections.
+// If the size parameter is less than or equal to 0, DefaultDatagramSize is used.
+// If the truncate parameter is true, the oversized logs will be truncated and the truncation
+// marker will be appended, otherwise they will be split into multiple datagrams, and each
+// datagram will be prefixed with a sequence marker like "#ID:INDEX/TOTAL " (for example,
+// "#12:1/3 "), the ID is unique within the writer, and the INDEX starts from 1.
+func NewDatagramWriter(w io.Writer, size int, truncate bool) io.Writer {
+	if size <= 0 {
+		size = DefaultDatagramSize
+	}
+	return &datagramWriter{w: w, size: size, truncate: truncate}
+}
+
+// This writer splits or truncates the oversized datagrams.
+type datagramWriter struct {
+	id       uint64
+	w        io.Writer
+	size     int
+	truncate bool
+}
+
+// Write is the implementation of io.Writer interface.
+// The returned number of bytes is relative to the given data.
+func (w *datagramWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.size {
+		return w.w.Write(p)
+	}
+	if w.truncate {
+		return w.writeTruncated(p)
+	}
+	return w.writeSplit(p)
+}
+
+// Writes the truncated datagram.
+func (w *datagramWriter) writeTruncated(p []byte) (int, error) {
+	var b []byte
+	if n := w.size - len(truncatedMarker); n > 0 {
+		b = append(append(make([]byte, 0, w.size), p[:n]...), truncatedMarker...)
+	} else {
+		b = p[:w.size]
+	}
+	if err := w.writeDatagram(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Writes the datagrams split from the given data.
+func (w *datagramWriter) writeSplit(p []byte) (int, error) {
+	id := strconv.FormatUint(atomic.AddUint64(&w.id, 1), 10)
+	// The length of the sequence marker depends on the number of datagrams, so we
+	// increase the number of datagrams until all the data can be carried.
+	total, chunk := 1, 0
+	for {
+		chunk = w.size - len(sequenceMarker(id, total, total))
+		if chunk <= 0 {
+			// The datagram size is too small to carry any data.
+			return w.writeTruncated(p)
+		}
+		if n := (len(p) + chunk - 1) / chunk; n > total {
+			total = n
+		} else {
+			break
+		}
+	}
+	var written int
+	for i := 1; len(p) > 0; i++ {
+		n := len(p)
+		if n > chunk {
+			n = chunk
+		}
+		b := append([]byte(sequenceMarker(id, i, total)), p[:n]...)
+		if err := w.writeDatagram(b); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Writes a single datagram.
+func (w *datagramWriter) writeDatagram(b []byte) error {
+	n, err := w.w.Write(b)
+	if err == nil && n != len(b) {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// Creates the sequence marker of the split datagram.
+func sequenceMarker(id string, index, total int) string {
+	return "#" + id + ":" + strconv.Itoa(index) + "/" + strconv.Itoa(total) + " "
+}
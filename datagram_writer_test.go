@@ -0,0 +1,79 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewDatagramWriter(t *testing.T) {
+	w := NewDatagramWriter(new(testChunkWriter), 0, false)
+	if w == nil {
+		t.Fatal("NewDatagramWriter(): return nil")
+	}
+	if got := w.(*datagramWriter).size; got != DefaultDatagramSize {
+		t.Fatalf("NewDatagramWriter(): size %d", got)
+	}
+}
+
+func TestDatagramWriter_Write(t *testing.T) {
+	cw := new(testChunkWriter)
+	w := NewDatagramWriter(cw, 12, false)
+
+	if n, err := w.Write([]byte("foo")); err != nil || n != 3 {
+		t.Fatalf("DatagramWriter.Write(): %d %v", n, err)
+	}
+	if n, err := w.Write([]byte("0123456789abcd")); err != nil || n != 14 {
+		t.Fatalf("DatagramWriter.Write(): %d %v", n, err)
+	}
+	want := "foo|#1:1/3 01234|#1:2/3 56789|#1:3/3 abcd"
+	if got := strings.Join(cw.chunks, "|"); got != want {
+		t.Fatalf("DatagramWriter.Write(): want %q, got %q", want, got)
+	}
+	for _, chunk := range cw.chunks {
+		if len(chunk) > 12 {
+			t.Fatalf("DatagramWriter.Write(): oversized datagram %q", chunk)
+		}
+	}
+}
+
+func TestDatagramWriter_WriteTruncate(t *testing.T) {
+	cw := new(testChunkWriter)
+	w := NewDatagramWriter(cw, 20, true)
+
+	if n, err := w.Write([]byte(strings.Repeat("x", 30))); err != nil || n != 30 {
+		t.Fatalf("DatagramWriter.Write(): %d %v", n, err)
+	}
+	if got, want := cw.chunks[0], "xxxxxx"+truncatedMarker; got != want {
+		t.Fatalf("DatagramWriter.Write(): want %q, got %q", want, got)
+	}
+
+	cw.chunks = nil
+	w = NewDatagramWriter(cw, 4, false)
+	if n, err := w.Write([]byte("0123456789")); err != nil || n != 10 {
+		t.Fatalf("DatagramWriter.Write(): %d %v", n, err)
+	}
+	if got := cw.chunks[0]; got != "0123" {
+		t.Fatalf("DatagramWriter.Write(): %q", got)
+	}
+}
+
+func TestDatagramWriter_WriteError(t *testing.T) {
+	w := NewDatagramWriter(testErrorWriter("test"), 12, false)
+	if n, err := w.Write([]byte("0123456789abcd")); err == nil || n != 0 {
+		t.Fatalf("DatagramWriter.Write(): %d %v", n, err)
+	}
+}
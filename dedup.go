@@ -0,0 +1,121 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// RepeatCountField is the field of the number of the identical consecutive logs collapsed
+// by the log deduplicator.
+const RepeatCountField = "repeat_count"
+
+// The logDeduplicator type is the built-in log deduplicator, which collapses the identical
+// consecutive logs (the logs with the same level and message) within a window.
+type logDeduplicator struct {
+	mu      sync.Mutex
+	window  time.Duration
+	key     samplerKey
+	active  bool
+	start   time.Time
+	gen     uint64
+	timer   *time.Timer
+	pending *dedupPending
+}
+
+// The dedupPending type is the collapsed logs that have not been recorded.
+type dedupPending struct {
+	log     *log
+	level   Level
+	message string
+	caller  string
+	count   uint64
+}
+
+// Creates a new log deduplicator with the given window.
+func newLogDeduplicator(window time.Duration) *logDeduplicator {
+	return &logDeduplicator{window: window}
+}
+
+// Determines whether the given log should be recorded. The returned pending logs (if any)
+// are the collapsed logs of the previous run, which must be recorded before the given log.
+func (d *logDeduplicator) check(l *log, level Level, message string, e *logEntity) (bool, *dedupPending) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := samplerKey{level: level, message: e.message}
+	if d.active && d.key == key && e.time.Sub(d.start) < d.window {
+		if d.pending == nil {
+			d.pending = &dedupPending{level: level, message: message}
+			gen := d.gen
+			d.timer = time.AfterFunc(d.window-e.time.Sub(d.start), func() { d.expire(gen) })
+		}
+		d.pending.log, d.pending.caller = l, e.caller
+		d.pending.count++
+		return false, nil
+	}
+	p := d.take()
+	d.key, d.start, d.active = key, e.time, true
+	return true, p
+}
+
+// Ends the current run and returns the collapsed logs of it.
+// This method must be called with the lock held.
+func (d *logDeduplicator) take() *dedupPending {
+	p := d.pending
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.pending, d.active = nil, false
+	d.gen++
+	return p
+}
+
+// Records the collapsed logs of the run of the given generation when its window ends.
+func (d *logDeduplicator) expire(gen uint64) {
+	d.mu.Lock()
+	var p *dedupPending
+	if d.gen == gen {
+		p = d.take()
+	}
+	d.mu.Unlock()
+	p.record()
+}
+
+// Ends the current run and records the collapsed logs of it.
+func (d *logDeduplicator) flush() {
+	d.mu.Lock()
+	p := d.take()
+	d.mu.Unlock()
+	p.record()
+}
+
+// Records the collapsed logs with the RepeatCountField field.
+func (p *dedupPending) record() {
+	if p == nil {
+		return
+	}
+	l := p.log
+	entity := l.core.getEntity(l, p.level, l.prefix+p.message, p.caller)
+	defer l.core.putEntity(entity)
+
+	entity.fields = internal.Fields(entity.fields).Clone(1)
+	entity.fields[RepeatCountField] = p.count
+	_ = l.emit(entity, p.message, false)
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetDeduplication(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	now := time.Now()
+	o.SetNowFunc(func() time.Time { return now })
+	var messages []string
+	var fields []map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		messages = append(messages, s.Message())
+		fields = append(fields, s.Fields())
+		return nil
+	})
+
+	if o.SetDeduplication(time.Hour) == nil {
+		t.Fatal("Logger.SetDeduplication(): nil")
+	}
+	for i := 0; i < 4; i++ {
+		o.WithField("i", i).Error("test")
+	}
+	if len(messages) != 1 || fields[0]["i"] != 0 {
+		t.Fatalf("Logger.SetDeduplication(): %v %v", messages, fields)
+	}
+	// The run is broken by another log.
+	o.Warn("test")
+	if len(messages) != 3 || messages[1] != "test" || messages[2] != "test" {
+		t.Fatalf("Logger.SetDeduplication(): %v", messages)
+	}
+	if fields[1][RepeatCountField] != uint64(3) || fields[1]["i"] != 3 || len(fields[2]) != 0 {
+		t.Fatalf("Logger.SetDeduplication(): %v", fields)
+	}
+
+	// The run is broken by the end of the window.
+	o.Warn("test")
+	now = now.Add(time.Hour)
+	o.Warn("test")
+	if len(messages) != 5 || fields[3][RepeatCountField] != uint64(1) || len(fields[4]) != 0 {
+		t.Fatalf("Logger.SetDeduplication(): %v %v", messages, fields)
+	}
+
+	// The FatalLevel and PanicLevel logs are never collapsed.
+	o.SetExitFunc(nil)
+	o.Warn("test")
+	o.Fatal("test")
+	o.Fatal("test")
+	if len(messages) != 8 || fields[5][RepeatCountField] != uint64(1) {
+		t.Fatalf("Logger.SetDeduplication(): %v %v", messages, fields)
+	}
+
+	o.Error("test")
+	o.SetDeduplication(0)
+	for i := 0; i < 3; i++ {
+		o.Error("test")
+	}
+	if len(messages) != 12 || len(fields[11]) != 0 {
+		t.Fatalf("Logger.SetDeduplication(): %v %v", messages, fields)
+	}
+}
+
+func TestLogger_SetDeduplication_Expire(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	var mu sync.Mutex
+	var counts []interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		mu.Lock()
+		counts = append(counts, s.Fields()[RepeatCountField])
+		mu.Unlock()
+		return nil
+	})
+
+	o.SetDeduplication(time.Millisecond * 20)
+	for i := 0; i < 3; i++ {
+		o.Error("test")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(counts)
+		mu.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(counts) != 2 || counts[0] != nil || counts[1] != uint64(2) {
+		t.Fatalf("Logger.SetDeduplication(): %v", counts)
+	}
+}
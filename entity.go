@@ -64,6 +64,22 @@ type Entity interface {
 	// Returns nil if not enabled.
 	Stack() []string
 
+	// PID returns the id of the current process.
+	// If the process metadata is not enabled, 0 is always returned.
+	PID() int
+
+	// Hostname returns the host name of the current process.
+	// If the process metadata is not enabled, an empty string is always returned.
+	Hostname() string
+
+	// Sequence returns the sequence number of the log in the logger, starting from 1.
+	// If the process metadata is not enabled, 0 is always returned.
+	Sequence() uint64
+
+	// ID returns the unique id of the log.
+	// If the record id is not enabled, an empty string is always returned.
+	ID() string
+
 	// Buffer returns the entity buffer instance.
 	Buffer() *bytes.Buffer
 }
@@ -104,6 +120,11 @@ type logEntity struct {
 	buffer     bytes.Buffer
 	caller     string
 	stack      []string
+	pid        int
+	hostname   string
+	sequence   uint64
+	id         string
+	fanOut     []*fanOutRecord
 }
 
 // Name returns the logger name.
@@ -179,6 +200,30 @@ func (o *logEntity) Stack() []string {
 	return o.stack
 }
 
+// PID returns the id of the current process.
+// If the process metadata is not enabled, 0 is always returned.
+func (o *logEntity) PID() int {
+	return o.pid
+}
+
+// Hostname returns the host name of the current process.
+// If the process metadata is not enabled, an empty string is always returned.
+func (o *logEntity) Hostname() string {
+	return o.hostname
+}
+
+// Sequence returns the sequence number of the log in the logger, starting from 1.
+// If the process metadata is not enabled, 0 is always returned.
+func (o *logEntity) Sequence() uint64 {
+	return o.sequence
+}
+
+// ID returns the unique id of the log.
+// If the record id is not enabled, an empty string is always returned.
+func (o *logEntity) ID() string {
+	return o.id
+}
+
 // Buffer returns the entity buffer instance.
 func (o *logEntity) Buffer() *bytes.Buffer {
 	return &o.buffer
@@ -242,6 +287,10 @@ func (o *logEntity) CloneWithContext(ctx context.Context) Summary {
 		buffer:     *buffer,
 		caller:     o.caller,
 		stack:      stack,
+		pid:        o.pid,
+		hostname:   o.hostname,
+		sequence:   o.sequence,
+		id:         o.id,
 	}
 }
 
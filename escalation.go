@@ -0,0 +1,159 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// LevelEscalatorOptions defines the options of the level escalator.
+type LevelEscalatorOptions struct {
+	// Threshold is the number of error logs within the window that triggers the escalation.
+	// If it is less than or equal to 0, 10 is used.
+	Threshold int
+
+	// Window is the time window for counting the error logs.
+	// If it is less than or equal to 0, one minute is used.
+	Window time.Duration
+
+	// Level is the escalated logger level, by default, DebugLevel is used.
+	Level Level
+
+	// Duration is the duration of the escalation, the escalation is extended when the errors
+	// continue to burst. If it is less than or equal to 0, two minutes is used.
+	Duration time.Duration
+}
+
+// LevelEscalator interface defines the controller that observes the error logs and
+// temporarily lowers the logger level after an error burst, so that the diagnostic logs
+// are captured exactly when they are needed. The escalator is a log hook, and it must
+// be added to the observed logger:
+//
+//	l.AddHook(NewLevelEscalator(l, LevelEscalatorOptions{}))
+type LevelEscalator interface {
+	Hook
+
+	// Escalated determines whether the logger level is escalated.
+	Escalated() bool
+
+	// Stop stops the current escalation and restores the logger level immediately.
+	Stop()
+}
+
+// NewLevelEscalator creates and returns a level escalator for the given logger.
+func NewLevelEscalator(l Logger, opts LevelEscalatorOptions) LevelEscalator {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 10
+	}
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if !opts.Level.IsValid() {
+		opts.Level = DebugLevel
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = time.Minute * 2
+	}
+	return &levelEscalator{logger: l, opts: opts}
+}
+
+// The built-in level escalator.
+type levelEscalator struct {
+	mu        sync.Mutex
+	logger    Logger
+	opts      LevelEscalatorOptions
+	errors    []time.Time // The times of the error logs within the window.
+	timer     *time.Timer // The restoration timer of the current escalation.
+	gen       uint64      // The generation of the restoration timer.
+	escalated bool
+	restored  Level // The logger level before the escalation.
+}
+
+// Levels is an implementation of the Hook interface.
+func (e *levelEscalator) Levels() []Level {
+	return []Level{PanicLevel, FatalLevel, ErrorLevel}
+}
+
+// Fire is an implementation of the Hook interface.
+func (e *levelEscalator) Fire(s Summary) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	for len(e.errors) > 0 && now.Sub(e.errors[0]) > e.opts.Window {
+		e.errors = e.errors[1:]
+	}
+	if e.errors = append(e.errors, now); len(e.errors) < e.opts.Threshold {
+		return nil
+	}
+	e.errors = e.errors[:0]
+	if !e.escalated {
+		e.restored = e.logger.GetLevel()
+		if e.restored >= e.opts.Level {
+			// The logger level already includes the escalated level.
+			return nil
+		}
+		e.escalated = true
+		e.logger.SetLevel(e.opts.Level)
+	}
+	// Starts or extends the current escalation.
+	e.startTimer()
+	return nil
+}
+
+// Escalated determines whether the logger level is escalated.
+func (e *levelEscalator) Escalated() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.escalated
+}
+
+// Stop stops the current escalation and restores the logger level immediately.
+func (e *levelEscalator) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.restore()
+}
+
+// Starts the restoration timer of the current escalation, the previous timer is discarded.
+func (e *levelEscalator) startTimer() {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	e.gen++
+	gen := e.gen
+	e.timer = time.AfterFunc(e.opts.Duration, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		// The discarded timers may still fire.
+		if e.gen == gen {
+			e.restore()
+		}
+	})
+}
+
+// Restores the logger level of the current escalation.
+func (e *levelEscalator) restore() {
+	if !e.escalated {
+		return
+	}
+	e.timer.Stop()
+	e.timer, e.escalated = nil, false
+	e.gen++
+	// The logger level changed by others during the escalation is not restored.
+	if e.logger.GetLevel() == e.opts.Level {
+		e.logger.SetLevel(e.restored)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNewLevelEscalator(t *testing.T) {
+	l := New("test").SetOutput(new(bytes.Buffer)).SetLevel(InfoLevel)
+	e := NewLevelEscalator(l, LevelEscalatorOptions{Threshold: 3, Duration: time.Millisecond * 50})
+	l.AddHook(e)
+
+	l.Error("test")
+	l.Error("test")
+	if e.Escalated() || l.GetLevel() != InfoLevel {
+		t.Fatalf("LevelEscalator: escalated before threshold, level %s", l.GetLevel())
+	}
+	l.Error("test")
+	if !e.Escalated() || l.GetLevel() != DebugLevel {
+		t.Fatalf("LevelEscalator: not escalated, level %s", l.GetLevel())
+	}
+	for i := 0; i < 100 && e.Escalated(); i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	if e.Escalated() || l.GetLevel() != InfoLevel {
+		t.Fatalf("LevelEscalator: not restored, level %s", l.GetLevel())
+	}
+}
+
+func TestLevelEscalator_Stop(t *testing.T) {
+	l := New("test").SetOutput(new(bytes.Buffer)).SetLevel(WarnLevel)
+	e := NewLevelEscalator(l, LevelEscalatorOptions{Threshold: 1, Level: TraceLevel})
+	l.AddHook(e)
+
+	l.Error("test")
+	if !e.Escalated() || l.GetLevel() != TraceLevel {
+		t.Fatalf("LevelEscalator: not escalated, level %s", l.GetLevel())
+	}
+	// The escalation is extended.
+	l.Error("test")
+	e.Stop()
+	if e.Escalated() || l.GetLevel() != WarnLevel {
+		t.Fatalf("LevelEscalator.Stop(): not restored, level %s", l.GetLevel())
+	}
+	e.Stop()
+
+	// The logger level changed during the escalation is kept.
+	l.Error("test")
+	l.SetLevel(ErrorLevel)
+	e.Stop()
+	if l.GetLevel() != ErrorLevel {
+		t.Fatalf("LevelEscalator.Stop(): level %s", l.GetLevel())
+	}
+}
+
+func TestLevelEscalator_Window(t *testing.T) {
+	l := New("test").SetOutput(new(bytes.Buffer)).SetLevel(InfoLevel)
+	e := NewLevelEscalator(l, LevelEscalatorOptions{Threshold: 2, Window: time.Millisecond})
+	l.AddHook(e)
+
+	l.Error("test")
+	time.Sleep(time.Millisecond * 5)
+	l.Error("test")
+	if e.Escalated() {
+		t.Fatal("LevelEscalator: escalated by errors outside the window")
+	}
+
+	// The logger level already includes the escalated level.
+	l.SetLevel(TraceLevel)
+	l.Error("test")
+	l.Error("test")
+	if e.Escalated() {
+		t.Fatal("LevelEscalator: escalated without lowering the level")
+	}
+	if got := e.Levels(); len(got) != 3 {
+		t.Fatalf("LevelEscalator.Levels(): %v", got)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// The default log field names of the registered events.
+const (
+	DefaultEventField     = "event"
+	DefaultEventCodeField = "event_code"
+)
+
+// EventDefinition defines a named business event.
+type EventDefinition struct {
+	// Name is the unique name of the event, such as "user.login".
+	Name string
+
+	// Code is the stable and unique code of the event, such as "AUTH-0001".
+	// If it is empty, the event code field is not added to the log.
+	Code string
+
+	// Fields is the default log fields of the event.
+	Fields map[string]interface{}
+}
+
+// EventCatalog interface defines the catalog of the named business events, so that the
+// critical events are logged consistently and are greppable across services:
+//
+//	c := NewEventCatalog()
+//	c.MustRegister(EventDefinition{Name: "user.login", Code: "AUTH-0001"})
+//	l.SetEventCatalog(c)
+//	l.Event("user.login").WithField("uid", 1).Info("User logged in")
+type EventCatalog interface {
+	// Register registers the given event definition.
+	// An error is returned when the name of the event is empty, or the name or the code
+	// of the event is already registered.
+	Register(EventDefinition) error
+
+	// MustRegister is like Register, but triggers a panic when an error occurs.
+	MustRegister(EventDefinition)
+
+	// Lookup returns the event definition of the given name.
+	Lookup(string) (EventDefinition, bool)
+}
+
+// NewEventCatalog creates and returns a new event catalog.
+func NewEventCatalog() EventCatalog {
+	return &eventCatalog{events: make(map[string]EventDefinition), codes: make(map[string]string)}
+}
+
+// The built-in event catalog.
+type eventCatalog struct {
+	mu     sync.RWMutex
+	events map[string]EventDefinition
+	codes  map[string]string // The event names indexed by the event codes.
+}
+
+// Register registers the given event definition.
+// An error is returned when the name of the event is empty, or the name or the code
+// of the event is already registered.
+func (c *eventCatalog) Register(e EventDefinition) error {
+	if e.Name == "" {
+		return errors.New("empty event name")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.events[e.Name]; found {
+		return fmt.Errorf("duplicate event name %q", e.Name)
+	}
+	if e.Code != "" {
+		if name, found := c.codes[e.Code]; found {
+			return fmt.Errorf("duplicate event code %q of event %q and %q", e.Code, name, e.Name)
+		}
+		c.codes[e.Code] = e.Name
+	}
+	if len(e.Fields) > 0 {
+		fields := make(map[string]interface{}, len(e.Fields))
+		for k, v := range e.Fields {
+			fields[k] = v
+		}
+		e.Fields = fields
+	}
+	c.events[e.Name] = e
+	return nil
+}
+
+// MustRegister is like Register, but triggers a panic when an error occurs.
+func (c *eventCatalog) MustRegister(e EventDefinition) {
+	if err := c.Register(e); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the event definition of the given name.
+func (c *eventCatalog) Lookup(name string) (EventDefinition, bool) {
+	c.mu.RLock()
+	e, found := c.events[name]
+	c.mu.RUnlock()
+	return e, found
+}
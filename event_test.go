@@ -0,0 +1,117 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestNewEventCatalog(t *testing.T) {
+	if NewEventCatalog() == nil {
+		t.Fatal("NewEventCatalog(): nil")
+	}
+}
+
+func TestEventCatalog_Register(t *testing.T) {
+	c := NewEventCatalog()
+	fields := map[string]interface{}{"foo": 1}
+
+	if err := c.Register(EventDefinition{Name: "a", Code: "A", Fields: fields}); err != nil {
+		t.Fatalf("EventCatalog.Register(): error %s", err)
+	}
+	if err := c.Register(EventDefinition{Name: "b"}); err != nil {
+		t.Fatalf("EventCatalog.Register(): error %s", err)
+	}
+	if err := c.Register(EventDefinition{Name: "c"}); err != nil {
+		t.Fatalf("EventCatalog.Register(): error %s", err)
+	}
+	for _, e := range []EventDefinition{{}, {Name: "a"}, {Name: "d", Code: "A"}} {
+		if err := c.Register(e); err == nil {
+			t.Fatalf("EventCatalog.Register(): nil error for %v", e)
+		}
+	}
+
+	// The default fields are copied.
+	fields["foo"] = 2
+	if e, found := c.Lookup("a"); !found {
+		t.Fatal("EventCatalog.Lookup(): not found")
+	} else {
+		if e.Code != "A" || e.Fields["foo"] != 1 {
+			t.Fatalf("EventCatalog.Lookup(): %v", e)
+		}
+	}
+	if _, found := c.Lookup("d"); found {
+		t.Fatal("EventCatalog.Lookup(): found")
+	}
+}
+
+func TestEventCatalog_MustRegister(t *testing.T) {
+	c := NewEventCatalog()
+	c.MustRegister(EventDefinition{Name: "a"})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("EventCatalog.MustRegister(): no panic")
+		}
+	}()
+
+	c.MustRegister(EventDefinition{Name: "a"})
+}
+
+func TestLog_Event(t *testing.T) {
+	errBuf := new(bytes.Buffer)
+	internal.ErrorWriter = errBuf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	c := NewEventCatalog()
+	c.MustRegister(EventDefinition{Name: "user.login", Code: "AUTH-0001", Fields: map[string]interface{}{"foo": 1}})
+
+	l := New("test")
+	buf := new(bytes.Buffer)
+	l.SetOutput(buf)
+	l.SetDefaultTimeFormat("test")
+
+	l.Event("user.login").Info("test")
+	if errBuf.Len() == 0 {
+		t.Fatal("Log.Event(): no error reported")
+	}
+	want := `{"fields":{"event":"user.login"},"level":"info","message":"test","name":"test","time":"test"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("Log.Event(): want %q, got %q", want, got)
+	}
+
+	l.SetEventCatalog(c)
+	buf.Reset()
+	errBuf.Reset()
+	l.Event("user.login").WithField("foo", 2).Info("test")
+	if errBuf.Len() != 0 {
+		t.Fatalf("Log.Event(): %q", errBuf.String())
+	}
+	want = `{"fields":{"event":"user.login","event_code":"AUTH-0001","foo":2},"level":"info","message":"test","name":"test","time":"test"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("Log.Event(): want %q, got %q", want, got)
+	}
+
+	buf.Reset()
+	l.Event("user.logout").Info("test")
+	if !strings.Contains(errBuf.String(), `Unknown log event "user.logout"`) {
+		t.Fatalf("Log.Event(): %q", errBuf.String())
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// FakeEntry is a log recorded by the fake logger.
+type FakeEntry struct {
+	Level   Level
+	Message string
+	Fields  map[string]interface{}
+	Caller  string
+	Stack   []string
+}
+
+// FakeLogger is a test double of the Logger interface, which records the logs in memory
+// instead of formatting and writing them, so that the code depending on Logger can be
+// unit-tested without wiring writers and formatters. The exit function of the fake logger
+// is disabled, so the FatalLevel logs do not exit the test process.
+type FakeLogger struct {
+	Logger
+
+	mu      sync.Mutex
+	entries []FakeEntry
+}
+
+// NewFakeLogger creates and returns a new fake logger with the given name.
+func NewFakeLogger(name string) *FakeLogger {
+	f := new(FakeLogger)
+	f.Logger = New(name).
+		SetFormatter(new(UnimplementedFormatter)).
+		SetOutputInterceptor(f.intercept).
+		SetExitFunc(nil)
+	return f
+}
+
+// Records the given log summary.
+func (f *FakeLogger) intercept(s Summary, _ io.Writer) (int, error) {
+	e := FakeEntry{Level: s.Level(), Message: s.Message(), Caller: s.Caller(), Stack: s.Stack()}
+	if fields := s.Fields(); len(fields) > 0 {
+		e.Fields = make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			e.Fields[k] = v
+		}
+	}
+	f.mu.Lock()
+	f.entries = append(f.entries, e)
+	f.mu.Unlock()
+	return 0, nil
+}
+
+// Entries returns a copy of the recorded logs in the order of recording.
+func (f *FakeLogger) Entries() []FakeEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeEntry(nil), f.entries...)
+}
+
+// EntriesAt returns the recorded logs of the given level in the order of recording.
+func (f *FakeLogger) EntriesAt(level Level) []FakeEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var r []FakeEntry
+	for i, j := 0, len(f.entries); i < j; i++ {
+		if f.entries[i].Level == level {
+			r = append(r, f.entries[i])
+		}
+	}
+	return r
+}
+
+// Contains determines whether a log with the given level and message has been recorded.
+func (f *FakeLogger) Contains(level Level, message string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, j := 0, len(f.entries); i < j; i++ {
+		if f.entries[i].Level == level && f.entries[i].Message == message {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of the recorded logs.
+func (f *FakeLogger) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.entries)
+}
+
+// Reset discards all the recorded logs.
+func (f *FakeLogger) Reset() {
+	f.mu.Lock()
+	f.entries = nil
+	f.mu.Unlock()
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"testing"
+)
+
+func TestNewFakeLogger(t *testing.T) {
+	var l Logger = NewFakeLogger("test")
+	if l == nil {
+		t.Fatal("NewFakeLogger(): nil")
+	}
+	if got := l.Name(); got != "test" {
+		t.Fatalf("FakeLogger.Name(): %s", got)
+	}
+}
+
+func TestFakeLogger(t *testing.T) {
+	l := NewFakeLogger("test")
+	l.SetLevel(DebugLevel)
+
+	l.Info("foo")
+	l.WithField("a", 1).Error("bar")
+	l.Trace("baz")
+	l.Fatal("fatal")
+
+	if got := l.Len(); got != 3 {
+		t.Fatalf("FakeLogger.Len(): %d", got)
+	}
+	entries := l.Entries()
+	if entries[0].Level != InfoLevel || entries[0].Message != "foo" || entries[0].Fields != nil {
+		t.Fatalf("FakeLogger.Entries(): %v", entries[0])
+	}
+	if entries[1].Level != ErrorLevel || entries[1].Message != "bar" || entries[1].Fields["a"] != 1 {
+		t.Fatalf("FakeLogger.Entries(): %v", entries[1])
+	}
+	if got := l.EntriesAt(FatalLevel); len(got) != 1 || got[0].Message != "fatal" {
+		t.Fatalf("FakeLogger.EntriesAt(): %v", got)
+	}
+	if got := l.EntriesAt(WarnLevel); len(got) != 0 {
+		t.Fatalf("FakeLogger.EntriesAt(): %v", got)
+	}
+	if !l.Contains(ErrorLevel, "bar") {
+		t.Fatal("FakeLogger.Contains(): false")
+	}
+	if l.Contains(InfoLevel, "bar") {
+		t.Fatal("FakeLogger.Contains(): true")
+	}
+
+	l.Reset()
+	if got := l.Len(); got != 0 {
+		t.Fatalf("FakeLogger.Reset(): %d", got)
+	}
+}
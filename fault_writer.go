@@ -0,0 +1,129 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is the default error returned by the fault writer.
+var ErrInjectedFault = errors.New("injected write fault")
+
+// FaultWriterOptions defines the faults injected by the fault writer.
+type FaultWriterOptions struct {
+	// FailEvery indicates that every Nth write fails without writing any data.
+	// If it is less than or equal to 0, the writes never fail.
+	FailEvery int
+
+	// Err is the error returned by the failed writes, by default, ErrInjectedFault is used.
+	Err error
+
+	// ShortWriteEvery indicates that every Nth write only writes the first half of the data
+	// and returns io.ErrShortWrite. If it is less than or equal to 0, the writes are never short.
+	// When a write both fails and is short, it fails.
+	ShortWriteEvery int
+
+	// Latency is the latency added to every write.
+	Latency time.Duration
+}
+
+// FaultWriter interface defines the writer wrapper that injects the programmed faults into
+// the writes, which is used to test the behavior of the applications and the retry, failover
+// and async subsystems under logging failures.
+type FaultWriter interface {
+	io.Writer
+
+	// SetOptions replaces the faults injected by the writer.
+	// The write counter used by FailEvery and ShortWriteEvery is reset.
+	SetOptions(FaultWriterOptions)
+
+	// Writes returns the number of the writes received by the writer.
+	Writes() int
+
+	// Faults returns the number of the failed and short writes.
+	Faults() int
+}
+
+// NewFaultWriter creates and returns a fault writer wrapping the given writer.
+func NewFaultWriter(w io.Writer, opts FaultWriterOptions) FaultWriter {
+	return &faultWriter{w: w, opts: opts}
+}
+
+// The built-in fault writer.
+type faultWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	opts    FaultWriterOptions
+	counter int // The write counter of the current options.
+	writes  int
+	faults  int
+}
+
+// SetOptions replaces the faults injected by the writer.
+// The write counter used by FailEvery and ShortWriteEvery is reset.
+func (w *faultWriter) SetOptions(opts FaultWriterOptions) {
+	w.mu.Lock()
+	w.opts, w.counter = opts, 0
+	w.mu.Unlock()
+}
+
+// Writes returns the number of the writes received by the writer.
+func (w *faultWriter) Writes() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writes
+}
+
+// Faults returns the number of the failed and short writes.
+func (w *faultWriter) Faults() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.faults
+}
+
+// Write is the implementation of io.Writer interface.
+func (w *faultWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	opts := w.opts
+	w.counter++
+	w.writes++
+	fail := opts.FailEvery > 0 && w.counter%opts.FailEvery == 0
+	short := !fail && opts.ShortWriteEvery > 0 && w.counter%opts.ShortWriteEvery == 0
+	if fail || short {
+		w.faults++
+	}
+	w.mu.Unlock()
+
+	if opts.Latency > 0 {
+		time.Sleep(opts.Latency)
+	}
+	if fail {
+		if opts.Err != nil {
+			return 0, opts.Err
+		}
+		return 0, ErrInjectedFault
+	}
+	if short {
+		n, err := w.w.Write(p[:len(p)/2])
+		if err != nil {
+			return n, err
+		}
+		return n, io.ErrShortWrite
+	}
+	return w.w.Write(p)
+}
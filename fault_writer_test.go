@@ -0,0 +1,81 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestNewFaultWriter(t *testing.T) {
+	if NewFaultWriter(new(bytes.Buffer), FaultWriterOptions{}) == nil {
+		t.Fatal("NewFaultWriter(): nil")
+	}
+}
+
+func TestFaultWriter_Write(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewFaultWriter(buf, FaultWriterOptions{FailEvery: 3, ShortWriteEvery: 2})
+
+	items := []struct {
+		N   int
+		Err error
+	}{
+		{4, nil},
+		{2, io.ErrShortWrite},
+		{0, ErrInjectedFault},
+		{2, io.ErrShortWrite},
+		{4, nil},
+		{0, ErrInjectedFault},
+	}
+
+	for i, item := range items {
+		n, err := w.Write([]byte("test"))
+		if n != item.N || err != item.Err {
+			t.Fatalf("FaultWriter.Write(): %d: want %d %v, got %d %v", i, item.N, item.Err, n, err)
+		}
+	}
+	if got := buf.String(); got != "testtetetest" {
+		t.Fatalf("FaultWriter.Write(): %q", got)
+	}
+	if w.Writes() != 6 || w.Faults() != 4 {
+		t.Fatalf("FaultWriter.Writes/Faults(): %d %d", w.Writes(), w.Faults())
+	}
+
+	e := errors.New("test")
+	w.SetOptions(FaultWriterOptions{FailEvery: 1, Err: e})
+	if n, err := w.Write([]byte("test")); n != 0 || err != e {
+		t.Fatalf("FaultWriter.Write(): %d %v", n, err)
+	}
+
+	w.SetOptions(FaultWriterOptions{Latency: time.Millisecond * 10})
+	start := time.Now()
+	if n, err := w.Write([]byte("test")); n != 4 || err != nil {
+		t.Fatalf("FaultWriter.Write(): %d %v", n, err)
+	}
+	if d := time.Since(start); d < time.Millisecond*10 {
+		t.Fatalf("FaultWriter.Write(): latency %s", d)
+	}
+}
+
+func TestFaultWriter_Write_WithErrorWriter(t *testing.T) {
+	w := NewFaultWriter(testErrorWriter("test"), FaultWriterOptions{ShortWriteEvery: 1})
+	if _, err := w.Write([]byte("test")); err == nil || err == io.ErrShortWrite {
+		t.Fatalf("FaultWriter.Write(): %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"math"
+	"time"
+)
+
+// The fieldKind type defines the kind of the typed log field value.
+type fieldKind uint8
+
+const (
+	fieldAny fieldKind = iota
+	fieldInt
+	fieldInt64
+	fieldUint64
+	fieldFloat64
+	fieldString
+	fieldBool
+	fieldDuration
+)
+
+// Field defines a strongly-typed log field, it is created by the typed field constructors
+// such as Int, String, Bool and Duration, and added to the log by Log.WithTypedFields.
+// The typed fields keep the values unboxed until they are added to the log, so the hot
+// paths can build the fields without the interface allocations.
+type Field struct {
+	Key  string
+	kind fieldKind
+	num  uint64
+	str  string
+	any  interface{}
+}
+
+// Int creates and returns a typed log field with the given int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: fieldInt, num: uint64(value)}
+}
+
+// Int64 creates and returns a typed log field with the given int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: fieldInt64, num: uint64(value)}
+}
+
+// Uint64 creates and returns a typed log field with the given uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: fieldUint64, num: value}
+}
+
+// Float64 creates and returns a typed log field with the given float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: fieldFloat64, num: math.Float64bits(value)}
+}
+
+// String creates and returns a typed log field with the given string value.
+func String(key string, value string) Field {
+	return Field{Key: key, kind: fieldString, str: value}
+}
+
+// Bool creates and returns a typed log field with the given bool value.
+func Bool(key string, value bool) Field {
+	if value {
+		return Field{Key: key, kind: fieldBool, num: 1}
+	}
+	return Field{Key: key, kind: fieldBool}
+}
+
+// Duration creates and returns a typed log field with the given time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, kind: fieldDuration, num: uint64(value)}
+}
+
+// Any creates and returns a log field with the given value of any type.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, kind: fieldAny, any: value}
+}
+
+// Value returns the value of the current field, the value has the same type as the value
+// given to the field constructor.
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case fieldInt:
+		return int(f.num)
+	case fieldInt64:
+		return int64(f.num)
+	case fieldUint64:
+		return f.num
+	case fieldFloat64:
+		return math.Float64frombits(f.num)
+	case fieldString:
+		return f.str
+	case fieldBool:
+		return f.num == 1
+	case fieldDuration:
+		return time.Duration(f.num)
+	}
+	return f.any
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestField_Value(t *testing.T) {
+	err := errors.New("test")
+	items := []struct {
+		Field Field
+		Want  interface{}
+	}{
+		{Int("k", -1), -1},
+		{Int64("k", -2), int64(-2)},
+		{Uint64("k", 3), uint64(3)},
+		{Float64("k", 1.5), 1.5},
+		{String("k", "v"), "v"},
+		{Bool("k", true), true},
+		{Bool("k", false), false},
+		{Duration("k", time.Second), time.Second},
+		{Any("k", err), err},
+		{Any("k", nil), nil},
+	}
+	for _, item := range items {
+		if item.Field.Key != "k" {
+			t.Fatalf("Field.Key: %q", item.Field.Key)
+		}
+		if got := item.Field.Value(); got != item.Want {
+			t.Fatalf("Field.Value(): want %T(%v), got %T(%v)", item.Want, item.Want, got, got)
+		}
+	}
+}
+
+func TestLogger_WithTypedFields(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+
+	var fields map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		fields = s.Fields()
+		return nil
+	})
+
+	if o.WithTypedFields() == nil {
+		t.Fatal("Logger.WithTypedFields(): nil")
+	}
+	o.WithTypedFields(Int("a", 1), String("b", "b")).Info("test")
+	if len(fields) != 2 || fields["a"] != 1 || fields["b"] != "b" {
+		t.Fatalf("Logger.WithTypedFields(): %v", fields)
+	}
+
+	o.WithField("a", "a").WithField("c", "c").WithTypedFields(Bool("a", true), Duration("d", time.Second)).Info("test")
+	if len(fields) != 3 || fields["a"] != true || fields["c"] != "c" || fields["d"] != time.Second {
+		t.Fatalf("Logger.WithTypedFields(): %v", fields)
+	}
+}
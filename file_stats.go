@@ -0,0 +1,55 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FileStats defines the runtime statistics of the log file writer, which can be used by
+// health checks to verify the logging pipeline end-to-end.
+type FileStats struct {
+	// Size is the size of the current log file.
+	Size int64 `json:"size"`
+
+	// Written is the total number of bytes written.
+	Written uint64 `json:"written"`
+
+	// Rotations is the total number of rotations.
+	Rotations uint64 `json:"rotations"`
+
+	// Backups is the number of existing backup files.
+	Backups int `json:"backups"`
+
+	// LastError is the message of the last error, it is empty if no error has occurred.
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorTime is the time of the last error, it is zero if no error has occurred.
+	LastErrorTime time.Time `json:"last_error_time"`
+}
+
+// String returns the JSON string of the current statistics.
+func (s FileStats) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// FileStatsProvider interface defines the writer that provides the log file statistics.
+// The writers created by NewFileWriter implement this interface.
+type FileStatsProvider interface {
+	// Stats returns the current log file statistics.
+	Stats() FileStats
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStats_String(t *testing.T) {
+	s := FileStats{Size: 1, Written: 2, Rotations: 3, Backups: 4, LastError: "test"}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s.String()), &m); err != nil {
+		t.Fatalf("FileStats.String(): %s", err)
+	}
+	if m["size"] != float64(1) || m["written"] != float64(2) || m["rotations"] != float64(3) ||
+		m["backups"] != float64(4) || m["last_error"] != "test" {
+		t.Fatalf("FileStats.String(): %v", m)
+	}
+}
+
+func TestFileWriter_Stats(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	w := MustNewFileWriter(name, 10, 10)
+	defer func() { _ = w.Close() }()
+
+	p, ok := w.(FileStatsProvider)
+	if !ok {
+		t.Fatal("NewFileWriter(): not FileStatsProvider")
+	}
+	if s := p.Stats(); s.Size != 0 || s.Written != 0 || s.Rotations != 0 || s.Backups != 0 || s.LastError != "" {
+		t.Fatalf("FileWriter.Stats(): %+v", s)
+	}
+	_, _ = w.Write([]byte("test\n"))
+	if s := p.Stats(); s.Size != 5 || s.Written != 5 || s.Rotations != 0 {
+		t.Fatalf("FileWriter.Stats(): %+v", s)
+	}
+	_, _ = w.Write([]byte("test\n"))
+	if s := p.Stats(); s.Size != 0 || s.Written != 10 || s.Rotations != 1 || s.Backups != 1 {
+		t.Fatalf("FileWriter.Stats(): %+v", s)
+	}
+
+	// Replace the log directory with a file, so the log file cannot be opened.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, filePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(dir) }()
+	if _, err := w.Write([]byte("test\n")); err == nil {
+		t.Fatal("FileWriter.Write(): nil error")
+	}
+	if s := p.Stats(); s.LastError == "" || s.LastErrorTime.IsZero() || s.Backups != 0 {
+		t.Fatalf("FileWriter.Stats(): %+v", s)
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +32,7 @@ const dirPerm os.FileMode = 0766
 const filePerm os.FileMode = 0666
 const fileFlag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
 const backupTimeFormat = "2006-01-02T15-04-05.000"
+const maxBackupNameAttempts = 100
 
 // NewFileWriter creates and returns an io.WriteCloser instance from the given path.
 // The max parameter is used to limit the maximum size of the log file, if it is 0, the
@@ -39,21 +42,163 @@ const backupTimeFormat = "2006-01-02T15-04-05.000"
 // The log file writer we returned does not restrict concurrent writing. If necessary,
 // you can use the writer wrapper with lock provided by us.
 func NewFileWriter(name string, max, backup uint32) (io.WriteCloser, error) {
+	return NewFileWriterWithOptions(name, FileWriterOptions{MaxSize: int64(max), MaxBackups: backup})
+}
+
+// MustNewFileWriter is like NewFileWriter, but triggers a panic when an error occurs.
+func MustNewFileWriter(name string, max, backup uint32) io.WriteCloser {
+	w, err := NewFileWriter(name, max, backup)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Rotator interface defines the writers that support manual log file rotation.
+type Rotator interface {
+	// Rotate renames the current log file to a backup file immediately.
+	Rotate() error
+}
+
+// Reopener interface defines the writers that support reopening the log file, which is
+// used to work with external log rotation tools, such as logrotate.
+type Reopener interface {
+	// Reopen closes and reopens the log file.
+	Reopen() error
+}
+
+// FileSyncPolicy defines when the log file writer synchronizes the log file to the disk.
+type FileSyncPolicy int
+
+const (
+	// FileSyncNever indicates that the log file is only synchronized when it is rotated or
+	// closed, and the durability depends on the page cache of the operating system.
+	FileSyncNever FileSyncPolicy = iota
+
+	// FileSyncInterval indicates that the log file is synchronized periodically at the
+	// interval given by FileWriterOptions.SyncInterval.
+	FileSyncInterval
+
+	// FileSyncEveryN indicates that the log file is synchronized after every N writes, and
+	// N is given by FileWriterOptions.SyncEvery.
+	FileSyncEveryN
+
+	// FileSyncAlways indicates that the log file is synchronized after every write.
+	FileSyncAlways
+)
+
+// FileWriterOptions defines the options of the log file writer.
+type FileWriterOptions struct {
+	// MaxSize is used to limit the maximum size of the log file in bytes, if it is less than
+	// or equal to 0, the file size limit will be disabled. The log files that exceed the
+	// maximum size limit will be renamed. Use ParseSize to parse human-readable sizes.
+	MaxSize int64
+
+	// MaxBackups limits the maximum number of backup log files retained.
+	// If the MaxAge is set and MaxBackups is 0, the number of backup files is not limited.
+	MaxBackups uint32
+
+	// MaxAge is the maximum age of the backup log files retained, the backup files whose
+	// modification time is older than the maximum age are removed, in addition to the
+	// MaxBackups limit. The backup files are checked when the writer is created and each
+	// time the log file is rotated. If it is less than or equal to 0, the age is not limited.
+	MaxAge time.Duration
+
+	// SyncPolicy determines when the log file is synchronized to the disk.
+	// The default policy is FileSyncNever.
+	SyncPolicy FileSyncPolicy
+
+	// SyncInterval is the synchronization interval of the FileSyncInterval policy, if it
+	// is less than or equal to 0, the FileSyncInterval policy is equivalent to FileSyncNever.
+	SyncInterval time.Duration
+
+	// SyncEvery is the number of writes of the FileSyncEveryN policy, if it is less than or
+	// equal to 1, the FileSyncEveryN policy is equivalent to FileSyncAlways.
+	SyncEvery int
+
+	// Chown indicates whether to change the owner of the log directory and log files
+	// created by the writer to UID and GID. This is usually used by daemons that create
+	// the log files as root before dropping privileges.
+	// A UID or GID of -1 means that the corresponding value will not be changed.
+	Chown bool
+	UID   int
+	GID   int
+
+	// OnRotate is the list of callbacks called with the backup file path after each
+	// rotation, which can be used to compress, upload or notify the backup files.
+	// The callbacks are called in order in a separate goroutine, so they do not block
+	// the log writing, and the backup file is not removed by the backup limit until the
+	// callbacks are finished.
+	OnRotate []func(backup string)
+
+	// DatedBackupDirs indicates whether to move the backup files into the year/month/day
+	// subdirectories of the log file directory, such as "2006/01/02/app-<time>.log",
+	// instead of keeping all backup files in the log file directory.
+	DatedBackupDirs bool
+
+	// RotateInterval is the interval of the time-based rotation, such as time.Hour or
+	// 24 * time.Hour, the log file is rotated at the local time boundaries of the interval
+	// even if the maximum size is not reached. The rotation happens on the first write after
+	// the boundary, so the empty log files are not rotated. If it is less than or equal to 0,
+	// the time-based rotation is disabled.
+	RotateInterval time.Duration
+
+	// BackupNameFunc returns the file name (without the directory) of the backup file of the
+	// log file with the given name and extension rotated at the given time, such as the names
+	// with the sequence numbers or the host names. The given directory is the directory where
+	// the backup file is placed. If the returned file already exists, the function is called
+	// again with a later time. If no unused name is returned within 100 calls, the rotation is
+	// skipped, the logs are still written to the log file, and the error is reported by the
+	// internal error handler. If it is nil, "<name>-<time><ext>" is used.
+	BackupNameFunc func(dir, name, ext string, t time.Time) string
+
+	// BackupMatchFunc determines whether the given file name is a backup file name of the log
+	// file with the given name and extension, which is used to find the backup files for the
+	// backup limit and the statistics. It should match the names of the BackupNameFunc.
+	// If the BackupNameFunc is set but the BackupMatchFunc is nil, the backup files are never
+	// removed by the backup limit. The custom backup files are sorted by the modification time.
+	BackupMatchFunc func(name, ext, file string) bool
+}
+
+// NewFileWriterWithOptions creates and returns an io.WriteCloser instance from the given path
+// and options.
+// The log file writer we returned does not restrict concurrent writing. If necessary,
+// you can use the writer wrapper with lock provided by us.
+func NewFileWriterWithOptions(name string, opts FileWriterOptions) (io.WriteCloser, error) {
 	if abs, err := filepath.Abs(name); err != nil {
 		return nil, err
 	} else {
 		name = abs
 	}
-	w := &fileWriter{path: name, max: max, backup: backup, clear: make(chan struct{}, 1)}
+	w := &fileWriter{
+		path: name, max: opts.MaxSize, backup: opts.MaxBackups, clear: make(chan struct{}, 1),
+		syncPolicy: opts.SyncPolicy, syncInterval: opts.SyncInterval, syncEvery: opts.SyncEvery,
+		chown: opts.Chown, uid: opts.UID, gid: opts.GID, onRotate: opts.OnRotate,
+		datedBackup: opts.DatedBackupDirs, interval: opts.RotateInterval,
+		backupName: opts.BackupNameFunc, backupMatch: opts.BackupMatchFunc, maxAge: opts.MaxAge,
+	}
+	if w.interval > 0 {
+		// The existing log file is rotated on the first write if it was last written
+		// before the current interval.
+		since := time.Now()
+		if info, err := os.Stat(name); err == nil && info.Mode().IsRegular() {
+			since = info.ModTime()
+		}
+		w.rotateAt = nextRotateTime(since, w.interval)
+	}
 	if err := w.open(); err != nil {
 		return nil, err
 	}
+	if w.maxAge > 0 {
+		w.clean()
+	}
 	return w, nil
 }
 
-// MustNewFileWriter is like NewFileWriter, but triggers a panic when an error occurs.
-func MustNewFileWriter(name string, max, backup uint32) io.WriteCloser {
-	w, err := NewFileWriter(name, max, backup)
+// MustNewFileWriterWithOptions is like NewFileWriterWithOptions, but triggers a panic when an
+// error occurs.
+func MustNewFileWriterWithOptions(name string, opts FileWriterOptions) io.WriteCloser {
+	w, err := NewFileWriterWithOptions(name, opts)
 	if err != nil {
 		panic(err)
 	}
@@ -65,11 +210,38 @@ type fileWriter struct {
 	mu     sync.Mutex
 	file   *os.File
 	path   string
-	size   uint32 // The current log file size.
-	max    uint32 // The maximum size of the log file.
+	size   int64  // The current log file size.
+	max    int64  // The maximum size of the log file.
 	backup uint32 // The maximum number of backup log files.
 	once   sync.Once
 	clear  chan struct{}
+
+	syncPolicy   FileSyncPolicy
+	syncInterval time.Duration
+	syncEvery    int
+	unsynced     int           // The number of writes since the last synchronization.
+	syncDone     chan struct{} // Stops the interval synchronization.
+
+	chown    bool
+	uid, gid int
+	onRotate []func(string)
+
+	pendingMu sync.Mutex
+	pending   map[string]int // The backup files whose rotation callbacks are running.
+
+	datedBackup bool // Place the backup files in the dated subdirectories.
+
+	interval time.Duration // The interval of the time-based rotation.
+	rotateAt time.Time     // The time of the next time-based rotation.
+	maxAge   time.Duration // The maximum age of backup log files.
+
+	backupName  func(dir, name, ext string, t time.Time) string
+	backupMatch func(name, ext, file string) bool
+
+	written       uint64 // The total number of bytes written.
+	rotations     uint64 // The total number of rotations.
+	lastError     error
+	lastErrorTime time.Time
 }
 
 // Write is an implementation of the io.WriteCloser interface, used to write a single
@@ -77,18 +249,115 @@ type fileWriter struct {
 func (w *fileWriter) Write(b []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if w.interval > 0 {
+		if now := time.Now(); !now.Before(w.rotateAt) {
+			if w.file != nil && w.size > 0 {
+				w.rotate()
+			}
+			w.rotateAt = nextRotateTime(now, w.interval)
+		}
+	}
 	if w.file == nil {
 		err = w.open()
 		if err != nil {
+			w.setError(err)
 			return
 		}
 	}
 	n, err = w.file.Write(b)
-	if w.max > 0 {
-		w.size += uint32(n)
-		if w.size >= w.max {
-			w.rotate()
+	if err != nil {
+		w.setError(err)
+	}
+	w.syncAfterWrite()
+	w.size += int64(n)
+	w.written += uint64(n)
+	if w.max > 0 && w.size >= w.max {
+		w.rotate()
+	}
+	return
+}
+
+// Stats is an implementation of the FileStatsProvider interface.
+func (w *fileWriter) Stats() FileStats {
+	w.mu.Lock()
+	stats := FileStats{
+		Size:          w.size,
+		Written:       w.written,
+		Rotations:     w.rotations,
+		LastErrorTime: w.lastErrorTime,
+	}
+	if w.lastError != nil {
+		stats.LastError = w.lastError.Error()
+	}
+	w.mu.Unlock()
+	// The backup files are counted without holding the lock, because the backup
+	// files can only be found by scanning the log file directory.
+	if files, err := w.findBackupFiles(); err == nil {
+		stats.Backups = len(files)
+	}
+	return stats
+}
+
+// Records the last error of the current log file writer.
+func (w *fileWriter) setError(err error) {
+	w.lastError, w.lastErrorTime = err, time.Now()
+}
+
+// Rotate is an implementation of the Rotator interface, used to rename the current log
+// file to a backup file immediately. The subsequent logs will be written to a new log file.
+// If the current log file does not exist or is empty, nothing will be done.
+func (w *fileWriter) Rotate() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// The current log file is kept if no backup file name can be found.
+	backup, err := w.newBackupFileName()
+	if err != nil {
+		return
+	}
+	if w.file != nil {
+		err = w.file.Sync()
+		if err2 := w.file.Close(); err == nil {
+			err = err2
+		}
+		w.file, w.size, w.unsynced = nil, 0, 0
+	}
+	info, err2 := os.Stat(w.path)
+	if err2 != nil {
+		if err == nil && !os.IsNotExist(err2) {
+			err = err2
 		}
+		return
+	}
+	if info.Size() == 0 {
+		return
+	}
+	if err2 = w.backupFile(backup); err2 != nil && err == nil {
+		err = err2
+	}
+	return
+}
+
+// Reopen is an implementation of the Reopener interface.
+// If the log file has been renamed or removed by external tools, such as logrotate in
+// "create" mode, the current log file will be closed and the log file path will be
+// reopened. If the log file path still refers to the current log file, nothing will be done.
+func (w *fileWriter) Reopen() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file != nil {
+		if current, err2 := w.file.Stat(); err2 == nil {
+			if info, err3 := os.Stat(w.path); err3 == nil && os.SameFile(current, info) {
+				return nil
+			}
+		}
+		err = w.file.Sync()
+		if err2 := w.file.Close(); err == nil {
+			err = err2
+		}
+		w.file, w.size, w.unsynced = nil, 0, 0
+	}
+	if err2 := w.open(); err == nil {
+		err = err2
 	}
 	return
 }
@@ -97,8 +366,12 @@ func (w *fileWriter) Write(b []byte) (n int, err error) {
 func (w *fileWriter) Close() (err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if w.syncDone != nil {
+		close(w.syncDone)
+		w.syncDone = nil
+	}
 	if w.file != nil {
-		defer func() { w.file, w.size = nil, 0 }()
+		defer func() { w.file, w.size, w.unsynced = nil, 0, 0 }()
 		err = w.file.Sync()
 		if err2 := w.file.Close(); err == nil {
 			err = err2
@@ -108,12 +381,18 @@ func (w *fileWriter) Close() (err error) {
 }
 
 func (w *fileWriter) open() error {
-	dir, name, ext := splitFilePath(w.path)
-	err := os.MkdirAll(dir, dirPerm)
-	if err != nil {
+	dir := filepath.Dir(w.path)
+	var info os.FileInfo
+	_, err := os.Stat(dir)
+	created := os.IsNotExist(err)
+	if err = os.MkdirAll(dir, dirPerm); err != nil {
 		return err
 	}
-	var info os.FileInfo
+	if created && w.chown {
+		if err = os.Chown(dir, w.uid, w.gid); err != nil {
+			return err
+		}
+	}
 	var file *os.File
 	if info, err = os.Stat(w.path); err != nil {
 		if os.IsNotExist(err) {
@@ -121,7 +400,14 @@ func (w *fileWriter) open() error {
 			if err != nil {
 				return err
 			}
+			if w.chown {
+				if err = file.Chown(w.uid, w.gid); err != nil {
+					_ = file.Close()
+					return err
+				}
+			}
 			w.file, w.size = file, 0
+			w.startSyncer()
 			return nil
 		}
 		return err
@@ -129,24 +415,85 @@ func (w *fileWriter) open() error {
 	if !info.Mode().IsRegular() {
 		return fmt.Errorf("path %s exists, but not is regular file", w.path)
 	}
-	if w.max > 0 && uint32(info.Size()) > w.max {
-		if err = os.Rename(w.path, newBackupFileName(dir, name, ext)); err != nil {
+	// If the existing file is renamed, a new file will be created.
+	created = false
+	if w.max > 0 && info.Size() > w.max {
+		// If no backup file name can be found, the logs are still written to the log file.
+		if backup, err := w.newBackupFileName(); err != nil {
+			w.setError(err)
+			internal.EchoError("Failed to rotate %s: %s.", w.path, err)
+		} else if err = w.backupFile(backup); err != nil {
 			return err
+		} else {
+			created = true
 		}
-		w.clean()
 	}
 	if file, err = os.OpenFile(w.path, fileFlag, filePerm); err != nil {
 		return err
 	}
+	if created && w.chown {
+		if err = file.Chown(w.uid, w.gid); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
 	if info, err = file.Stat(); err != nil {
 		_ = file.Close()
 		return err
 	}
 	w.file = file
-	w.size = uint32(info.Size())
+	w.size = info.Size()
+	w.startSyncer()
 	return nil
 }
 
+// Starts the interval synchronization if it is required and not already running.
+func (w *fileWriter) startSyncer() {
+	if w.syncPolicy == FileSyncInterval && w.syncInterval > 0 && w.syncDone == nil {
+		w.syncDone = make(chan struct{})
+		go w.syncer(w.syncDone)
+	}
+}
+
+// Synchronizes the log file according to the synchronization policy after writing.
+func (w *fileWriter) syncAfterWrite() {
+	switch w.syncPolicy {
+	case FileSyncEveryN:
+		if w.unsynced++; w.unsynced < w.syncEvery {
+			return
+		}
+	case FileSyncAlways:
+	default:
+		return
+	}
+	w.unsynced = 0
+	if err := w.file.Sync(); err != nil {
+		w.setError(err)
+		internal.EchoError("Failed to sync %s: %s.", w.path, err)
+	}
+}
+
+// Synchronizes the log file periodically until the given channel is closed.
+func (w *fileWriter) syncer(done chan struct{}) {
+	ticker := time.NewTicker(w.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.file != nil {
+				if err := w.file.Sync(); err != nil {
+					w.setError(err)
+					internal.EchoError("Failed to sync %s: %s.", w.path, err)
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
 func (w *fileWriter) clean() {
 	w.once.Do(w.sweeper)
 	select {
@@ -156,45 +503,266 @@ func (w *fileWriter) clean() {
 }
 
 func (w *fileWriter) rotate() {
+	// If no backup file name can be found, the logs are still written to the log file.
+	backup, err := w.newBackupFileName()
+	if err != nil {
+		w.setError(err)
+		internal.EchoError("Failed to rotate %s: %s.", w.path, err)
+		return
+	}
 	if err := w.file.Sync(); err != nil {
+		w.setError(err)
 		internal.EchoError("Failed to sync %s: %s.", w.path, err)
 	}
 	if err := w.file.Close(); err != nil {
+		w.setError(err)
 		internal.EchoError("Failed to close %s: %s.", w.path, err)
 	}
-	w.file, w.size = nil, 0
-	dir, name, ext := splitFilePath(w.path)
-	if err := os.Rename(w.path, newBackupFileName(dir, name, ext)); err != nil {
+	w.file, w.size, w.unsynced = nil, 0, 0
+	if err := w.backupFile(backup); err != nil {
+		w.setError(err)
 		internal.EchoError("Failed to rename %s: %s.", w.path, err)
 	}
+}
+
+// Creates a new backup file name of the current log file.
+func (w *fileWriter) newBackupFileName() (string, error) {
+	dir, name, ext := splitFilePath(w.path)
+	return newBackupFileName(dir, name, ext, w.datedBackup, w.backupName)
+}
+
+// Renames the current log file to the given backup file, and notifies the rotation callbacks.
+func (w *fileWriter) backupFile(backup string) error {
+	if w.datedBackup {
+		if err := w.mkdirBackup(backup); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(w.path, backup); err != nil {
+		return err
+	}
+	w.rotations++
+	if len(w.onRotate) > 0 {
+		w.setPending(backup, 1)
+		go w.notifyRotate(backup)
+	}
 	w.clean()
+	return nil
+}
+
+// Calls the rotation callbacks in order with the given backup file path, and cleans
+// the backup files after the callbacks are finished.
+func (w *fileWriter) notifyRotate(backup string) {
+	for i, j := 0, len(w.onRotate); i < j; i++ {
+		w.onRotate[i](backup)
+	}
+	w.setPending(backup, -1)
+	w.clean()
+}
+
+// Adds the given delta to the number of the running rotation callbacks of the given backup file.
+func (w *fileWriter) setPending(backup string, delta int) {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+	if w.pending == nil {
+		w.pending = make(map[string]int)
+	}
+	if n := w.pending[backup] + delta; n > 0 {
+		w.pending[backup] = n
+	} else {
+		delete(w.pending, backup)
+	}
+}
+
+// Removes the backup files whose rotation callbacks are running from the given files.
+func (w *fileWriter) excludePending(files []string) []string {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+	if len(w.pending) == 0 {
+		return files
+	}
+	others := files[:0]
+	for i, j := 0, len(files); i < j; i++ {
+		if w.pending[files[i]] == 0 {
+			others = append(others, files[i])
+		}
+	}
+	return others
 }
 
 func (w *fileWriter) sweeper() {
 	go func() {
 		for {
 			<-w.clear
-			dir, name, ext := splitFilePath(w.path)
-			if items, err := os.ReadDir(dir); err == nil {
-				if len(items) == 0 {
-					continue
-				}
-				base, files := filepath.Base(w.path), make([]string, 0)
-				for i, j := 0, len(items); i < j; i++ {
-					if items[i].Type().IsRegular() && isBackupFileName(items[i].Name(), base, name+"-", ext) {
-						files = append(files, filepath.Join(dir, items[i].Name()))
-					}
-				}
-				if n := uint32(len(files)); n > w.backup {
-					removeFiles(files[:n-w.backup])
+			files, err := w.findBackupFiles()
+			if err != nil {
+				internal.EchoError("Call os.ReadDir() with dir %s failed: %s.", filepath.Dir(w.path), err)
+				continue
+			}
+			// The backup files used by the rotation callbacks are kept until the callbacks are finished.
+			files = w.excludePending(files)
+			var removed []string
+			if w.maxAge > 0 {
+				removed, files = splitExpiredFiles(files, time.Now().Add(-w.maxAge))
+			}
+			if n := uint32(len(files)); n > w.backup && (w.backup > 0 || w.maxAge <= 0) {
+				removed = append(removed, files[:n-w.backup]...)
+			}
+			if len(removed) > 0 {
+				removeFiles(removed)
+				if w.datedBackup {
+					removeEmptyBackupDirs(filepath.Dir(w.path), removed)
 				}
-			} else {
-				internal.EchoError("Call os.ReadDir() with dir %s failed: %s.", dir, err)
 			}
 		}
 	}()
 }
 
+// Finds all backup files of the current log file, sorted from oldest to newest.
+func (w *fileWriter) findBackupFiles() ([]string, error) {
+	dir, name, ext := splitFilePath(w.path)
+	dirs := []string{dir}
+	if w.datedBackup {
+		// The pattern is always valid, and the matched directories are sorted.
+		found, _ := filepath.Glob(filepath.Join(dir, "[0-9][0-9][0-9][0-9]", "[0-9][0-9]", "[0-9][0-9]"))
+		dirs = append(dirs, found...)
+	}
+	base, files := filepath.Base(w.path), make([]string, 0)
+	for i, j := 0, len(dirs); i < j; i++ {
+		items, err := os.ReadDir(dirs[i])
+		if err != nil {
+			// The dated directories may be removed by the sweeper concurrently.
+			if i > 0 && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for k, l := 0, len(items); k < l; k++ {
+			if items[k].Type().IsRegular() && w.isBackupFile(items[k].Name(), base, name, ext) {
+				files = append(files, filepath.Join(dirs[i], items[k].Name()))
+			}
+		}
+	}
+	if w.backupName != nil {
+		sortFilesByModTime(files)
+	} else if len(dirs) > 1 {
+		// The backup file names contain the backup time, so sorting by the names is
+		// sorting by the backup time.
+		sort.SliceStable(files, func(i, j int) bool {
+			return filepath.Base(files[i]) < filepath.Base(files[j])
+		})
+	}
+	return files, nil
+}
+
+// Determines if the given file name is a backup file name of the current log file.
+func (w *fileWriter) isBackupFile(target, base, name, ext string) bool {
+	if w.backupName == nil {
+		return isBackupFileName(target, base, name+"-", ext)
+	}
+	return target != base && w.backupMatch != nil && w.backupMatch(name, ext, target)
+}
+
+// Sorts the given files from oldest to newest by the modification time, the files with
+// the same modification time are sorted by the names.
+func sortFilesByModTime(files []string) {
+	times := make(map[string]time.Time, len(files))
+	for i, j := 0, len(files); i < j; i++ {
+		// The file removed concurrently is sorted as the oldest one.
+		if info, err := os.Stat(files[i]); err == nil {
+			times[files[i]] = info.ModTime()
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if ti, tj := times[files[i]], times[files[j]]; !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return filepath.Base(files[i]) < filepath.Base(files[j])
+	})
+}
+
+// Creates the directories of the given backup file and changes the owner of the
+// created directories if necessary.
+func (w *fileWriter) mkdirBackup(backup string) error {
+	root := filepath.Dir(w.path)
+	var missing []string
+	for dir := filepath.Dir(backup); dir != root; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			break
+		}
+		missing = append(missing, dir)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(missing[0], dirPerm); err != nil {
+		return err
+	}
+	if w.chown {
+		for i, j := 0, len(missing); i < j; i++ {
+			if err := os.Chown(missing[i], w.uid, w.gid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Removes the empty dated directories of the given removed backup files.
+func removeEmptyBackupDirs(root string, files []string) {
+	for i, j := 0, len(files); i < j; i++ {
+		for dir := filepath.Dir(files[i]); dir != root; dir = filepath.Dir(dir) {
+			// Only empty directories can be removed, so the error is expected here.
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+}
+
+// Rotate all the given writers that implement the Rotator interface.
+// Each writer is rotated only once, and the first error encountered is returned.
+func rotateWriters(writers []io.Writer) (err error) {
+	var rotated []Rotator
+	for i, j := 0, len(writers); i < j; i++ {
+		r, ok := writers[i].(Rotator)
+		if !ok || containsRotator(rotated, r) {
+			continue
+		}
+		rotated = append(rotated, r)
+		if e := r.Rotate(); err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// Determines if the given rotator is in the given list.
+func containsRotator(rotators []Rotator, r Rotator) bool {
+	// Comparing uncomparable values will panic.
+	if !reflect.TypeOf(r).Comparable() {
+		return false
+	}
+	for i, j := 0, len(rotators); i < j; i++ {
+		if rotators[i] == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Splits the given files into the files modified before the given time and the others.
+func splitExpiredFiles(files []string, before time.Time) (expired, others []string) {
+	for i, j := 0, len(files); i < j; i++ {
+		if info, err := os.Stat(files[i]); err == nil && info.ModTime().Before(before) {
+			expired = append(expired, files[i])
+		} else {
+			others = append(others, files[i])
+		}
+	}
+	return
+}
+
 // Delete the given list of files.
 func removeFiles(files []string) {
 	for i, j := 0, len(files); i < j; i++ {
@@ -213,8 +781,43 @@ func splitFilePath(path string) (dir, name, ext string) {
 }
 
 // Create a new log backup file name.
-func newBackupFileName(dir, name, ext string) string {
-	return filepath.Join(dir, name+"-"+time.Now().Local().Format(backupTimeFormat)+ext)
+// If the backup file of the current time already exists, for example, the log file is
+// rotated multiple times within one millisecond, the time is moved forward until the
+// backup file name is unused.
+// If the dated parameter is true, the backup file is placed in the year/month/day
+// subdirectory of the given directory. If the given naming function is not nil, it is
+// used to name the backup file instead of the default "<name>-<time><ext>", and an error
+// is returned if it does not return an unused name within maxBackupNameAttempts attempts,
+// since the default name is not managed by the matching function of the custom names.
+func newBackupFileName(dir, name, ext string, dated bool, fn func(string, string, string, time.Time) string) (string, error) {
+	now := time.Now().Local()
+	for i := 0; ; i++ {
+		target := dir
+		if dated {
+			target = filepath.Join(dir, now.Format("2006"), now.Format("01"), now.Format("02"))
+		}
+		var path string
+		if fn != nil {
+			// The naming function may ignore the time, so we give up to avoid looping forever.
+			if i >= maxBackupNameAttempts {
+				return "", fmt.Errorf("no unused backup file name after %d attempts", maxBackupNameAttempts)
+			}
+			path = filepath.Join(target, fn(target, name, ext, now))
+		} else {
+			path = filepath.Join(target, name+"-"+now.Format(backupTimeFormat)+ext)
+		}
+		if _, err := os.Lstat(path); err != nil {
+			return path, nil
+		}
+		now = now.Add(time.Millisecond)
+	}
+}
+
+// Returns the next local time boundary of the given interval after the given time.
+func nextRotateTime(t time.Time, interval time.Duration) time.Time {
+	_, offset := t.Zone()
+	d := time.Duration(offset) * time.Second
+	return t.Add(d).Truncate(interval).Add(interval - d)
 }
 
 // Determines if the given filename is a log backup file.
@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -180,3 +182,602 @@ func TestFileWriterWithExistBigFile(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewFileWriterWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+
+	policies := []FileSyncPolicy{FileSyncNever, FileSyncInterval, FileSyncEveryN, FileSyncAlways}
+	for _, policy := range policies {
+		w, err := NewFileWriterWithOptions(name, FileWriterOptions{
+			SyncPolicy:   policy,
+			SyncInterval: time.Millisecond,
+			SyncEvery:    2,
+		})
+		if err != nil {
+			t.Fatalf("NewFileWriterWithOptions(): policy %d error: %s", policy, err)
+		}
+		for i := 0; i < 3; i++ {
+			if _, err = w.Write([]byte("test\n")); err != nil {
+				t.Fatalf("NewFileWriterWithOptions(): policy %d write error: %s", policy, err)
+			}
+		}
+		time.Sleep(time.Millisecond * 5)
+		if err = w.Close(); err != nil {
+			t.Fatalf("NewFileWriterWithOptions(): policy %d close error: %s", policy, err)
+		}
+	}
+	if got, err := os.ReadFile(name); err != nil {
+		t.Fatal(err)
+	} else {
+		if want := bytes.Repeat([]byte("test\n"), 12); !bytes.Equal(got, want) {
+			t.Fatalf("NewFileWriterWithOptions(): %q", got)
+		}
+	}
+	if _, err := NewFileWriterWithOptions(dir, FileWriterOptions{}); err == nil {
+		t.Fatal("NewFileWriterWithOptions(): nil error")
+	}
+}
+
+func TestFileWriterSyncEveryN(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.log")
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{SyncPolicy: FileSyncEveryN, SyncEvery: 3})
+	defer func() { _ = w.Close() }()
+
+	fw := w.(*fileWriter)
+	for i := 1; i <= 5; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+		if want := i % 3; fw.unsynced != want {
+			t.Fatalf("FileWriter.Write(): unsynced %d, want %d", fw.unsynced, want)
+		}
+	}
+}
+
+func TestFileWriterSyncIntervalReopen(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.log")
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{
+		SyncPolicy:   FileSyncInterval,
+		SyncInterval: time.Millisecond,
+	})
+	fw := w.(*fileWriter)
+	if fw.syncDone == nil {
+		t.Fatal("NewFileWriterWithOptions(): syncer not started")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if fw.syncDone != nil {
+		t.Fatal("FileWriter.Close(): syncer not stopped")
+	}
+	// Writing after closing reopens the file and restarts the syncer.
+	if _, err := w.Write([]byte("test\n")); err != nil {
+		t.Fatal(err)
+	}
+	if fw.syncDone == nil {
+		t.Fatal("FileWriter.Write(): syncer not restarted")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMustNewFileWriterWithOptions_Panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustNewFileWriterWithOptions(): no panic")
+		}
+	}()
+	MustNewFileWriterWithOptions(t.TempDir(), FileWriterOptions{})
+}
+
+func TestFileWriterWithChown(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "logs", "test.log")
+	// Changing the owner to the current user is always allowed.
+	w, err := NewFileWriterWithOptions(name, FileWriterOptions{
+		MaxSize: 20,
+		Chown:   true,
+		UID:     os.Getuid(),
+		GID:     os.Getgid(),
+	})
+	if err != nil {
+		t.Fatalf("NewFileWriterWithOptions(): chown error: %s", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	for i := 0; i < 3; i++ {
+		if _, err = w.Write([]byte("0123456789\n")); err != nil {
+			t.Fatalf("FileWriter.Write(): chown error: %s", err)
+		}
+	}
+	// The second record reaches the size limit, the third record creates a new file.
+	if items, err := os.ReadDir(filepath.Dir(name)); err != nil {
+		t.Fatal(err)
+	} else {
+		if len(items) != 2 {
+			t.Fatalf("FileWriter.Write(): chown %d files", len(items))
+		}
+	}
+}
+
+func TestFileWriterWithLargeMaxSize(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.log")
+	// The size limit exceeds the range of uint32.
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{MaxSize: MustParseSize("5GB")})
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Write([]byte("test\n")); err != nil {
+		t.Fatal(err)
+	}
+	fw := w.(*fileWriter)
+	if fw.max != 5<<30 || fw.size != 5 {
+		t.Fatalf("FileWriter.Write(): max %d, size %d", fw.max, fw.size)
+	}
+}
+
+func TestFileWriterRotate(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	w := MustNewFileWriter(name, 0, 10)
+	defer func() { _ = w.Close() }()
+
+	r, ok := w.(Rotator)
+	if !ok {
+		t.Fatal("FileWriter: not a Rotator")
+	}
+	// The empty log file is not rotated.
+	if err := r.Rotate(); err != nil {
+		t.Fatalf("FileWriter.Rotate(): %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+		// Multiple rotations within one millisecond must not overwrite each other.
+		if err := r.Rotate(); err != nil {
+			t.Fatalf("FileWriter.Rotate(): %s", err)
+		}
+	}
+	if items, err := os.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else {
+		if len(items) != 3 {
+			t.Fatalf("FileWriter.Rotate(): %d files", len(items))
+		}
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("FileWriter.Rotate(): log file exists: %v", err)
+	}
+	if _, err := w.Write([]byte("test\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(name); err != nil {
+		t.Fatal(err)
+	} else {
+		if string(got) != "test\n" {
+			t.Fatalf("FileWriter.Rotate(): %q", got)
+		}
+	}
+}
+
+func TestFileWriterRotateInterval(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	// The existing log file written before the current interval is rotated on the first write.
+	if err := os.WriteFile(name, []byte("old\n"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(name, old, old); err != nil {
+		t.Fatal(err)
+	}
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{MaxBackups: 10, RotateInterval: time.Millisecond * 100})
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Write([]byte("a\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(name); string(got) != "a\n" {
+		t.Fatalf("FileWriter.Write(): %q", got)
+	}
+	if n := w.(FileStatsProvider).Stats().Rotations; n != 1 {
+		t.Fatalf("FileWriter.Write(): %d rotations", n)
+	}
+
+	time.Sleep(time.Until(nextRotateTime(time.Now(), time.Millisecond*100)))
+	if _, err := w.Write([]byte("b\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(name); string(got) != "b\n" {
+		t.Fatalf("FileWriter.Write(): %q", got)
+	}
+	if n := w.(FileStatsProvider).Stats().Rotations; n != 2 {
+		t.Fatalf("FileWriter.Write(): %d rotations", n)
+	}
+}
+
+func TestNextRotateTime(t *testing.T) {
+	loc := time.FixedZone("test", 5*3600+1800)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, loc)
+	if got, want := nextRotateTime(now, time.Hour), time.Date(2024, 1, 2, 4, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("nextRotateTime(): want %s, got %s", want, got)
+	}
+	if got, want := nextRotateTime(now, time.Hour*24), time.Date(2024, 1, 3, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("nextRotateTime(): want %s, got %s", want, got)
+	}
+	if got, want := nextRotateTime(time.Date(2024, 1, 2, 0, 0, 0, 0, loc), time.Hour*24), time.Date(2024, 1, 3, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("nextRotateTime(): want %s, got %s", want, got)
+	}
+}
+
+func TestFileWriterBackupNameFunc(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	var seq int
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{
+		MaxSize:    4,
+		MaxBackups: 2,
+		BackupNameFunc: func(dir, name, ext string, t time.Time) string {
+			seq++
+			return name + "." + strconv.Itoa(seq) + ext
+		},
+		BackupMatchFunc: func(name, ext, file string) bool {
+			return strings.HasPrefix(file, name+".") && strings.HasSuffix(file, ext)
+		},
+	})
+	defer func() { _ = w.Close() }()
+
+	// A file that looks like a default backup file is not a custom backup file.
+	other := filepath.Join(dir, "test-"+time.Now().Format(backupTimeFormat)+".log")
+	if err := os.WriteFile(other, nil, filePerm); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	time.Sleep(time.Millisecond * 50)
+
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Name())
+	}
+	if strings.Join(got, ",") != filepath.Base(other)+",test.3.log,test.4.log" {
+		t.Fatalf("FileWriter.BackupNameFunc: %v", got)
+	}
+	if n := w.(FileStatsProvider).Stats().Backups; n != 2 {
+		t.Fatalf("FileWriter.Stats(): %d backups", n)
+	}
+}
+
+func TestFileWriterBackupNameFunc_Exhausted(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{
+		MaxSize:         4,
+		MaxBackups:      5,
+		BackupNameFunc:  func(dir, name, ext string, t time.Time) string { return name + ".bak" + ext },
+		BackupMatchFunc: func(name, ext, file string) bool { return file == name+".bak"+ext },
+	})
+	defer func() { _ = w.Close() }()
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The log file is not rotated to an unmanaged backup file when the name is used,
+	// and the logs are still written to the log file.
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Name())
+	}
+	if strings.Join(got, ",") != "test.bak.log,test.log" {
+		t.Fatalf("FileWriter.BackupNameFunc: %v", got)
+	}
+	if got, err := os.ReadFile(name); err != nil || string(got) != "test\ntest\n" {
+		t.Fatalf("FileWriter.Write(): %q %v", got, err)
+	}
+	if s := w.(FileStatsProvider).Stats(); s.LastError == "" {
+		t.Fatalf("FileWriter.Stats(): %+v", s)
+	}
+	if err := w.(Rotator).Rotate(); err == nil {
+		t.Fatal("FileWriter.Rotate(): nil error")
+	}
+	if _, err := w.Write([]byte("test\n")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewBackupFileName_Func(t *testing.T) {
+	dir := t.TempDir()
+	fn := func(dir, name, ext string, t time.Time) string { return name + ".bak" + ext }
+	got, err := newBackupFileName(dir, "test", ".log", false, fn)
+	if err != nil || got != filepath.Join(dir, "test.bak.log") {
+		t.Fatalf("newBackupFileName(): %s %v", got, err)
+	}
+	if err = os.WriteFile(got, nil, filePerm); err != nil {
+		t.Fatal(err)
+	}
+	// The naming function ignoring the time never returns an unused name.
+	if got, err = newBackupFileName(dir, "test", ".log", false, fn); err == nil {
+		t.Fatalf("newBackupFileName(): %s", got)
+	}
+}
+
+func TestFileWriterMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	now := time.Now()
+	var backups []string
+	for i := 0; i < 3; i++ {
+		backup := filepath.Join(dir, "test-"+now.Add(time.Duration(i-3)*time.Hour).Format(backupTimeFormat)+".log")
+		if err := os.WriteFile(backup, []byte("test\n"), filePerm); err != nil {
+			t.Fatal(err)
+		}
+		mt := now.Add(time.Duration(i-3)*time.Hour + time.Minute*30)
+		if err := os.Chtimes(backup, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+		backups = append(backups, backup)
+	}
+
+	// The backup files older than the maximum age are removed when the writer is created,
+	// and the number of backup files is not limited.
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{MaxSize: 4, MaxAge: time.Hour * 2})
+	defer func() { _ = w.Close() }()
+	time.Sleep(time.Millisecond * 50)
+	for i, backup := range backups {
+		if _, err := os.Stat(backup); (i == 0) != os.IsNotExist(err) {
+			t.Fatalf("FileWriter.MaxAge: %s %v", backup, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(time.Millisecond * 50)
+	if n := w.(FileStatsProvider).Stats().Backups; n != 4 {
+		t.Fatalf("FileWriter.MaxAge: %d backups", n)
+	}
+}
+
+func TestSplitExpiredFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	for _, name := range []string{a, b} {
+		if err := os.WriteFile(name, nil, filePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(a, old, old); err != nil {
+		t.Fatal(err)
+	}
+	expired, others := splitExpiredFiles([]string{a, b, filepath.Join(dir, "c")}, time.Now().Add(-time.Minute))
+	if len(expired) != 1 || expired[0] != a || len(others) != 2 || others[0] != b {
+		t.Fatalf("splitExpiredFiles(): %v %v", expired, others)
+	}
+}
+
+func TestNewBackupFileName(t *testing.T) {
+	dir := t.TempDir()
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		got, err := newBackupFileName(dir, "test", ".log", false, nil)
+		if err != nil || seen[got] {
+			t.Fatalf("newBackupFileName(): duplicate %s", got)
+		}
+		seen[got] = true
+		if err := os.WriteFile(got, nil, filePerm); err != nil {
+			t.Fatal(err)
+		}
+		if !isBackupFileName(filepath.Base(got), "test.log", "test-", ".log") {
+			t.Fatalf("newBackupFileName(): invalid %s", got)
+		}
+	}
+}
+
+func TestFileWriterOnRotate(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	ch := make(chan string, 10)
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{
+		MaxSize:    10,
+		MaxBackups: 10,
+		OnRotate: []func(string){
+			func(backup string) { ch <- "1:" + backup },
+			func(backup string) { ch <- "2:" + backup },
+		},
+	})
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Write([]byte("0123456789\n")); err != nil {
+		t.Fatal(err)
+	}
+	var backup string
+	for i := 1; i <= 2; i++ {
+		select {
+		case got := <-ch:
+			if got[:2] != string(rune('0'+i))+":" {
+				t.Fatalf("FileWriter.Write(): callback order %q", got)
+			}
+			backup = got[2:]
+		case <-time.After(time.Second):
+			t.Fatal("FileWriter.Write(): callback not called")
+		}
+	}
+	if !isBackupFileName(filepath.Base(backup), "test.log", "test-", ".log") {
+		t.Fatalf("FileWriter.Write(): invalid backup %s", backup)
+	}
+	if got, err := os.ReadFile(backup); err != nil {
+		t.Fatal(err)
+	} else {
+		if string(got) != "0123456789\n" {
+			t.Fatalf("FileWriter.Write(): backup %q", got)
+		}
+	}
+
+	// Manual rotation also notifies the callbacks.
+	if _, err := w.Write([]byte("test\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.(Rotator).Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("FileWriter.Rotate(): callback not called")
+		}
+	}
+}
+
+func TestFileWriterWithDatedBackupDirs(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	// An old backup file in the log file directory.
+	old := filepath.Join(dir, "test-2000-01-01T00-00-00.000.log")
+	if err := os.WriteFile(old, nil, filePerm); err != nil {
+		t.Fatal(err)
+	}
+	oldDir := filepath.Join(dir, "2000", "01", "02")
+	if err := os.MkdirAll(oldDir, dirPerm); err != nil {
+		t.Fatal(err)
+	}
+	oldDated := filepath.Join(oldDir, "test-2000-01-02T00-00-00.000.log")
+	if err := os.WriteFile(oldDated, nil, filePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan string, 10)
+	w := MustNewFileWriterWithOptions(name, FileWriterOptions{
+		MaxBackups:      2,
+		DatedBackupDirs: true,
+		OnRotate:        []func(string){func(backup string) { ch <- backup }},
+	})
+	defer func() { _ = w.Close() }()
+
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.(Rotator).Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case backup := <-ch:
+			rel, err := filepath.Rel(dir, backup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := time.Now().Local().Format("2006/01/02"); filepath.ToSlash(filepath.Dir(rel)) != want {
+				t.Fatalf("FileWriter.Rotate(): dated backup %s", rel)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("FileWriter.Rotate(): callback not called")
+		}
+	}
+
+	// The sweeper runs asynchronously.
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(filepath.Join(dir, "2000")); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("FileWriter.Rotate(): old backup not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2000")); !os.IsNotExist(err) {
+		t.Fatalf("FileWriter.Rotate(): old dated directory not removed: %v", err)
+	}
+	if files, err := w.(*fileWriter).findBackupFiles(); err != nil {
+		t.Fatal(err)
+	} else {
+		if len(files) != 2 {
+			t.Fatalf("FileWriter.Rotate(): backups %v", files)
+		}
+	}
+}
+
+func TestFileWriterReopen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.log")
+	w := MustNewFileWriter(name, 0, 0)
+	defer func() { _ = w.Close() }()
+
+	r, ok := w.(Reopener)
+	if !ok {
+		t.Fatal("FileWriter: not a Reopener")
+	}
+	if _, err := w.Write([]byte("foo\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The log file is not changed.
+	if err := r.Reopen(); err != nil {
+		t.Fatalf("FileWriter.Reopen(): %s", err)
+	}
+	if _, err := w.Write([]byte("bar\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The log file is renamed by external tools.
+	moved := filepath.Join(dir, "test.log.1")
+	if err := os.Rename(name, moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reopen(); err != nil {
+		t.Fatalf("FileWriter.Reopen(): %s", err)
+	}
+	if _, err := w.Write([]byte("baz\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The log file is removed by external tools.
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reopen(); err != nil {
+		t.Fatalf("FileWriter.Reopen(): %s", err)
+	}
+	if _, err := w.Write([]byte("qux\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := os.ReadFile(moved); err != nil {
+		t.Fatal(err)
+	} else {
+		if string(got) != "foo\nbar\n" {
+			t.Fatalf("FileWriter.Reopen(): moved %q", got)
+		}
+	}
+	if got, err := os.ReadFile(name); err != nil {
+		t.Fatal(err)
+	} else {
+		if string(got) != "qux\n" {
+			t.Fatalf("FileWriter.Reopen(): current %q", got)
+		}
+	}
+	// The closed writer is reopened.
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reopen(); err != nil {
+		t.Fatalf("FileWriter.Reopen(): %s", err)
+	}
+}
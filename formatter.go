@@ -16,7 +16,11 @@ package logger
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"sync"
+
+	"github.com/edoger/zkits-logger/internal"
 )
 
 // Formatter interface defines a standard log formatter.
@@ -31,6 +35,17 @@ type Formatter interface {
 	Format(Entity, *bytes.Buffer) error
 }
 
+// StreamFormatter interface defines a log formatter that can write log data directly to a writer.
+// When the logger enables streaming, the formatter will write the log data to the given writer, and
+// the log data exceeding the stream threshold will be written directly to the log writer in chunks,
+// instead of being buffered in the log entity buffer.
+type StreamFormatter interface {
+	Formatter
+
+	// FormatTo formats the given log entity into character data and writes it to the given writer.
+	FormatTo(Entity, io.Writer) error
+}
+
 // FormatterFunc type defines a log formatter in the form of a function.
 type FormatterFunc func(Entity, *bytes.Buffer) error
 
@@ -100,3 +115,87 @@ func (w *levelPriorityFormatOutput) Format(e Entity, b *bytes.Buffer) (io.Writer
 	}
 	return w.low.Format(e, b)
 }
+
+// NewShadowFormatOutput creates a log format output instance that formats and writes every
+// log through both the primary and the shadow format outputs, which is used to de-risk the
+// migration of log formats (for example, from the legacy text file to the new json shipper).
+// The log formatted by the primary format output is returned to the logger as usual, and the
+// shadow log is written to the writer returned by the shadow format output after the primary log.
+// The errors of the shadow format output never affect the primary log, they are passed to the
+// given error handler, if the error handler is nil, they are reported by the internal error handler.
+func NewShadowFormatOutput(primary, shadow FormatOutput, onError func(error)) FormatOutput {
+	return &fanOutFormatOutput{primary: primary, others: []FormatOutput{shadow}, onError: onError}
+}
+
+// NewFanOutFormatOutput creates a log format output instance that formats and writes every
+// log through all the given format outputs, so that one log can be formatted by the console
+// formatter to the standard output and by the json formatter to a file simultaneously.
+// The first format output is the primary one, the log formatted by it is returned to the logger
+// (and passed to the log hooks) as usual, and the logs formatted by the others are written to
+// their writers after the primary log, the errors of the others are reported by the internal error handler
+// and never affect the primary log. At least one format output must be given.
+func NewFanOutFormatOutput(primary FormatOutput, others ...FormatOutput) FormatOutput {
+	return &fanOutFormatOutput{primary: primary, others: others}
+}
+
+// This is the built-in fan-out format output wrapper.
+type fanOutFormatOutput struct {
+	primary FormatOutput
+	others  []FormatOutput
+	onError func(error)
+}
+
+// The pool of the fan-out log buffers.
+var fanOutBufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// Format formats the given log entity and returns the writer to which the log needs to be written.
+func (w *fanOutFormatOutput) Format(e Entity, b *bytes.Buffer) (io.Writer, error) {
+	fw, err := w.primary.Format(e, b)
+	entity, ok := e.(*logEntity)
+	for i, j := 0, len(w.others); i < j; i++ {
+		r := w.format(w.others[i], e)
+		if ok {
+			// The logger writes the secondary logs after the primary log, with the same
+			// record size limit and record terminator.
+			entity.fanOut = append(entity.fanOut, r)
+		} else {
+			r.write(e.Name())
+		}
+	}
+	return fw, err
+}
+
+// Formats the given log entity by the given format output.
+func (w *fanOutFormatOutput) format(f FormatOutput, e Entity) *fanOutRecord {
+	r := &fanOutRecord{buffer: fanOutBufferPool.Get().(*bytes.Buffer), onError: w.onError}
+	r.writer, r.err = f.Format(e, r.buffer)
+	if r.err == nil && r.writer == nil {
+		r.err = errors.New("nil log writer")
+	}
+	return r
+}
+
+// The log formatted by a secondary format output of the fan-out format output.
+type fanOutRecord struct {
+	writer  io.Writer
+	buffer  *bytes.Buffer
+	err     error
+	onError func(error)
+}
+
+// Writes the formatted log and releases the log buffer.
+func (r *fanOutRecord) write(name string) {
+	if r.err == nil {
+		_, r.err = r.writer.Write(r.buffer.Bytes())
+	}
+	if r.err != nil {
+		if r.onError != nil {
+			r.onError(r.err)
+		} else {
+			internal.EchoError("(%s) Failed to write fan-out log: %s", name, r.err)
+		}
+	}
+	r.buffer.Reset()
+	fanOutBufferPool.Put(r.buffer)
+	r.buffer = nil
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 )
 
@@ -100,3 +101,137 @@ func TestLevelPriorityFormatOutput(t *testing.T) {
 		t.Fatalf("LevelPriorityFormatOutput: %s", got)
 	}
 }
+
+func TestShadowFormatOutput(t *testing.T) {
+	w1 := new(bytes.Buffer)
+	w2 := new(bytes.Buffer)
+	f := NewShadowFormatOutput(
+		NewFormatOutput(DefaultTextFormatter(), w1),
+		NewFormatOutput(DefaultJSONFormatter(), w2),
+		nil,
+	)
+	if f == nil {
+		t.Fatal("NewShadowFormatOutput(): nil")
+	}
+
+	o := New("test")
+	o.SetFormatOutput(f)
+	o.SetDefaultTimeFormat("test")
+
+	o.Info("test")
+
+	if got, want := w1.String(), "test:[test][INF] test\n"; got != want {
+		t.Fatalf("ShadowFormatOutput: want %q, got %q", want, got)
+	}
+	if got, want := w2.String(), `{"level":"info","message":"test","name":"test","time":"test"}`+"\n"; got != want {
+		t.Fatalf("ShadowFormatOutput: want %q, got %q", want, got)
+	}
+}
+
+func TestShadowFormatOutput_WithError(t *testing.T) {
+	w := new(bytes.Buffer)
+	var errs []error
+	onError := func(err error) { errs = append(errs, err) }
+
+	o := New("test")
+	o.SetFormatOutput(NewShadowFormatOutput(
+		NewFormatOutput(DefaultTextFormatter(), w),
+		FormatOutputFunc(func(Entity, *bytes.Buffer) (io.Writer, error) { return nil, errors.New("test") }),
+		onError,
+	))
+	o.Info("test")
+
+	o.SetFormatOutput(NewShadowFormatOutput(
+		NewFormatOutput(DefaultTextFormatter(), w),
+		FormatOutputFunc(func(Entity, *bytes.Buffer) (io.Writer, error) { return nil, nil }),
+		onError,
+	))
+	o.Info("test")
+
+	o.SetFormatOutput(NewShadowFormatOutput(
+		NewFormatOutput(DefaultTextFormatter(), w),
+		NewFormatOutput(DefaultJSONFormatter(), testErrorWriter("test")),
+		onError,
+	))
+	o.Info("test")
+
+	if len(errs) != 3 {
+		t.Fatalf("ShadowFormatOutput: errors %v", errs)
+	}
+	// The primary logs are always written.
+	if got := strings.Count(w.String(), "\n"); got != 3 {
+		t.Fatalf("ShadowFormatOutput: %q", w.String())
+	}
+}
+
+func TestFanOutFormatOutput(t *testing.T) {
+	w1 := new(bytes.Buffer)
+	w2 := new(bytes.Buffer)
+	w3 := new(bytes.Buffer)
+	f := NewFanOutFormatOutput(
+		NewFormatOutput(NewConsoleFormatter(), w1),
+		NewFormatOutput(DefaultJSONFormatter(), w2),
+		NewFormatOutput(DefaultTextFormatter(), w3),
+		NewFormatOutput(DefaultTextFormatter(), testErrorWriter("test")),
+	)
+	if f == nil {
+		t.Fatal("NewFanOutFormatOutput(): nil")
+	}
+
+	var summaries []string
+	o := New("test")
+	o.SetFormatOutput(f)
+	o.SetDefaultTimeFormat("test")
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		summaries = append(summaries, s.String())
+		return nil
+	})
+
+	o.Info("test")
+
+	if got := w1.String(); !strings.Contains(got, "test [test]") {
+		t.Fatalf("FanOutFormatOutput: %q", got)
+	}
+	if got, want := w2.String(), `{"level":"info","message":"test","name":"test","time":"test"}`+"\n"; got != want {
+		t.Fatalf("FanOutFormatOutput: want %q, got %q", want, got)
+	}
+	if got, want := w3.String(), "test:[test][INF] test\n"; got != want {
+		t.Fatalf("FanOutFormatOutput: want %q, got %q", want, got)
+	}
+	// The hooks receive the log formatted by the primary format output.
+	if len(summaries) != 1 || summaries[0] != w1.String() {
+		t.Fatalf("FanOutFormatOutput: %q", summaries)
+	}
+}
+
+type testOrderWriter struct {
+	name    string
+	records *[]string
+}
+
+func (w testOrderWriter) Write(p []byte) (int, error) {
+	*w.records = append(*w.records, w.name+"="+string(p))
+	return len(p), nil
+}
+
+func TestFanOutFormatOutput_Record(t *testing.T) {
+	var records []string
+	o := New("test")
+	o.SetFormatOutput(NewFanOutFormatOutput(
+		NewFormatOutput(DefaultTextFormatter(), testOrderWriter{"primary", &records}),
+		NewFormatOutput(DefaultTextFormatter(), testOrderWriter{"other", &records}),
+	))
+	o.SetDefaultTimeFormat("test")
+	o.SetMaxRecordSize(40)
+	o.SetRecordTerminator("\r\n")
+
+	o.Info(strings.Repeat("x", 100))
+
+	want := "test:[test][INF] " + strings.Repeat("x", 8) + truncatedMarker + "\r\n"
+	if len(records) != 2 || records[0] != "primary="+want || records[1] != "other="+want {
+		t.Fatalf("FanOutFormatOutput: want %q, got %q", want, records)
+	}
+	if got := o.GetTruncatedCount(); got != 1 {
+		t.Fatalf("FanOutFormatOutput: truncated %d", got)
+	}
+}
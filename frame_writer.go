@@ -0,0 +1,65 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// NewFrameWriter creates a writer that frames each written log with a 4-byte big-endian
+// length prefix, which makes it possible to transport binary log formats (such as MessagePack
+// or protobuf) over stream connections without delimiter ambiguity.
+// The magic parameter is an optional fixed header (such as magic number and version) written
+// before the length prefix of each frame, and it can be nil.
+// Each frame is written to the underlying writer with a single write call.
+func NewFrameWriter(w io.Writer, magic []byte) io.Writer {
+	var header []byte
+	if len(magic) > 0 {
+		header = make([]byte, len(magic))
+		copy(header, magic)
+	}
+	return &frameWriter{w: w, magic: header}
+}
+
+// This writer frames each written log with a length prefix.
+type frameWriter struct {
+	w     io.Writer
+	magic []byte
+}
+
+// Write is the implementation of io.Writer interface.
+// The returned number of bytes is relative to the given data, and it is 0 if the frame
+// is not completely written.
+func (w *frameWriter) Write(p []byte) (int, error) {
+	if uint64(len(p)) > math.MaxUint32 {
+		return 0, fmt.Errorf("frame size %d exceeds limit", len(p))
+	}
+	n := len(w.magic)
+	b := make([]byte, n+4+len(p))
+	copy(b, w.magic)
+	binary.BigEndian.PutUint32(b[n:], uint32(len(p)))
+	copy(b[n+4:], p)
+	k, err := w.w.Write(b)
+	if err == nil && k != len(b) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewFrameWriter(t *testing.T) {
+	if NewFrameWriter(new(bytes.Buffer), nil) == nil {
+		t.Fatal("NewFrameWriter(): return nil")
+	}
+}
+
+func TestFrameWriter_Write(t *testing.T) {
+	b := new(bytes.Buffer)
+	w := NewFrameWriter(b, nil)
+
+	if n, err := w.Write([]byte("foo")); err != nil || n != 3 {
+		t.Fatalf("FrameWriter.Write(): %d %v", n, err)
+	}
+	if got, want := b.Bytes(), []byte{0, 0, 0, 3, 'f', 'o', 'o'}; !bytes.Equal(got, want) {
+		t.Fatalf("FrameWriter.Write(): want %v, got %v", want, got)
+	}
+
+	b.Reset()
+	w = NewFrameWriter(b, []byte{0xAB, 1})
+	if n, err := w.Write(nil); err != nil || n != 0 {
+		t.Fatalf("FrameWriter.Write(): %d %v", n, err)
+	}
+	if got, want := b.Bytes(), []byte{0xAB, 1, 0, 0, 0, 0}; !bytes.Equal(got, want) {
+		t.Fatalf("FrameWriter.Write(): want %v, got %v", want, got)
+	}
+
+	w = NewFrameWriter(testErrorWriter("test"), nil)
+	if n, err := w.Write([]byte("foo")); err == nil || n != 0 {
+		t.Fatalf("FrameWriter.Write(): %d %v", n, err)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// GELFFormatterOptions defines the options of the GELF formatter.
+type GELFFormatterOptions struct {
+	// Host is the host of the GELF messages, the default is the host name of the system.
+	Host string
+
+	// NullDelimiter indicates whether the GELF messages are terminated by the null byte
+	// instead of the line break, which is required by the GELF TCP inputs.
+	NullDelimiter bool
+}
+
+// NewGELFFormatter creates and returns an instance of the GELF (Graylog Extended Log Format)
+// formatter. The first line of the log message is the "short_message", and the complete
+// message with the stack is the "full_message" if it differs from the short message.
+// The log levels are mapped to the syslog severities by the Level.Severity, and the log
+// name, caller, id and fields are the additional fields prefixed with an underscore. The
+// nested map fields are flattened, such as "_a_b", and the field values other than the
+// numbers are converted to strings.
+func NewGELFFormatter(opts GELFFormatterOptions) Formatter {
+	f := &gelfFormatter{host: opts.Host, null: opts.NullDelimiter}
+	if f.host == "" {
+		if f.host, _ = os.Hostname(); f.host == "" {
+			f.host = "localhost"
+		}
+	}
+	return f
+}
+
+// The gelfFormatter type is the built-in GELF formatter.
+type gelfFormatter struct {
+	host string
+	null bool
+}
+
+// Format formats the given log entity into character data and writes it to the given buffer.
+func (f *gelfFormatter) Format(e Entity, b *bytes.Buffer) error {
+	message := e.Message()
+	short := message
+	if i := strings.IndexByte(short, '\n'); i >= 0 {
+		short = short[:i]
+	}
+	kv := map[string]interface{}{
+		"version":       "1.1",
+		"host":          f.host,
+		"short_message": short,
+		"timestamp":     float64(e.Time().UnixMicro()) / 1e6,
+		"level":         e.Level().Severity(),
+	}
+	full := message
+	if stack := e.Stack(); len(stack) > 0 {
+		full += "\n" + strings.Join(stack, "\n")
+	}
+	if full != short {
+		kv["full_message"] = full
+	}
+	// The short message is required by GELF.
+	if short == "" {
+		kv["short_message"] = "-"
+	}
+	flattenGELFFields(kv, "", e.Fields())
+	if name := e.Name(); name != "" {
+		kv["_logger"] = name
+	}
+	if caller := e.Caller(); caller != "" {
+		kv["_caller"] = caller
+	}
+	if id := e.ID(); id != "" {
+		kv["_record_id"] = id
+	}
+	// The json.Encoder.Encode method automatically adds line breaks.
+	if err := json.NewEncoder(b).Encode(kv); err != nil {
+		return err
+	}
+	if f.null {
+		b.Bytes()[b.Len()-1] = 0
+	}
+	return nil
+}
+
+// Adds the given fields to the given GELF message as the additional fields.
+func flattenGELFFields(kv map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for k, v := range fields {
+		key := prefix + "_" + sanitizeGELFKey(k)
+		switch o := v.(type) {
+		case map[string]interface{}:
+			flattenGELFFields(kv, key, o)
+			continue
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		default:
+			v = internal.ToString(v)
+		}
+		// The "_id" field is reserved by GELF.
+		if key == "_id" {
+			key = "__id"
+		}
+		kv[key] = v
+	}
+}
+
+// Replaces the characters not allowed in the GELF field names with the underscores.
+func sanitizeGELFKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, k)
+}
+
+const (
+	// DefaultGELFChunkSize is the default maximum datagram size of the GELF chunk writer,
+	// which is suitable for the WAN networks.
+	DefaultGELFChunkSize = 1420
+
+	// The GELF chunk header size, the magic bytes, message id, sequence number and count.
+	gelfChunkHeaderSize = 12
+
+	// The maximum number of chunks of a GELF message.
+	gelfMaxChunks = 128
+)
+
+// ErrGELFMessageTooLarge is returned by the GELF chunk writer when the message needs more
+// than 128 chunks.
+var ErrGELFMessageTooLarge = errors.New("gelf message too large")
+
+// The GELF message id counter of the chunk writers.
+var gelfMessageID = uint64(time.Now().UnixNano())
+
+// NewGELFChunkWriter creates a writer that splits the GELF messages exceeding the given
+// maximum datagram size into the GELF chunks, this writer is usually used to wrap the UDP
+// connections of the GELF UDP inputs. If the size parameter is less than or equal to the
+// chunk header size, DefaultGELFChunkSize is used.
+func NewGELFChunkWriter(w io.Writer, size int) io.Writer {
+	if size <= gelfChunkHeaderSize {
+		size = DefaultGELFChunkSize
+	}
+	return &gelfChunkWriter{w: w, size: size}
+}
+
+// The gelfChunkWriter type is the built-in GELF chunk writer.
+type gelfChunkWriter struct {
+	w    io.Writer
+	size int
+}
+
+// Write is the implementation of io.Writer interface.
+// The returned number of bytes is relative to the given data.
+func (w *gelfChunkWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.size {
+		return w.w.Write(p)
+	}
+	chunk := w.size - gelfChunkHeaderSize
+	count := (len(p) + chunk - 1) / chunk
+	if count > gelfMaxChunks {
+		return 0, ErrGELFMessageTooLarge
+	}
+	buf := make([]byte, w.size)
+	buf[0], buf[1] = 0x1e, 0x0f
+	binary.BigEndian.PutUint64(buf[2:10], atomic.AddUint64(&gelfMessageID, 1))
+	buf[11] = byte(count)
+	for i := 0; i < count; i++ {
+		buf[10] = byte(i)
+		data := p[i*chunk:]
+		if len(data) > chunk {
+			data = data[:chunk]
+		}
+		n := copy(buf[gelfChunkHeaderSize:], data)
+		if _, err := w.w.Write(buf[:gelfChunkHeaderSize+n]); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
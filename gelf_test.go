@@ -0,0 +1,116 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGELFFormatter(t *testing.T) {
+	if host, _ := os.Hostname(); host != "" {
+		if got := NewGELFFormatter(GELFFormatterOptions{}).(*gelfFormatter).host; got != host {
+			t.Fatalf("NewGELFFormatter(): host %q", got)
+		}
+	}
+
+	e := &logEntity{
+		name: "test", level: WarnLevel, message: "foo\nbar", caller: "a.go:1", id: "1",
+		time: time.Unix(1700000000, 123456000), stack: []string{"s1", "s2"},
+		fields: map[string]interface{}{
+			"a": 1, "b c": true, "id": "x", "err": errors.New("e"),
+			"m": map[string]interface{}{"n": 1.5, "o": map[string]interface{}{"p": "q"}},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := NewGELFFormatter(GELFFormatterOptions{Host: "host"}).Format(e, buf); err != nil {
+		t.Fatalf("GELFFormatter.Format(): %s", err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("}\n")) {
+		t.Fatalf("GELFFormatter.Format(): %q", buf.String())
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("GELFFormatter.Format(): %s", err)
+	}
+	want := map[string]interface{}{
+		"version": "1.1", "host": "host", "short_message": "foo", "full_message": "foo\nbar\ns1\ns2",
+		"timestamp": 1700000000.123456, "level": float64(4), "_logger": "test", "_caller": "a.go:1",
+		"_record_id": "1", "_a": float64(1), "_b_c": "true", "__id": "x", "_err": "e", "_m_n": 1.5, "_m_o_p": "q",
+	}
+	if len(m) != len(want) {
+		t.Fatalf("GELFFormatter.Format(): %v", m)
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Fatalf("GELFFormatter.Format(): %s: want %v, got %v", k, v, m[k])
+		}
+	}
+
+	buf.Reset()
+	f := NewGELFFormatter(GELFFormatterOptions{Host: "host", NullDelimiter: true})
+	if err := f.Format(&logEntity{level: InfoLevel}, buf); err != nil {
+		t.Fatalf("GELFFormatter.Format(): %s", err)
+	}
+	if got := buf.String(); !strings.HasSuffix(got, "}\x00") || !strings.Contains(got, `"short_message":"-"`) || strings.Contains(got, "full_message") {
+		t.Fatalf("GELFFormatter.Format(): %q", got)
+	}
+}
+
+func TestGELFChunkWriter_Write(t *testing.T) {
+	cw := new(testChunkWriter)
+	w := NewGELFChunkWriter(cw, 0)
+	if w.(*gelfChunkWriter).size != DefaultGELFChunkSize {
+		t.Fatal("NewGELFChunkWriter(): default size")
+	}
+
+	w = NewGELFChunkWriter(cw, 16)
+	if n, err := w.Write([]byte("small")); err != nil || n != 5 {
+		t.Fatalf("GELFChunkWriter.Write(): %d %v", n, err)
+	}
+	if n, err := w.Write([]byte("0123456789abcdefghij")); err != nil || n != 20 {
+		t.Fatalf("GELFChunkWriter.Write(): %d %v", n, err)
+	}
+	if len(cw.chunks) != 6 || cw.chunks[0] != "small" {
+		t.Fatalf("GELFChunkWriter.Write(): %q", cw.chunks)
+	}
+	id := binary.BigEndian.Uint64([]byte(cw.chunks[1][2:10]))
+	var data string
+	for i, chunk := range cw.chunks[1:] {
+		if chunk[0] != 0x1e || chunk[1] != 0x0f || chunk[10] != byte(i) || chunk[11] != 5 {
+			t.Fatalf("GELFChunkWriter.Write(): header %q", chunk)
+		}
+		if binary.BigEndian.Uint64([]byte(chunk[2:10])) != id {
+			t.Fatalf("GELFChunkWriter.Write(): id %q", chunk)
+		}
+		data += chunk[12:]
+	}
+	if data != "0123456789abcdefghij" {
+		t.Fatalf("GELFChunkWriter.Write(): %q", data)
+	}
+
+	if _, err := w.Write(make([]byte, 4*129)); err != ErrGELFMessageTooLarge {
+		t.Fatalf("GELFChunkWriter.Write(): %v", err)
+	}
+	if _, err := NewGELFChunkWriter(testErrorWriter("test"), 16).Write(make([]byte, 20)); err == nil {
+		t.Fatal("GELFChunkWriter.Write(): nil error")
+	}
+}
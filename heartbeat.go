@@ -0,0 +1,82 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// The time when the current process (this package) was started.
+var processStartTime = time.Now()
+
+// DefaultHeartbeatInterval is the default interval of the heartbeat logs.
+const DefaultHeartbeatInterval = time.Minute
+
+// HeartbeatOptions defines the options of the heartbeat logs.
+type HeartbeatOptions struct {
+	// Interval is the interval of the heartbeat logs.
+	// If it is less than or equal to 0, DefaultHeartbeatInterval is used.
+	Interval time.Duration
+
+	// Message is the message of the heartbeat logs, the default message is "alive".
+	Message string
+
+	// Level is the level of the heartbeat logs, the default level is InfoLevel.
+	Level Level
+
+	// Stats returns the additional fields of the heartbeat logs, it can be nil.
+	Stats func() map[string]interface{}
+}
+
+// RunHeartbeat records the heartbeat logs periodically until the given context is done,
+// it blocks the current goroutine, so it is usually called in a separate goroutine.
+// The heartbeat logs carry the "uptime" (the elapsed time since the process was started)
+// and "goroutines" fields, and the fields returned by the HeartbeatOptions.Stats.
+func RunHeartbeat(ctx context.Context, l Log, opts HeartbeatOptions) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultHeartbeatInterval
+	}
+	if opts.Message == "" {
+		opts.Message = "alive"
+	}
+	if !opts.Level.IsValid() {
+		opts.Level = InfoLevel
+	}
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			heartbeat(l, opts)
+		}
+	}
+}
+
+// Records a single heartbeat log.
+func heartbeat(l Log, opts HeartbeatOptions) {
+	fields := map[string]interface{}{
+		"uptime": time.Since(processStartTime), "goroutines": runtime.NumGoroutine(),
+	}
+	if opts.Stats != nil {
+		for k, v := range opts.Stats() {
+			fields[k] = v
+		}
+	}
+	l.WithFields(fields).Log(opts.Level, opts.Message)
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunHeartbeat(t *testing.T) {
+	o := New("test").SetOutput(io.Discard)
+	var mu sync.Mutex
+	var summaries []Summary
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		mu.Lock()
+		summaries = append(summaries, s.Clone())
+		mu.Unlock()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*55)
+	defer cancel()
+	RunHeartbeat(ctx, o, HeartbeatOptions{
+		Interval: time.Millisecond * 10,
+		Stats:    func() map[string]interface{} { return map[string]interface{}{"jobs": 1} },
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(summaries) < 2 {
+		t.Fatalf("RunHeartbeat(): %d logs", len(summaries))
+	}
+	s := summaries[0]
+	if s.Message() != "alive" || s.Level() != InfoLevel {
+		t.Fatalf("RunHeartbeat(): %s %s", s.Level(), s.Message())
+	}
+	fields := s.Fields()
+	if _, ok := fields["uptime"].(time.Duration); !ok || fields["goroutines"] == nil || fields["jobs"] != 1 {
+		t.Fatalf("RunHeartbeat(): %v", fields)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 )
 
 // KnownCallerDepth is the internally known call stack depth.
@@ -59,3 +60,37 @@ func GetCaller(skipped int, long bool) string {
 	}
 	return "???:0"
 }
+
+// GetCallerSkipping is like GetCaller, but it walks the call stack upward from the given depth
+// until it leaves the functions whose names start with any of the given prefixes, such as the
+// package paths of the logger wrappers.
+func GetCallerSkipping(skipped int, long bool, prefixes []string) string {
+	var pcs [32]uintptr
+	// The runtime.Callers skips one more frame than the runtime.Caller.
+	n := runtime.Callers(skipped+KnownCallerDepth+1, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.PC == 0 {
+			break
+		}
+		if !hasFunctionPrefix(frame.Function, prefixes) || !more {
+			if base := filepath.Base(frame.File); long {
+				return filepath.Join(filepath.Base(filepath.Dir(frame.File)), base) + ":" + strconv.Itoa(frame.Line)
+			} else {
+				return base + ":" + strconv.Itoa(frame.Line)
+			}
+		}
+	}
+	return "???:0"
+}
+
+// Determines whether the given function name starts with any of the given prefixes.
+func hasFunctionPrefix(name string, prefixes []string) bool {
+	for i, j := 0, len(prefixes); i < j; i++ {
+		if strings.HasPrefix(name, prefixes[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -22,6 +22,8 @@ const (
 	ERR = "\u001B[95m" // hi-intensity red
 	FAT = "\u001B[31m" // magenta
 	PNC = "\u001B[91m" // hi-intensity magenta
+	KEY = "\u001B[94m" // hi-intensity blue
+	RST = "\u001B[0m"  // reset
 )
 
 // Colorful wraps strings with the given color and appends to the end of the slice.
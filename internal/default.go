@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -53,6 +55,126 @@ var (
 )
 
 // EchoError writes the given logger internal error message to ErrorWriter.
+// When the error throttle is enabled by SetErrorThrottle, the identical messages are written
+// at most once per throttle interval, and the number of the suppressed messages is written
+// with the message when the throttle interval ends.
 func EchoError(format string, args ...interface{}) {
-	_, _ = fmt.Fprintln(ErrorWriter, fmt.Sprintf(format, args...))
+	msg := fmt.Sprintf(format, args...)
+	if d := time.Duration(atomic.LoadInt64(&errorThrottle)); d > 0 {
+		var ok bool
+		if msg, ok = throttleError(msg, d); !ok {
+			return
+		}
+	}
+	_, _ = fmt.Fprintln(ErrorWriter, msg)
+}
+
+// The maximum number of the distinct messages tracked by the error throttle.
+const maxThrottledErrors = 1024
+
+var (
+	errorThrottle int64 // The throttle interval of the identical internal error messages.
+	errorMutex    sync.Mutex
+	errorRecords  map[string]*errorRecord
+)
+
+// The errorRecord type records the state of a throttled internal error message.
+type errorRecord struct {
+	last       time.Time
+	suppressed uint64
+	gen        uint64
+	timer      *time.Timer // Writes the suppressed messages when the throttle interval ends.
+}
+
+// SetErrorThrottle sets the minimum interval between the identical internal error messages.
+// The pending suppressed messages of the previous throttle are written immediately.
+// If the given interval is less than or equal to 0, the error throttle is disabled.
+func SetErrorThrottle(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	errorMutex.Lock()
+	defer errorMutex.Unlock()
+	for msg, r := range errorRecords {
+		writeSuppressedError(msg, r)
+	}
+	atomic.StoreInt64(&errorThrottle, int64(d))
+	errorRecords = nil
+}
+
+// Determines whether the given internal error message should be written, and returns the
+// message with the number of the suppressed identical messages.
+func throttleError(msg string, d time.Duration) (string, bool) {
+	errorMutex.Lock()
+	defer errorMutex.Unlock()
+
+	now := time.Now()
+	if r, found := errorRecords[msg]; found {
+		if now.Sub(r.last) < d {
+			if r.suppressed == 0 {
+				gen := r.gen
+				r.timer = time.AfterFunc(d-now.Sub(r.last), func() { expireError(msg, r, gen) })
+			}
+			r.suppressed++
+			return "", false
+		}
+		n := takeSuppressedError(r)
+		r.last = now
+		if n > 0 {
+			return suppressedError(msg, n), true
+		}
+		return msg, true
+	}
+	if errorRecords == nil {
+		errorRecords = make(map[string]*errorRecord)
+	} else if len(errorRecords) >= maxThrottledErrors {
+		// The timers of the removed records still write their suppressed messages.
+		for k, r := range errorRecords {
+			if now.Sub(r.last) >= d {
+				delete(errorRecords, k)
+			}
+		}
+		if len(errorRecords) >= maxThrottledErrors {
+			errorRecords = make(map[string]*errorRecord)
+		}
+	}
+	errorRecords[msg] = &errorRecord{last: now}
+	return msg, true
+}
+
+// Writes the suppressed messages of the given record when the throttle interval of the
+// given generation ends.
+func expireError(msg string, r *errorRecord, gen uint64) {
+	errorMutex.Lock()
+	defer errorMutex.Unlock()
+	if r.gen == gen {
+		r.last = time.Now()
+		writeSuppressedError(msg, r)
+	}
+}
+
+// Writes the suppressed messages of the given record if there are any.
+// This function must be called with the lock held.
+func writeSuppressedError(msg string, r *errorRecord) {
+	if n := takeSuppressedError(r); n > 0 {
+		_, _ = fmt.Fprintln(ErrorWriter, suppressedError(msg, n))
+	}
+}
+
+// Takes the number of the suppressed messages of the given record and stops its timer.
+// This function must be called with the lock held.
+func takeSuppressedError(r *errorRecord) uint64 {
+	n := r.suppressed
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	r.suppressed = 0
+	r.gen++
+	return n
+}
+
+// Returns the given message with the number of the suppressed identical messages.
+func suppressedError(msg string, n uint64) string {
+	return fmt.Sprintf("%s (suppressed %d identical messages)", msg, n)
 }
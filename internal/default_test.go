@@ -17,7 +17,9 @@ package internal
 import (
 	"bytes"
 	"os"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestEmptyExitFunc(t *testing.T) {
@@ -63,3 +65,83 @@ func TestEchoError(t *testing.T) {
 		t.Fatalf("EchoError(): got %q", got)
 	}
 }
+
+type testSyncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *testSyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *testSyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *testSyncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func TestSetErrorThrottle(t *testing.T) {
+	defer func() {
+		ErrorWriter = os.Stderr
+		SetErrorThrottle(0)
+	}()
+
+	buf := new(testSyncBuffer)
+	ErrorWriter = buf
+
+	SetErrorThrottle(time.Millisecond * 50)
+	for i := 0; i < 3; i++ {
+		EchoError("test-%d", 1)
+	}
+	EchoError("test-%d", 2)
+	if got := buf.String(); got != "test-1\ntest-2\n" {
+		t.Fatalf("EchoError(): got %q", got)
+	}
+
+	// The suppressed messages are written when the throttle interval ends, even if the
+	// message is not repeated.
+	time.Sleep(time.Millisecond * 80)
+	EchoError("test-%d", 3)
+	if got := buf.String(); got != "test-1\ntest-2\ntest-1 (suppressed 2 identical messages)\ntest-3\n" {
+		t.Fatalf("EchoError(): got %q", got)
+	}
+
+	// The pending suppressed messages are written when the throttle is changed.
+	buf.Reset()
+	EchoError("test-%d", 3)
+	SetErrorThrottle(-1)
+	if got := buf.String(); got != "test-3 (suppressed 1 identical messages)\n" {
+		t.Fatalf("SetErrorThrottle(): got %q", got)
+	}
+
+	buf.Reset()
+	EchoError("test-%d", 1)
+	EchoError("test-%d", 1)
+	if got := buf.String(); got != "test-1\ntest-1\n" {
+		t.Fatalf("EchoError(): got %q", got)
+	}
+}
+
+func TestThrottleError_Limit(t *testing.T) {
+	defer SetErrorThrottle(0)
+
+	SetErrorThrottle(time.Hour)
+	for i := 0; i < maxThrottledErrors; i++ {
+		throttleError(string(rune(i)), time.Hour)
+	}
+	if _, ok := throttleError("test", time.Hour); !ok {
+		t.Fatal("throttleError(): false")
+	}
+	if len(errorRecords) != 1 {
+		t.Fatalf("throttleError(): %d records", len(errorRecords))
+	}
+}
@@ -15,6 +15,8 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
@@ -89,6 +91,110 @@ func FormatFieldsToText(src map[string]interface{}) string {
 	return strings.Join(texts, ", ")
 }
 
+// FormatFieldsToQuotedText is like FormatFieldsToText, but the field values are quoted.
+func FormatFieldsToQuotedText(src map[string]interface{}) string {
+	texts := make([]string, 0, len(src))
+	for k, v := range src {
+		texts = append(texts, k+"="+strconv.Quote(ToString(v)))
+	}
+	// Ensure that the order of log extension fields is consistent.
+	if len(texts) > 1 {
+		sort.Strings(texts)
+	}
+	return strings.Join(texts, ", ")
+}
+
+// IndentLines indents the continuation lines of the given string with the given indent,
+// the trailing line break is kept as is.
+func IndentLines(s, indent string) string {
+	if indent == "" || !strings.Contains(s, "\n") {
+		return s
+	}
+	if strings.HasSuffix(s, "\n") {
+		return strings.ReplaceAll(s[:len(s)-1], "\n", "\n"+indent) + "\n"
+	}
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// IsNestedValue determines whether the given value is a map or a struct (or a pointer to
+// them), the values implementing the error or fmt.Stringer interface are not nested values.
+func IsNestedValue(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	switch value.(type) {
+	case error, fmt.Stringer:
+		return false
+	}
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Map || t.Kind() == reflect.Struct
+}
+
+// InlineNestedFields returns the fields with the nested values converted to the inline JSON
+// strings, the values that can not be encoded are kept as is.
+func InlineNestedFields(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+	for k, v := range src {
+		if IsNestedValue(v) {
+			if b, err := json.Marshal(v); err == nil {
+				dst[k] = string(b)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// ExpandNestedFields returns the fields with the nested values expanded to the nested key
+// paths, such as {"a": {"b": "c"}} is expanded to {"a.b": "c"}. The struct values are
+// expanded by their JSON encoding, and the values that can not be encoded are kept as is.
+func ExpandNestedFields(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+	for k, v := range src {
+		expandNestedValue(dst, k, v)
+	}
+	return dst
+}
+
+// Expands the given value with the given key path into the given fields.
+func expandNestedValue(dst map[string]interface{}, key string, value interface{}) {
+	if !IsNestedValue(value) {
+		dst[key] = value
+		return
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		b, err := json.Marshal(value)
+		if err != nil {
+			dst[key] = value
+			return
+		}
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		var v interface{}
+		if err = d.Decode(&v); err != nil {
+			dst[key] = value
+			return
+		}
+		if m, ok = v.(map[string]interface{}); !ok {
+			// Such as the nil pointers.
+			dst[key] = v
+			return
+		}
+	}
+	if len(m) == 0 {
+		dst[key] = "{}"
+		return
+	}
+	for k, v := range m {
+		expandNestedValue(dst, key+"."+k, v)
+	}
+}
+
 // FormatPairsToFields standardizes the given pairs to fields.
 func FormatPairsToFields(pairs []interface{}) map[string]interface{} {
 	fields := make(map[string]interface{}, len(pairs)/2)
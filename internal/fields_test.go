@@ -175,6 +175,98 @@ func TestFormatFieldsToText(t *testing.T) {
 	}
 }
 
+func TestFormatFieldsToQuotedText(t *testing.T) {
+	want := `bar="bar, baz", foo="1"`
+	got := FormatFieldsToQuotedText(map[string]interface{}{
+		"foo": 1,
+		"bar": "bar, baz",
+	})
+	if want != got {
+		t.Fatalf("FormatFieldsToQuotedText(): want %q, got %q", want, got)
+	}
+}
+
+func TestIndentLines(t *testing.T) {
+	items := [][3]string{
+		{"foo", "  ", "foo"},
+		{"foo\nbar", "", "foo\nbar"},
+		{"foo\nbar\nbaz", "  ", "foo\n  bar\n  baz"},
+		{"foo\nbar\n", "\t", "foo\n\tbar\n"},
+	}
+	for _, item := range items {
+		if got := IndentLines(item[0], item[1]); got != item[2] {
+			t.Fatalf("IndentLines(): want %q, got %q", item[2], got)
+		}
+	}
+}
+
+type testNestedStruct struct {
+	A string            `json:"a"`
+	B map[string]string `json:"b"`
+}
+
+func TestIsNestedValue(t *testing.T) {
+	items := []struct {
+		Value interface{}
+		Want  bool
+	}{
+		{nil, false},
+		{1, false},
+		{"a", false},
+		{[]int{1}, false},
+		{errors.New("e"), false},
+		{&testStringer{v: "a"}, false},
+		{map[string]int{}, true},
+		{testNestedStruct{}, true},
+		{&testNestedStruct{}, true},
+	}
+	for _, item := range items {
+		if got := IsNestedValue(item.Value); got != item.Want {
+			t.Fatalf("IsNestedValue(%v): want %v, got %v", item.Value, item.Want, got)
+		}
+	}
+}
+
+func TestInlineNestedFields(t *testing.T) {
+	got := InlineNestedFields(map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": "d"},
+		"e": testNestedStruct{A: "x"},
+		"f": map[string]interface{}{"g": func() {}},
+	})
+	if got["a"] != 1 || got["b"] != `{"c":"d"}` || got["e"] != `{"a":"x","b":null}` {
+		t.Fatalf("InlineNestedFields(): %v", got)
+	}
+	if _, ok := got["f"].(map[string]interface{}); !ok {
+		t.Fatalf("InlineNestedFields(): %v", got)
+	}
+}
+
+func TestExpandNestedFields(t *testing.T) {
+	got := ExpandNestedFields(map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": "d", "e": map[string]interface{}{"f": 2}},
+		"g": &testNestedStruct{A: "x", B: map[string]string{"y": "z"}},
+		"h": map[string]interface{}{},
+		"i": (*testNestedStruct)(nil),
+		"j": map[string]interface{}{"k": func() {}},
+	})
+	want := map[string]interface{}{
+		"a": 1, "b.c": "d", "b.e.f": 2, "g.a": "x", "g.b.y": "z", "h": "{}", "i": nil,
+	}
+	if len(got) != len(want)+1 {
+		t.Fatalf("ExpandNestedFields(): %v", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("ExpandNestedFields(): %s: want %v, got %v", k, v, got[k])
+		}
+	}
+	if _, ok := got["j.k"].(func()); !ok {
+		t.Fatalf("ExpandNestedFields(): %v", got)
+	}
+}
+
 func TestFormatPairsToFields(t *testing.T) {
 	got := FormatPairsToFields([]interface{}{
 		"foo", "test",
@@ -0,0 +1,31 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// SetInternalErrorThrottle sets the minimum interval between the identical logger internal
+// error messages (such as the write failures of a broken log writer), which are written to
+// the standard error output. The identical messages are written at most once per interval,
+// and the number of the suppressed messages is written with the message when the interval
+// ends. The pending suppressed messages are written immediately when the throttle is changed.
+// If the given interval is less than or equal to 0, the throttle is disabled (the default).
+func SetInternalErrorThrottle(d time.Duration) {
+	internal.SetErrorThrottle(d)
+}
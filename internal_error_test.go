@@ -0,0 +1,44 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestSetInternalErrorThrottle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	internal.ErrorWriter = buf
+	defer func() {
+		SetInternalErrorThrottle(0)
+		internal.ErrorWriter = os.Stderr
+	}()
+
+	o := New("test")
+	o.SetOutput(testErrorWriter("test"))
+	SetInternalErrorThrottle(time.Hour)
+	for i := 0; i < 10; i++ {
+		o.Info("test")
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Fatalf("SetInternalErrorThrottle(): %q", buf.String())
+	}
+}
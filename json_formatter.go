@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
 
 	"github.com/edoger/zkits-logger/internal"
@@ -40,33 +42,141 @@ func NewJSONFormatterFromPool(p JSONFormatterObjectPool) Formatter {
 // The keys parameter is used to modify the default json field name.
 // If the full parameter is true, it will always ensure that all fields exist in the top-level json object.
 func NewJSONFormatter(keys map[string]string, full bool) (Formatter, error) {
-	if len(keys) > 0 {
-		structure := true
-		mapping := map[string]string{
-			"name": "name", "time": "time", "level": "level", "message": "message",
-			"fields": "fields", "caller": "caller", "stack": "stack",
-		}
-		for key, value := range keys {
-			if mapping[key] == "" {
-				// We require that the key-name map must be pure.
-				return nil, fmt.Errorf("invalid json formatter key %q", key)
-			}
-			// We ignore the case where all fields are mapped as empty, which is more practical.
-			if value != "" && mapping[key] != value {
-				structure = false
-				mapping[key] = value
-			}
+	return NewJSONFormatterWithOptions(JSONFormatterOptions{Keys: keys, Full: full})
+}
+
+// JSONFieldCollision defines how the flattened log fields that collide with the built-in
+// keys of the json object are handled.
+type JSONFieldCollision int
+
+const (
+	// JSONFieldCollisionPrefix indicates that the colliding field is renamed by prefixing
+	// the key with the fields key and a dot, such as "fields.level".
+	JSONFieldCollisionPrefix JSONFieldCollision = iota
+
+	// JSONFieldCollisionOverwrite indicates that the colliding field overwrites the built-in key.
+	JSONFieldCollisionOverwrite
+
+	// JSONFieldCollisionDiscard indicates that the colliding field is discarded.
+	JSONFieldCollisionDiscard
+)
+
+// JSONTimeEncoding defines how the log time is encoded in the json object.
+type JSONTimeEncoding int
+
+const (
+	// JSONTimeString indicates that the log time is encoded as a formatted string.
+	JSONTimeString JSONTimeEncoding = iota
+
+	// JSONTimeUnix indicates that the log time is encoded as a number of unix seconds.
+	JSONTimeUnix
+
+	// JSONTimeUnixMilli indicates that the log time is encoded as a number of unix milliseconds.
+	JSONTimeUnixMilli
+
+	// JSONTimeUnixMicro indicates that the log time is encoded as a number of unix microseconds.
+	JSONTimeUnixMicro
+)
+
+// JSONLevelNumber defines how the log level is encoded as a number in the json object.
+type JSONLevelNumber int
+
+const (
+	// JSONLevelNone indicates that the log level is not encoded as a number.
+	JSONLevelNone JSONLevelNumber = iota
+
+	// JSONLevelOrdinal indicates that the log level is encoded as its internal ordering,
+	// from 1 (panic) to 7 (trace).
+	JSONLevelOrdinal
+
+	// JSONLevelSeverity indicates that the log level is encoded as its syslog severity,
+	// see Level.Severity.
+	JSONLevelSeverity
+)
+
+// JSONFormatterOptions defines the options of the json formatter.
+type JSONFormatterOptions struct {
+	// Keys is used to modify the default json field name, see NewJSONFormatter.
+	Keys map[string]string
+
+	// Full indicates whether to always ensure that all fields exist in the top-level json object.
+	Full bool
+
+	// FlattenFields indicates whether to merge the log fields into the top-level json object
+	// instead of nesting them under the fields key, which is expected by many log ingestion
+	// schemas. The fields colliding with the built-in keys are handled by FieldCollision.
+	FlattenFields bool
+
+	// FieldCollision determines how the flattened fields colliding with the built-in keys
+	// are handled, by default, the colliding fields are renamed with a prefix.
+	FieldCollision JSONFieldCollision
+
+	// TimeFormat is the time layout used by this formatter, independent of the default
+	// time format of the logger, so that different outputs can use different layouts.
+	// If it is empty, the default time format of the logger is used.
+	TimeFormat string
+
+	// TimeEncoding determines how the log time is encoded, by default, the log time is
+	// encoded as a string. The TimeFormat is ignored when the time is encoded as a number.
+	TimeEncoding JSONTimeEncoding
+
+	// LevelNumber determines whether and how the log level is encoded as a number.
+	LevelNumber JSONLevelNumber
+
+	// LevelNumberKey is the key of the numeric log level in the json object.
+	// If it is empty, the numeric level replaces the string level, otherwise,
+	// the numeric level is added alongside the string level.
+	LevelNumberKey string
+
+	// LevelLabels overrides the strings emitted for the log levels, such as "warning"
+	// instead of "warn". It does not change the semantics of ParseLevel.
+	LevelLabels map[Level]string
+
+	// KeyOrder lists the keys of the top-level json object (the names after being modified
+	// by Keys) that are emitted first and in the given order, such as []string{"time", "level"}.
+	// The remaining keys are emitted after them in lexicographic order.
+	KeyOrder []string
+}
+
+// NewJSONFormatterWithOptions creates and returns an instance of the log json formatter
+// with the given options.
+func NewJSONFormatterWithOptions(opts JSONFormatterOptions) (Formatter, error) {
+	structure := true
+	mapping := map[string]string{
+		"name": "name", "time": "time", "level": "level", "message": "message",
+		"fields": "fields", "caller": "caller", "stack": "stack", "id": "id",
+	}
+	for key, value := range opts.Keys {
+		if mapping[key] == "" {
+			// We require that the key-name map must be pure.
+			return nil, fmt.Errorf("invalid json formatter key %q", key)
 		}
-		// when the json field cannot be predicted in advance, we use map to package the log data.
-		// is there a better solution to improve the efficiency of json serialization?
-		if !structure {
-			return NewJSONFormatterFromPool(newJSONFormatterMapPool(full, mapping)), nil
+		// We ignore the case where all fields are mapped as empty, which is more practical.
+		if value != "" && mapping[key] != value {
+			structure = false
+			mapping[key] = value
 		}
 	}
+	// when the json field cannot be predicted in advance, we use map to package the log data.
+	// is there a better solution to improve the efficiency of json serialization?
+	if !structure || opts.FlattenFields || opts.TimeFormat != "" || opts.TimeEncoding != JSONTimeString ||
+		opts.LevelNumber != JSONLevelNone || len(opts.LevelLabels) > 0 || len(opts.KeyOrder) > 0 {
+		return NewJSONFormatterFromPool(newJSONFormatterMapPool(opts, mapping)), nil
+	}
 	// In most cases, the performance of json serialization of structure is higher than
 	// that of json serialization of map. When the json field name has not changed, we
 	// try to use structure for json serialization.
-	return NewJSONFormatterFromPool(newJSONFormatterObjectPool(full)), nil
+	return NewJSONFormatterFromPool(newJSONFormatterObjectPool(opts.Full)), nil
+}
+
+// MustNewJSONFormatterWithOptions is like NewJSONFormatterWithOptions, but triggers a panic
+// when an error occurs.
+func MustNewJSONFormatterWithOptions(opts JSONFormatterOptions) Formatter {
+	f, err := NewJSONFormatterWithOptions(opts)
+	if err != nil {
+		panic(err)
+	}
+	return f
 }
 
 // MustNewJSONFormatter is like NewJSONFormatter, but triggers a panic when an error occurs.
@@ -104,41 +214,88 @@ func (f *jsonFormatter) Format(e Entity, b *bytes.Buffer) (err error) {
 	return
 }
 
+// FormatTo formats the given log entity into character data and writes it to the given writer.
+// The built-in json log objects are written to the writer member by member (the members of
+// the log fields are also written one by one), so the log is not buffered in full. The json
+// log objects of the custom object pools are encoded in full by the encoding/json package.
+func (f *jsonFormatter) FormatTo(e Entity, w io.Writer) error {
+	o := f.pool.GetObject(e)
+	defer f.pool.PutObject(o)
+
+	sw := &jsonStreamWriter{w: w}
+	switch v := o.(type) {
+	case map[string]interface{}:
+		sw.writeMap(v, nil)
+	case *jsonOrderedMap:
+		sw.writeMap(v.kv, v.order)
+	case *jsonFormatterObject:
+		sw.writeObject(v)
+	default:
+		return json.NewEncoder(w).Encode(o)
+	}
+	sw.write([]byte{'\n'})
+	return sw.err
+}
+
 // This is the built-in pool of serializable JSON map.
 type jsonFormatterMapPool struct {
-	full bool
+	full         bool
+	flatten      bool
+	collision    JSONFieldCollision
+	timeFormat   string
+	timeEncoding JSONTimeEncoding
+	levelNumber  JSONLevelNumber
+	levelKey     string
+	levelLabels  map[Level]string
+	order        []string
 	// These fields store the names of the keys in the json object.
-	name, time, level, message, fields, caller, stack string
+	name, time, level, message, fields, caller, stack, id string
 }
 
 // Creates and returns a new pool of serializable JSON map.
-func newJSONFormatterMapPool(full bool, keys map[string]string) JSONFormatterObjectPool {
+func newJSONFormatterMapPool(opts JSONFormatterOptions, keys map[string]string) JSONFormatterObjectPool {
 	return &jsonFormatterMapPool{
-		full: full, name: keys["name"], time: keys["time"], level: keys["level"],
-		message: keys["message"], fields: keys["fields"], caller: keys["caller"], stack: keys["stack"],
+		full: opts.Full, flatten: opts.FlattenFields, collision: opts.FieldCollision, timeFormat: opts.TimeFormat,
+		timeEncoding: opts.TimeEncoding, levelNumber: opts.LevelNumber, levelKey: opts.LevelNumberKey,
+		levelLabels: copyLevelLabels(opts.LevelLabels),
+		name:        keys["name"], time: keys["time"], level: keys["level"], message: keys["message"],
+		fields: keys["fields"], caller: keys["caller"], stack: keys["stack"], id: keys["id"],
+		order: copyKeyOrder(opts.KeyOrder),
 	}
 }
 
 // GetObject creates and returns a new JSON log map from the given log Entity.
 // This method is an implementation of the JSONFormatterObjectPool interface.
 func (p *jsonFormatterMapPool) GetObject(e Entity) interface{} {
-	kv := map[string]interface{}{p.level: e.Level().String(), p.message: e.Message()}
+	kv := map[string]interface{}{p.level: p.levelString(e.Level()), p.message: e.Message()}
+	if p.levelNumber != JSONLevelNone {
+		if p.levelKey == "" {
+			kv[p.level] = p.encodeLevel(e.Level())
+		} else {
+			kv[p.levelKey] = p.encodeLevel(e.Level())
+		}
+	}
 	if name := e.Name(); p.full || name != "" {
 		kv[p.name] = name
 	}
-	if tm := e.TimeString(); p.full || tm != "" {
-		kv[p.time] = tm
-	}
-	if fields := e.Fields(); len(fields) > 0 {
-		kv[p.fields] = internal.StandardiseFieldsForJSONEncoder(fields)
-	} else {
-		if p.full { // Always keep it as an empty json object.
-			kv[p.fields] = struct{}{}
+	switch p.timeEncoding {
+	case JSONTimeUnix:
+		kv[p.time] = e.Time().Unix()
+	case JSONTimeUnixMilli:
+		kv[p.time] = e.Time().UnixMilli()
+	case JSONTimeUnixMicro:
+		kv[p.time] = e.Time().UnixMicro()
+	default:
+		if tm := p.formatTime(e); p.full || tm != "" {
+			kv[p.time] = tm
 		}
 	}
 	if caller := e.Caller(); p.full || caller != "" {
 		kv[p.caller] = caller
 	}
+	if id := e.ID(); id != "" {
+		kv[p.id] = id
+	}
 	if stack := e.Stack(); len(stack) > 0 {
 		kv[p.stack] = stack
 	} else {
@@ -146,9 +303,89 @@ func (p *jsonFormatterMapPool) GetObject(e Entity) interface{} {
 			kv[p.stack] = []string{}
 		}
 	}
+	if fields := e.Fields(); len(fields) > 0 {
+		if p.flatten {
+			p.flattenFields(kv, internal.StandardiseFieldsForJSONEncoder(fields))
+		} else {
+			kv[p.fields] = internal.StandardiseFieldsForJSONEncoder(fields)
+		}
+	} else {
+		if p.full && !p.flatten { // Always keep it as an empty json object.
+			kv[p.fields] = struct{}{}
+		}
+	}
+	if len(p.order) > 0 {
+		return &jsonOrderedMap{kv: kv, order: p.order}
+	}
 	return kv
 }
 
+// Formats the time of the given log entity with the time format of the formatter.
+func (p *jsonFormatterMapPool) formatTime(e Entity) string {
+	if p.timeFormat == "" {
+		return e.TimeString()
+	}
+	return e.Time().Format(p.timeFormat)
+}
+
+// Returns the string of the given log level.
+func (p *jsonFormatterMapPool) levelString(level Level) string {
+	if s, found := p.levelLabels[level]; found {
+		return s
+	}
+	return level.String()
+}
+
+// Encodes the given log level as a number.
+func (p *jsonFormatterMapPool) encodeLevel(level Level) int {
+	if p.levelNumber == JSONLevelSeverity {
+		return level.Severity()
+	}
+	return int(level)
+}
+
+// Merges the given log fields into the given json map.
+func (p *jsonFormatterMapPool) flattenFields(kv, fields map[string]interface{}) {
+	for k, v := range fields {
+		if p.isBuiltinKey(k) {
+			switch p.collision {
+			case JSONFieldCollisionOverwrite:
+			case JSONFieldCollisionDiscard:
+				continue
+			default:
+				k = p.fields + "." + k
+			}
+		}
+		kv[k] = v
+	}
+}
+
+// Determines whether the given key is a built-in key of the json object.
+// The fields key is not a built-in key when the fields are flattened.
+func (p *jsonFormatterMapPool) isBuiltinKey(k string) bool {
+	switch k {
+	case p.name, p.time, p.level, p.message, p.caller, p.stack, p.id:
+		return true
+	}
+	return p.levelKey != "" && p.levelNumber != JSONLevelNone && k == p.levelKey
+}
+
+// Returns a copy of the given key order with the duplicate keys removed.
+func copyKeyOrder(order []string) []string {
+	if len(order) == 0 {
+		return nil
+	}
+	r := make([]string, 0, len(order))
+	seen := make(map[string]bool, len(order))
+	for _, k := range order {
+		if !seen[k] {
+			seen[k] = true
+			r = append(r, k)
+		}
+	}
+	return r
+}
+
 // PutObject does nothing here.
 // This method is an implementation of the JSONFormatterObjectPool interface.
 func (*jsonFormatterMapPool) PutObject(interface{}) { /* do nothing */ }
@@ -164,6 +401,7 @@ type jsonFormatterObjectPool struct {
 type jsonFormatterObject struct {
 	Caller  *string     `json:"caller,omitempty"`
 	Fields  interface{} `json:"fields,omitempty"` // map[string]interface{} or struct{}
+	ID      string      `json:"id,omitempty"`
 	Level   string      `json:"level"`
 	Message string      `json:"message"`
 	Name    string      `json:"name,omitempty"`
@@ -182,7 +420,7 @@ func newJSONFormatterObjectPool(full bool) JSONFormatterObjectPool {
 // This method is an implementation of the JSONFormatterObjectPool interface.
 func (p *jsonFormatterObjectPool) GetObject(e Entity) interface{} {
 	o := p.pool.Get().(*jsonFormatterObject)
-	o.Level, o.Message, o.Name = e.Level().String(), e.Message(), e.Name()
+	o.Level, o.Message, o.Name, o.ID = e.Level().String(), e.Message(), e.Name(), e.ID()
 	if tm := e.TimeString(); p.full || tm != "" {
 		o.Time = &tm
 	}
@@ -210,6 +448,135 @@ func (p *jsonFormatterObjectPool) GetObject(e Entity) interface{} {
 // This method is an implementation of the JSONFormatterObjectPool interface.
 func (p *jsonFormatterObjectPool) PutObject(v interface{}) {
 	o := v.(*jsonFormatterObject)
-	o.Caller, o.Fields, o.Level, o.Message, o.Name, o.Stack, o.Time = nil, nil, "", "", "", nil, nil
+	o.Caller, o.Fields, o.ID, o.Level, o.Message, o.Name, o.Stack, o.Time = nil, nil, "", "", "", "", nil, nil
 	p.pool.Put(o)
 }
+
+// The json map that encodes the keys in the given order.
+type jsonOrderedMap struct {
+	kv    map[string]interface{}
+	order []string
+}
+
+// MarshalJSON encodes the ordered keys first, followed by the remaining keys in
+// lexicographic order.
+// This method is an implementation of the json.Marshaler interface.
+func (m *jsonOrderedMap) MarshalJSON() ([]byte, error) {
+	b := new(bytes.Buffer)
+	w := &jsonStreamWriter{w: b}
+	w.writeMap(m.kv, m.order)
+	return b.Bytes(), w.err
+}
+
+// Returns the keys of the given json map, the keys in the given order come first, and
+// the remaining keys follow in lexicographic order.
+func jsonMapKeys(kv map[string]interface{}, order []string) []string {
+	keys := make([]string, 0, len(kv))
+	for _, k := range order {
+		if _, found := kv[k]; found {
+			keys = append(keys, k)
+		}
+	}
+	n := len(keys)
+	for k := range kv {
+		if n == 0 || !isOrderedJSONKey(k, order) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys[n:])
+	return keys
+}
+
+// Determines whether the given key is in the given key order.
+func isOrderedJSONKey(k string, order []string) bool {
+	for _, s := range order {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}
+
+// The jsonStreamWriter type writes the json log objects to the writer member by member, the
+// output is the same as the encoding/json package. The first error encountered is kept and
+// the subsequent writes are ignored.
+type jsonStreamWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Writes the given raw data.
+func (w *jsonStreamWriter) write(p []byte) {
+	if w.err == nil {
+		_, w.err = w.w.Write(p)
+	}
+}
+
+// Writes the given member key, the i is the index of the member in the object.
+func (w *jsonStreamWriter) writeKey(i int, k string) {
+	if i > 0 {
+		w.write([]byte{','})
+	}
+	w.writeValue(k)
+	w.write([]byte{':'})
+}
+
+// Writes the given value, the json maps are written member by member.
+func (w *jsonStreamWriter) writeValue(v interface{}) {
+	if w.err != nil {
+		return
+	}
+	if m, ok := v.(map[string]interface{}); ok && m != nil {
+		w.writeMap(m, nil)
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.write(b)
+}
+
+// Writes the given json map with the keys in the given order, see jsonMapKeys.
+func (w *jsonStreamWriter) writeMap(kv map[string]interface{}, order []string) {
+	w.write([]byte{'{'})
+	for i, k := range jsonMapKeys(kv, order) {
+		w.writeKey(i, k)
+		w.writeValue(kv[k])
+	}
+	w.write([]byte{'}'})
+}
+
+// Writes the given json log object, the members are written in the order of the fields of
+// the object, and the empty members tagged with omitempty are omitted.
+func (w *jsonStreamWriter) writeObject(o *jsonFormatterObject) {
+	var n int
+	member := func(k string, v interface{}) {
+		w.writeKey(n, k)
+		w.writeValue(v)
+		n++
+	}
+	w.write([]byte{'{'})
+	if o.Caller != nil {
+		member("caller", *o.Caller)
+	}
+	if o.Fields != nil {
+		member("fields", o.Fields)
+	}
+	if o.ID != "" {
+		member("id", o.ID)
+	}
+	member("level", o.Level)
+	member("message", o.Message)
+	if o.Name != "" {
+		member("name", o.Name)
+	}
+	if len(o.Stack) > 0 {
+		member("stack", o.Stack)
+	}
+	if o.Time != nil {
+		member("time", *o.Time)
+	}
+	w.write([]byte{'}'})
+}
@@ -16,8 +16,10 @@ package logger
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultJSONFormatter(t *testing.T) {
@@ -125,3 +127,272 @@ func TestJSONFormatter_Format_WithStack(t *testing.T) {
 		t.Fatalf("JSONFormatter.Format(): %s", buf.String())
 	}
 }
+
+func TestNewJSONFormatterWithOptions(t *testing.T) {
+	if f, err := NewJSONFormatterWithOptions(JSONFormatterOptions{}); err != nil {
+		t.Fatalf("NewJSONFormatterWithOptions(): error %s", err)
+	} else {
+		if f == nil {
+			t.Fatal("NewJSONFormatterWithOptions(): nil")
+		}
+	}
+
+	if f, err := NewJSONFormatterWithOptions(JSONFormatterOptions{Keys: map[string]string{"foo": "bar"}}); err == nil {
+		t.Fatal("NewJSONFormatterWithOptions(): nil error")
+	} else {
+		if f != nil {
+			t.Fatal("NewJSONFormatterWithOptions(): not nil")
+		}
+	}
+}
+
+func TestMustNewJSONFormatterWithOptions(t *testing.T) {
+	if MustNewJSONFormatterWithOptions(JSONFormatterOptions{FlattenFields: true}) == nil {
+		t.Fatal("MustNewJSONFormatterWithOptions(): nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustNewJSONFormatterWithOptions(): no panic")
+		}
+	}()
+
+	MustNewJSONFormatterWithOptions(JSONFormatterOptions{Keys: map[string]string{"foo": "bar"}})
+}
+
+func TestJSONFormatter_Format_FlattenFields(t *testing.T) {
+	l := New("test")
+	buf := new(bytes.Buffer)
+	l.SetOutput(buf)
+	l.SetDefaultTimeFormat("test")
+
+	items := []struct {
+		Given JSONFieldCollision
+		Want  string
+	}{
+		{JSONFieldCollisionPrefix, `{"fields.level":"x","foo":1,"level":"info","message":"test","name":"test","time":"test"}`},
+		{JSONFieldCollisionOverwrite, `{"foo":1,"level":"x","message":"test","name":"test","time":"test"}`},
+		{JSONFieldCollisionDiscard, `{"foo":1,"level":"info","message":"test","name":"test","time":"test"}`},
+	}
+
+	for _, item := range items {
+		l.SetFormatter(MustNewJSONFormatterWithOptions(JSONFormatterOptions{
+			FlattenFields: true, FieldCollision: item.Given,
+		}))
+		buf.Reset()
+		l.WithField("foo", 1).WithField("level", "x").Info("test")
+
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("JSONFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+
+	l.SetFormatter(MustNewJSONFormatterWithOptions(JSONFormatterOptions{
+		Keys: map[string]string{"fields": "data"}, Full: true, FlattenFields: true,
+	}))
+	buf.Reset()
+	l.WithField("message", "x").Info("test")
+
+	want := `{"caller":"","data.message":"x","level":"info","message":"test","name":"test","stack":[],"time":"test"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("JSONFormatter.Format(): want %q, got %q", want, got)
+	}
+}
+
+func TestJSONFormatter_Format_WithTimeFormat(t *testing.T) {
+	l := New("test")
+	buf := new(bytes.Buffer)
+	l.SetOutput(buf)
+	l.SetDefaultTimeFormat("test")
+	l.SetFormatter(MustNewJSONFormatterWithOptions(JSONFormatterOptions{TimeFormat: "2006"}))
+
+	l.Info("test")
+
+	want := `{"level":"info","message":"test","name":"test","time":"` + time.Now().Format("2006") + `"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("JSONFormatter.Format(): want %q, got %q", want, got)
+	}
+
+	// The time format of the formatter does not depend on the default time format.
+	buf.Reset()
+	l.SetDefaultTimeFormat(time.RFC3339Nano)
+	l.Info("test")
+
+	if got := buf.String(); got != want {
+		t.Fatalf("JSONFormatter.Format(): want %q, got %q", want, got)
+	}
+}
+
+func TestJSONFormatter_Format_WithTimeEncoding(t *testing.T) {
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	items := []struct {
+		Given JSONTimeEncoding
+		Want  string
+	}{
+		{JSONTimeUnix, `{"level":"info","message":"test","time":1672628645}`},
+		{JSONTimeUnixMilli, `{"level":"info","message":"test","time":1672628645123}`},
+		{JSONTimeUnixMicro, `{"level":"info","message":"test","time":1672628645123456}`},
+	}
+
+	for _, item := range items {
+		f := MustNewJSONFormatterWithOptions(JSONFormatterOptions{TimeEncoding: item.Given})
+		buf := new(bytes.Buffer)
+		e := &logEntity{level: InfoLevel, message: "test", time: tm, timeFormat: "test"}
+
+		if err := f.Format(e, buf); err != nil {
+			t.Fatalf("JSONFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("JSONFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+}
+
+func TestJSONFormatter_Format_WithLevelNumber(t *testing.T) {
+	items := []struct {
+		Given JSONFormatterOptions
+		Want  string
+	}{
+		{JSONFormatterOptions{LevelNumber: JSONLevelOrdinal}, `{"level":5,"message":"test"}`},
+		{JSONFormatterOptions{LevelNumber: JSONLevelSeverity}, `{"level":6,"message":"test"}`},
+		{
+			JSONFormatterOptions{LevelNumber: JSONLevelSeverity, LevelNumberKey: "severity"},
+			`{"level":"error","message":"test","severity":3}`,
+		},
+		{
+			JSONFormatterOptions{LevelNumber: JSONLevelOrdinal, LevelNumberKey: "lvl", FlattenFields: true},
+			`{"fields.lvl":"x","level":"warn","lvl":4,"message":"test"}`,
+		},
+	}
+
+	for i, item := range items {
+		f := MustNewJSONFormatterWithOptions(item.Given)
+		buf := new(bytes.Buffer)
+		e := &logEntity{level: InfoLevel, message: "test"}
+		if i == 2 {
+			e.level = ErrorLevel
+		}
+		if i == 3 {
+			e.level, e.fields = WarnLevel, map[string]interface{}{"lvl": "x"}
+		}
+
+		if err := f.Format(e, buf); err != nil {
+			t.Fatalf("JSONFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("JSONFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+}
+
+func TestJSONFormatter_Format_WithLevelLabels(t *testing.T) {
+	f := MustNewJSONFormatterWithOptions(JSONFormatterOptions{
+		LevelLabels: map[Level]string{WarnLevel: "WARNING"},
+	})
+
+	buf := new(bytes.Buffer)
+	if err := f.Format(&logEntity{level: WarnLevel, message: "test"}, buf); err != nil {
+		t.Fatalf("JSONFormatter.Format(): error %s", err)
+	}
+	want := `{"level":"WARNING","message":"test"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("JSONFormatter.Format(): want %q, got %q", want, got)
+	}
+
+	buf.Reset()
+	if err := f.Format(&logEntity{level: InfoLevel, message: "test"}, buf); err != nil {
+		t.Fatalf("JSONFormatter.Format(): error %s", err)
+	}
+	want = `{"level":"info","message":"test"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("JSONFormatter.Format(): want %q, got %q", want, got)
+	}
+}
+
+func TestJSONFormatter_Format_WithID(t *testing.T) {
+	e := &logEntity{level: InfoLevel, message: "test", id: "foo"}
+	items := []struct {
+		Formatter Formatter
+		Want      string
+	}{
+		{DefaultJSONFormatter(), `{"id":"foo","level":"info","message":"test"}`},
+		{MustNewJSONFormatter(map[string]string{"id": "record_id"}, false), `{"level":"info","message":"test","record_id":"foo"}`},
+	}
+
+	for _, item := range items {
+		buf := new(bytes.Buffer)
+		if err := item.Formatter.Format(e, buf); err != nil {
+			t.Fatalf("JSONFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("JSONFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+}
+
+func TestJSONFormatter_Format_WithKeyOrder(t *testing.T) {
+	e := &logEntity{
+		name: "test", level: InfoLevel, message: "foo", time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		timeFormat: "2006-01-02", fields: map[string]interface{}{"b": 1, "a": "<x>"},
+	}
+	items := []struct {
+		Options JSONFormatterOptions
+		Want    string
+	}{
+		{
+			JSONFormatterOptions{KeyOrder: []string{"time", "level", "time", "unknown"}},
+			`{"time":"2024-01-02","level":"info","fields":{"a":"\u003cx\u003e","b":1},"message":"foo","name":"test"}`,
+		},
+		{
+			JSONFormatterOptions{Keys: map[string]string{"time": "ts"}, FlattenFields: true, KeyOrder: []string{"ts", "message", "b"}},
+			`{"ts":"2024-01-02","message":"foo","b":1,"a":"\u003cx\u003e","level":"info","name":"test"}`,
+		},
+	}
+	for _, item := range items {
+		buf := new(bytes.Buffer)
+		if err := MustNewJSONFormatterWithOptions(item.Options).Format(e, buf); err != nil {
+			t.Fatalf("JSONFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("JSONFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+}
+
+func TestJSONFormatter_FormatTo(t *testing.T) {
+	e := &logEntity{
+		name: "test", level: InfoLevel, message: "<foo>", time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		timeFormat: "2006-01-02", caller: "foo.go:1", stack: []string{"a", "b"}, id: "x",
+		fields: map[string]interface{}{"b": 1, "a": map[string]interface{}{"d": []int{1}, "c": nil}, "e": errors.New("e")},
+	}
+	formatters := []Formatter{
+		DefaultJSONFormatter(),
+		MustNewJSONFormatter(nil, true),
+		MustNewJSONFormatter(map[string]string{"time": "ts"}, false),
+		MustNewJSONFormatterWithOptions(JSONFormatterOptions{FlattenFields: true, KeyOrder: []string{"time", "level"}}),
+	}
+	for _, f := range formatters {
+		for _, entity := range []*logEntity{e, {level: ErrorLevel, message: "bar"}} {
+			want := new(bytes.Buffer)
+			if err := f.Format(entity, want); err != nil {
+				t.Fatalf("JSONFormatter.Format(): error %s", err)
+			}
+			cw := new(testChunkWriter)
+			if err := f.(StreamFormatter).FormatTo(entity, cw); err != nil {
+				t.Fatalf("JSONFormatter.FormatTo(): error %s", err)
+			}
+			if got := strings.Join(cw.chunks, ""); got != want.String() {
+				t.Fatalf("JSONFormatter.FormatTo(): want %q, got %q", want.String(), got)
+			}
+			// The json object is written member by member.
+			if len(cw.chunks) < 8 {
+				t.Fatalf("JSONFormatter.FormatTo(): %q", cw.chunks)
+			}
+		}
+	}
+
+	cw := &testChunkWriter{err: errors.New("test")}
+	if err := DefaultJSONFormatter().(StreamFormatter).FormatTo(e, cw); err == nil {
+		t.Fatal("JSONFormatter.FormatTo(): nil error")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets is the default upper bounds of the latency histogram buckets.
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyBucket defines a bucket of the latency histogram.
+type LatencyBucket struct {
+	// Le is the inclusive upper bound of the bucket.
+	Le time.Duration `json:"le"`
+
+	// Count is the cumulative number of the observations that are less than or equal to
+	// the upper bound of the bucket.
+	Count uint64 `json:"count"`
+}
+
+// LatencyHistogram defines the histogram of the sampled latencies.
+type LatencyHistogram struct {
+	// Count is the number of the sampled observations.
+	Count uint64 `json:"count"`
+
+	// Sum is the total latency of the sampled observations.
+	Sum time.Duration `json:"sum"`
+
+	// Max is the maximum latency of the sampled observations.
+	Max time.Duration `json:"max"`
+
+	// Buckets is the cumulative buckets of the histogram, the observations greater than
+	// the last upper bound are only counted by the Count.
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// Mean returns the mean latency of the sampled observations.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// LatencyStats defines the sampled latency statistics of the logger, which can be used to
+// identify the slow destinations from inside the process.
+// The LatencyStats implements the expvar.Var interface, so it can be published directly:
+//
+//	expvar.Publish("logger", expvar.Func(func() interface{} { return l.LatencyStats() }))
+type LatencyStats struct {
+	// Records is the total number of the records seen by the sampler.
+	Records uint64 `json:"records"`
+
+	// Format is the histogram of the format latencies. For the streamed logs, the format
+	// latency includes the write latency.
+	Format LatencyHistogram `json:"format"`
+
+	// Write is the histogram of the write latencies, including the log interceptor.
+	Write LatencyHistogram `json:"write"`
+}
+
+// String returns the JSON string of the current statistics.
+func (s LatencyStats) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// The latencyRecorder type records the sampled format and write latencies.
+type latencyRecorder struct {
+	records uint64
+	every   uint64
+	format  *latencyHistogram
+	write   *latencyHistogram
+}
+
+// Create a new latency recorder that samples one of every given number of records.
+func newLatencyRecorder(every int, buckets []time.Duration) *latencyRecorder {
+	return &latencyRecorder{
+		every:  uint64(every),
+		format: newLatencyHistogram(buckets),
+		write:  newLatencyHistogram(buckets),
+	}
+}
+
+// Determines whether the current record should be sampled.
+func (r *latencyRecorder) sample() bool {
+	return (atomic.AddUint64(&r.records, 1)-1)%r.every == 0
+}
+
+// Returns the snapshot of the current statistics.
+func (r *latencyRecorder) stats() LatencyStats {
+	return LatencyStats{
+		Records: atomic.LoadUint64(&r.records),
+		Format:  r.format.snapshot(),
+		Write:   r.write.snapshot(),
+	}
+}
+
+// The latencyHistogram type is a lock-free latency histogram.
+type latencyHistogram struct {
+	count   uint64
+	sum     uint64
+	max     uint64
+	bounds  []time.Duration
+	buckets []uint64
+}
+
+// Create a new latency histogram with the given bucket upper bounds.
+func newLatencyHistogram(bounds []time.Duration) *latencyHistogram {
+	return &latencyHistogram{bounds: bounds, buckets: make([]uint64, len(bounds))}
+}
+
+// Records the given latency.
+func (h *latencyHistogram) observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	atomic.AddUint64(&h.count, 1)
+	atomic.AddUint64(&h.sum, uint64(d))
+	for {
+		m := atomic.LoadUint64(&h.max)
+		if uint64(d) <= m || atomic.CompareAndSwapUint64(&h.max, m, uint64(d)) {
+			break
+		}
+	}
+	for i, bound := range h.bounds {
+		if d <= bound {
+			atomic.AddUint64(&h.buckets[i], 1)
+			return
+		}
+	}
+}
+
+// Returns the snapshot of the histogram with the cumulative buckets.
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	s := LatencyHistogram{
+		Count:   atomic.LoadUint64(&h.count),
+		Sum:     time.Duration(atomic.LoadUint64(&h.sum)),
+		Max:     time.Duration(atomic.LoadUint64(&h.max)),
+		Buckets: make([]LatencyBucket, len(h.bounds)),
+	}
+	var total uint64
+	for i, bound := range h.bounds {
+		total += atomic.LoadUint64(&h.buckets[i])
+		s.Buckets[i] = LatencyBucket{Le: bound, Count: total}
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLatencyStats_String(t *testing.T) {
+	s := LatencyStats{Records: 3, Format: LatencyHistogram{Count: 1, Sum: time.Second}}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s.String()), &m); err != nil {
+		t.Fatalf("LatencyStats.String(): %s", err)
+	}
+	if m["records"] != float64(3) || m["format"].(map[string]interface{})["sum"] != float64(time.Second) {
+		t.Fatalf("LatencyStats.String(): %v", m)
+	}
+}
+
+func TestLatencyHistogram_Mean(t *testing.T) {
+	if got := (LatencyHistogram{}).Mean(); got != 0 {
+		t.Fatalf("LatencyHistogram.Mean(): %s", got)
+	}
+	if got := (LatencyHistogram{Count: 4, Sum: time.Second}).Mean(); got != time.Second/4 {
+		t.Fatalf("LatencyHistogram.Mean(): %s", got)
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	h := newLatencyHistogram([]time.Duration{time.Millisecond, time.Second})
+	h.observe(-1)
+	h.observe(time.Millisecond)
+	h.observe(time.Millisecond * 2)
+	h.observe(time.Minute)
+
+	s := h.snapshot()
+	if s.Count != 4 || s.Max != time.Minute || s.Sum != time.Minute+time.Millisecond*3 {
+		t.Fatalf("latencyHistogram.snapshot(): %+v", s)
+	}
+	if len(s.Buckets) != 2 || s.Buckets[0].Count != 2 || s.Buckets[1].Count != 3 || s.Buckets[1].Le != time.Second {
+		t.Fatalf("latencyHistogram.snapshot(): %+v", s.Buckets)
+	}
+}
+
+func TestLogger_SetLatencySampling(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+
+	if s := o.GetLatencyStats(); s.Records != 0 || s.Format.Count != 0 {
+		t.Fatalf("Logger.GetLatencyStats(): %+v", s)
+	}
+	if o.SetLatencySampling(2) == nil {
+		t.Fatal("Logger.SetLatencySampling(): return nil.")
+	}
+	for i := 0; i < 5; i++ {
+		o.Info("test")
+	}
+	s := o.GetLatencyStats()
+	if s.Records != 5 || s.Format.Count != 3 || s.Write.Count != 3 {
+		t.Fatalf("Logger.GetLatencyStats(): %+v", s)
+	}
+	if len(s.Write.Buckets) != len(DefaultLatencyBuckets) {
+		t.Fatalf("Logger.GetLatencyStats(): %+v", s.Write.Buckets)
+	}
+
+	o.SetLatencySampling(1, time.Second, time.Millisecond)
+	o.Info("test")
+	s = o.GetLatencyStats()
+	if s.Records != 1 || len(s.Format.Buckets) != 2 || s.Format.Buckets[0].Le != time.Millisecond {
+		t.Fatalf("Logger.GetLatencyStats(): %+v", s)
+	}
+
+	o.SetLatencySampling(0)
+	o.Info("test")
+	if s = o.GetLatencyStats(); s.Records != 0 {
+		t.Fatalf("Logger.GetLatencyStats(): %+v", s)
+	}
+}
@@ -15,8 +15,10 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/edoger/zkits-logger/internal"
 )
@@ -135,6 +137,39 @@ func (level Level) ColorfulShortCapitalString() string {
 	return "UNO"
 }
 
+// Severity returns the syslog severity (RFC 5424) of the current level.
+// If the log level is not supported, always returns 7 (debug).
+func (level Level) Severity() int {
+	switch level {
+	case PanicLevel:
+		return 0 // Emergency
+	case FatalLevel:
+		return 2 // Critical
+	case ErrorLevel:
+		return 3 // Error
+	case WarnLevel:
+		return 4 // Warning
+	case InfoLevel:
+		return 6 // Informational
+	default:
+		return 7 // Debug
+	}
+}
+
+// Copies the given level labels, the empty labels are ignored.
+func copyLevelLabels(labels map[Level]string) map[Level]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	r := make(map[Level]string, len(labels))
+	for level, label := range labels {
+		if label != "" {
+			r[level] = label
+		}
+	}
+	return r
+}
+
 // IsValid determines whether the current level is valid.
 func (level Level) IsValid() bool {
 	return level <= TraceLevel && level >= PanicLevel
@@ -145,26 +180,82 @@ func (level Level) IsEnabled(l Level) bool {
 	return l <= level && l > 0
 }
 
+// The registered level aliases, the keys are normalized by normalizeLevelAlias.
+var (
+	levelAliasesMu sync.RWMutex
+	levelAliases   = make(map[string]Level)
+)
+
+// Normalizes the given level string.
+func normalizeLevelAlias(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// RegisterLevelAlias registers an additional string (case-insensitive) accepted by ParseLevel
+// for the given level, such as "4" or "sev=warn", so that the config files from the other
+// ecosystems can be consumed without translation shims. The built-in level strings cannot be
+// overridden, and an error is returned if the given alias is empty or a built-in level string,
+// or the given level is invalid. The previously registered alias is replaced.
+func RegisterLevelAlias(alias string, level Level) error {
+	key := normalizeLevelAlias(alias)
+	if key == "" {
+		return errors.New("empty log level alias")
+	}
+	if !level.IsValid() {
+		return fmt.Errorf("invalid log level %d for alias %q", level, alias)
+	}
+	if _, found := parseBuiltinLevel(key); found {
+		return fmt.Errorf("log level alias %q is a built-in level string", alias)
+	}
+	levelAliasesMu.Lock()
+	levelAliases[key] = level
+	levelAliasesMu.Unlock()
+	return nil
+}
+
+// UnregisterLevelAlias removes the given level alias registered by RegisterLevelAlias.
+func UnregisterLevelAlias(alias string) {
+	levelAliasesMu.Lock()
+	delete(levelAliases, normalizeLevelAlias(alias))
+	levelAliasesMu.Unlock()
+}
+
 // ParseLevel parses the log level from the given string.
+// The level aliases registered by RegisterLevelAlias are also accepted.
 func ParseLevel(s string) (Level, error) {
-	switch strings.ToLower(strings.TrimSpace(s)) {
+	key := normalizeLevelAlias(s)
+	if level, found := parseBuiltinLevel(key); found {
+		return level, nil
+	}
+	levelAliasesMu.RLock()
+	level, found := levelAliases[key]
+	levelAliasesMu.RUnlock()
+	if found {
+		return level, nil
+	}
+	// A level zero value is not a supported level.
+	return 0, fmt.Errorf("invalid log level string %q", s)
+}
+
+// Parses the built-in log level from the given normalized string.
+func parseBuiltinLevel(s string) (Level, bool) {
+	switch s {
 	case "panic", "pnc":
-		return PanicLevel, nil
+		return PanicLevel, true
 	case "fatal", "fat":
-		return FatalLevel, nil
+		return FatalLevel, true
 	case "error", "err":
-		return ErrorLevel, nil
+		return ErrorLevel, true
 	case "warn", "wan", "warning":
-		return WarnLevel, nil
+		return WarnLevel, true
 	case "info", "inf", "echo":
-		return InfoLevel, nil
+		return InfoLevel, true
 	case "debug", "dbg":
-		return DebugLevel, nil
+		return DebugLevel, true
 	case "trace", "tac", "print":
-		return TraceLevel, nil
+		return TraceLevel, true
 	}
-	// A level zero value is not a supported level.
-	return 0, fmt.Errorf("invalid log level string %q", s)
+	return 0, false
 }
 
 // MustParseLevel parses the log level from the given string.
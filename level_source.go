@@ -0,0 +1,97 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// DefaultLevelPollInterval is the default polling interval of the polling level source.
+const DefaultLevelPollInterval = time.Second * 10
+
+// LevelSource interface defines the remote configuration source of the logger level, such
+// as Consul, etcd or the feature-flag systems.
+type LevelSource interface {
+	// Watch calls the given function with the current logger level of the source, and each
+	// time the logger level changes, until the given context is done. It blocks the current
+	// goroutine, and the returned error means that the source can not be watched anymore.
+	Watch(ctx context.Context, set func(Level)) error
+}
+
+// LevelSourceFunc type defines the level source in the form of a function, it can be used
+// to adapt the push-based sources.
+type LevelSourceFunc func(ctx context.Context, set func(Level)) error
+
+// Watch calls the given function with the current logger level of the source, and each
+// time the logger level changes, until the given context is done.
+func (f LevelSourceFunc) Watch(ctx context.Context, set func(Level)) error {
+	return f(ctx, set)
+}
+
+// NewPollingLevelSource creates and returns a level source that polls the logger level by the
+// given function periodically. If the given interval is less than or equal to 0,
+// DefaultLevelPollInterval is used. The polling errors are reported by the internal error
+// handler, and they do not stop the polling.
+func NewPollingLevelSource(interval time.Duration, poll func(context.Context) (Level, error)) LevelSource {
+	if interval <= 0 {
+		interval = DefaultLevelPollInterval
+	}
+	return &pollingLevelSource{interval: interval, poll: poll}
+}
+
+// The pollingLevelSource type is the built-in polling level source.
+type pollingLevelSource struct {
+	interval time.Duration
+	poll     func(context.Context) (Level, error)
+}
+
+// Watch polls the logger level periodically until the given context is done.
+func (s *pollingLevelSource) Watch(ctx context.Context, set func(Level)) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if level, err := s.poll(ctx); err != nil {
+			if ctx.Err() == nil {
+				internal.EchoError("Failed to poll logger level: %s", err)
+			}
+		} else {
+			set(level)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunLevelSource applies the logger levels of the given level source to the given logger
+// until the given context is done, it blocks the current goroutine, so it is usually called
+// in a separate goroutine. The invalid levels of the source are ignored.
+// The returned error is the error of the LevelSource.Watch, it is nil if the context is done.
+func RunLevelSource(ctx context.Context, l Logger, s LevelSource) error {
+	err := s.Watch(ctx, func(level Level) {
+		if level.IsValid() && level != l.GetLevel() {
+			l.SetLevel(level)
+		}
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestLevelSourceFunc_Watch(t *testing.T) {
+	o := New("test")
+	s := LevelSourceFunc(func(ctx context.Context, set func(Level)) error {
+		set(DebugLevel)
+		set(Level(100))
+		return errors.New("closed")
+	})
+	if err := RunLevelSource(context.Background(), o, s); err == nil || err.Error() != "closed" {
+		t.Fatalf("RunLevelSource(): %v", err)
+	}
+	if got := o.GetLevel(); got != DebugLevel {
+		t.Fatalf("RunLevelSource(): %s", got)
+	}
+}
+
+func TestNewPollingLevelSource(t *testing.T) {
+	buf := new(bytes.Buffer)
+	internal.ErrorWriter = buf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	if NewPollingLevelSource(0, nil).(*pollingLevelSource).interval != DefaultLevelPollInterval {
+		t.Fatal("NewPollingLevelSource(): default interval")
+	}
+
+	var polls uint32
+	levels := []Level{WarnLevel, 0, ErrorLevel}
+	s := NewPollingLevelSource(time.Millisecond, func(context.Context) (Level, error) {
+		n := atomic.AddUint32(&polls, 1)
+		if n == 2 {
+			return 0, errors.New("unavailable")
+		}
+		if n > 3 {
+			n = 3
+		}
+		return levels[n-1], nil
+	})
+
+	o := New("test")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- RunLevelSource(ctx, o, s) }()
+	for i := 0; i < 1000 && atomic.LoadUint32(&polls) < 4; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunLevelSource(): %s", err)
+	}
+	if got := o.GetLevel(); got != ErrorLevel {
+		t.Fatalf("RunLevelSource(): %s", got)
+	}
+	if got := buf.String(); !strings.Contains(got, "Failed to poll logger level: unavailable") {
+		t.Fatalf("RunLevelSource(): %q", got)
+	}
+}
@@ -194,6 +194,28 @@ func TestLevel_ColorfulShortCapitalString(t *testing.T) {
 	}
 }
 
+func TestLevel_Severity(t *testing.T) {
+	items := []struct {
+		Given Level
+		Want  int
+	}{
+		{PanicLevel, 0},
+		{FatalLevel, 2},
+		{ErrorLevel, 3},
+		{WarnLevel, 4},
+		{InfoLevel, 6},
+		{DebugLevel, 7},
+		{TraceLevel, 7},
+		{Level(0), 7},
+	}
+
+	for _, item := range items {
+		if got := item.Given.Severity(); got != item.Want {
+			t.Fatalf("Level.Severity(): want %d, got %d", item.Want, got)
+		}
+	}
+}
+
 func TestLevel_IsValid(t *testing.T) {
 	items := []struct {
 		Given Level
@@ -381,3 +403,45 @@ func TestIsLowPriorityLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterLevelAlias(t *testing.T) {
+	defer UnregisterLevelAlias("4")
+	defer UnregisterLevelAlias("sev=warn")
+
+	if err := RegisterLevelAlias("4", WarnLevel); err != nil {
+		t.Fatalf("RegisterLevelAlias(): error %s", err)
+	}
+	if err := RegisterLevelAlias(" SEV=WARN ", WarnLevel); err != nil {
+		t.Fatalf("RegisterLevelAlias(): error %s", err)
+	}
+	for _, s := range []string{"4", "sev=warn", "Sev=Warn"} {
+		if got, err := ParseLevel(s); err != nil || got != WarnLevel {
+			t.Fatalf("ParseLevel(%q): %v %v", s, got, err)
+		}
+	}
+
+	items := []struct {
+		Alias string
+		Level Level
+	}{
+		{"", WarnLevel},
+		{"5", Level(0)},
+		{"WARNING", ErrorLevel},
+		{"info", InfoLevel},
+	}
+	for _, item := range items {
+		if err := RegisterLevelAlias(item.Alias, item.Level); err == nil {
+			t.Fatalf("RegisterLevelAlias(%q): nil error", item.Alias)
+		}
+	}
+
+	UnregisterLevelAlias("4")
+	if _, err := ParseLevel("4"); err == nil {
+		t.Fatal("ParseLevel(): nil error")
+	}
+
+	l := New("test")
+	if err := l.SetLevelString("sev=warn"); err != nil || l.GetLevel() != WarnLevel {
+		t.Fatalf("Logger.SetLevelString(): %v %v", l.GetLevel(), err)
+	}
+}
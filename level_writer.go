@@ -16,6 +16,7 @@ package logger
 
 import (
 	"io"
+	"strings"
 )
 
 // NewLevelWriter creates a writer that records each message written as a log message.
@@ -24,21 +25,83 @@ func NewLevelWriter(l Level, g Log) io.Writer {
 	return &logLevelWriter{level: l, log: g}
 }
 
+// NewLevelDetectingWriter is like NewLevelWriter, but the level of each message is detected
+// from the common level prefix of the message, such as "ERROR: ", "[debug] " and "WARN ", and
+// the detected prefix is removed from the message. The bare prefix (without the brackets or
+// the colon) is detected only if it is capitalized, so "info about the task" is not affected.
+// The messages without the level prefix are recorded at the given level.
+// The detected FatalLevel and PanicLevel prefixes are recorded at the ErrorLevel, so the
+// bridged third-party output never calls the exit function or the panic function.
+func NewLevelDetectingWriter(l Level, g Log) io.Writer {
+	return &logLevelWriter{level: l, log: g, detect: true}
+}
+
 // This writer records each message written as a log message.
 // The level of the log is determined by the level given when the writer was created.
 type logLevelWriter struct {
-	level Level
-	log   Log
+	level  Level
+	log    Log
+	detect bool
 }
 
 // Write method is an implementation of the io.Writer interface.
 // This method will always write successfully.
 func (w *logLevelWriter) Write(p []byte) (n int, err error) {
 	// Strips extra newlines, as the log formatter automatically appends a newline.
+	var s string
 	if n = len(p); n > 0 && p[n-1] == '\n' {
-		w.log.Log(w.level, string(p[:n-1]))
+		s = string(p[:n-1])
 	} else {
-		w.log.Log(w.level, string(p))
+		s = string(p)
+	}
+	if w.detect {
+		if level, message, found := detectLevelPrefix(s); found {
+			w.log.Log(level, message)
+			return
+		}
 	}
+	w.log.Log(w.level, s)
 	return
 }
+
+// Detects the level prefix of the given message, and returns the level and the message
+// without the level prefix.
+func detectLevelPrefix(s string) (Level, string, bool) {
+	t := strings.TrimLeft(s, " \t")
+	var name, rest string
+	var bare bool
+	if strings.HasPrefix(t, "[") {
+		i := strings.IndexByte(t, ']')
+		if i < 0 {
+			return 0, s, false
+		}
+		name, rest = t[1:i], t[i+1:]
+		rest = strings.TrimPrefix(rest, ":")
+	} else {
+		i := strings.IndexAny(t, ": \t")
+		if i < 0 {
+			return 0, s, false
+		}
+		name, rest = t[:i], t[i+1:]
+		bare = t[i] != ':'
+	}
+	if bare && name != strings.ToUpper(name) {
+		return 0, s, false
+	}
+	var level Level
+	switch strings.ToLower(name) {
+	case "panic", "fatal", "error", "err":
+		level = ErrorLevel
+	case "warn", "warning":
+		level = WarnLevel
+	case "info":
+		level = InfoLevel
+	case "debug":
+		level = DebugLevel
+	case "trace":
+		level = TraceLevel
+	default:
+		return 0, s, false
+	}
+	return level, strings.TrimLeft(rest, " \t"), true
+}
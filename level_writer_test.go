@@ -52,3 +52,51 @@ func TestLevelWriter_Write(t *testing.T) {
 		}
 	}
 }
+
+func TestLevelDetectingWriter_Write(t *testing.T) {
+	w := new(bytes.Buffer)
+	o := New("test")
+	o.SetOutput(w)
+	o.SetLevel(TraceLevel)
+	var raised bool
+	o.SetExitFunc(func(int) { raised = true })
+	o.SetPanicFunc(func(string) { raised = true })
+	o.SetFormatter(FormatterFunc(func(e Entity, b *bytes.Buffer) error {
+		b.WriteString(e.Level().String() + " " + e.Message())
+		return nil
+	}))
+	lw := NewLevelDetectingWriter(InfoLevel, o.AsLog())
+	if lw == nil {
+		t.Fatal("NewLevelDetectingWriter(): nil")
+	}
+
+	items := [][2]string{
+		{"ERROR: test\n", "error test"},
+		{"error:test", "error test"},
+		{"WARN  test", "warn test"},
+		{"  [DEBUG] test", "debug test"},
+		{"[trace]: test", "trace test"},
+		{"[Warning]test", "warn test"},
+		{"FATAL: test", "error test"},
+		{"panic: test", "error test"},
+		{"[fatal] test", "error test"},
+		{"Error test", "info Error test"},
+		{"info about test", "info info about test"},
+		{"[foo] test", "info [foo] test"},
+		{"[ERROR test", "info [ERROR test"},
+		{"test", "info test"},
+		{"", "info "},
+	}
+	for _, item := range items {
+		w.Reset()
+		if n, err := lw.Write([]byte(item[0])); err != nil || n != len(item[0]) {
+			t.Fatalf("LevelDetectingWriter.Write(): %d %v", n, err)
+		}
+		if got := w.String(); got != item[1] {
+			t.Fatalf("LevelDetectingWriter.Write(): got %q, want %q", got, item[1])
+		}
+	}
+	if raised {
+		t.Fatal("LevelDetectingWriter.Write(): the exit or panic function is called")
+	}
+}
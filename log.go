@@ -48,15 +48,65 @@ type Log interface {
 	// WithFieldPairs adds the given key-value pairs to the log.
 	WithFieldPairs(pairs ...interface{}) Log
 
+	// WithTypedFields adds the given strongly-typed fields to the log, the fields are created
+	// by the typed field constructors such as Int, String, Bool and Duration.
+	WithTypedFields(...Field) Log
+
 	// WithContext adds the given context to the log.
 	WithContext(context.Context) Log
 
+	// Event adds the fields of the given named event registered in the event catalog of
+	// the logger to the log, including the event name, the event code and the default fields.
+	// If the event is not registered, only the event name is added and an error is reported.
+	Event(string) Log
+
 	// WithCaller forces the caller report of the current log to be enabled.
 	WithCaller(...int) Log
 
 	// WithStack adds call stack information to the current log.
 	WithStack() Log
 
+	// Timer starts and returns a timer for the operation described by the given message.
+	// When the Timer.Done method is called, a log with the given message and the "elapsed"
+	// field is recorded.
+	Timer(string) Timer
+
+	// Begin starts an operation with the given name and records its start log, and returns
+	// the scoped log of the operation. The operation can be nested by calling the Begin
+	// method of the returned operation.
+	Begin(string) Operation
+
+	// ErrIf does nothing and returns false if the given error is nil, otherwise it records
+	// an ErrorLevel log with the given message, the error and the given key-value pairs
+	// attached, and returns true.
+	//
+	//	if o.ErrIf(err, "failed to save user", "user", id) {
+	//		return
+	//	}
+	ErrIf(err error, message string, pairs ...interface{}) bool
+
+	// Wrap returns nil if the given error is nil, otherwise it records an ErrorLevel log
+	// with the given message and the error attached, and returns the error wrapped with
+	// the given message, so that the error is logged exactly once at the point of wrapping.
+	//
+	//	return o.Wrap(err, "failed to save user")
+	Wrap(err error, message string) error
+
+	// Recover recovers the panic of the current goroutine and records it as a PanicLevel
+	// log with the call stack, the panic is not propagated, and the panic function of the
+	// logger is not called. It must be called directly by a deferred function:
+	//
+	//	defer o.Recover()
+	Recover()
+
+	// RecoverWith is like Recover, but records the panic with the given log level, and
+	// re-panics with the original panic value after recording if rethrow is true.
+	// The exit function and the panic function of the logger are never called.
+	// It must be called directly by a deferred function:
+	//
+	//	defer o.RecoverWith(ErrorLevel, true)
+	RecoverWith(level Level, rethrow bool)
+
 	// IsLevelEnabled checks whether the given log level is enabled.
 	// Always returns false if the given log level is invalid.
 	IsLevelEnabled(Level) bool
@@ -193,6 +243,34 @@ type Log interface {
 	// the exit function given in advance.
 	Fatalf(string, ...interface{})
 
+	// FatalWithCode uses the given parameters to record a FatalLevel log.
+	// After the log record is completed, the system will automatically call
+	// the exit function given in advance with the given exit code.
+	FatalWithCode(int, ...interface{})
+
+	// Check returns a checked entry of the given log level and message if the given log
+	// level is enabled, otherwise it returns nil, so the expensive fields can be built
+	// only when the log will be recorded:
+	//
+	//	if ce := o.Check(DebugLevel, "request"); ce != nil {
+	//		ce.With("body", dump(request)).Write()
+	//	}
+	//
+	// The logs of the disabled levels are still recorded into the ring sink.
+	Check(Level, string) CheckedEntry
+
+	// LogE is like Log, but returns the format error or the write error of the log to
+	// the caller, which is required by the applications that must treat the log failure
+	// as a hard error, such as audit logs. If the given log level is not enabled, the log
+	// is discarded and nil is returned.
+	LogE(Level, ...interface{}) error
+
+	// InfoE is like Info, but returns the format error or the write error of the log.
+	InfoE(...interface{}) error
+
+	// ErrorE is like Error, but returns the format error or the write error of the log.
+	ErrorE(...interface{}) error
+
 	// Panic uses the given parameters to record a PanicLevel log.
 	// After the log record is completed, the system will automatically call
 	// the panic function given in advance.
@@ -209,28 +287,94 @@ type Log interface {
 	Panicf(string, ...interface{})
 }
 
+// The default maximum size of the entity buffer retained in the pool.
+const defaultMaxBufferSize = 4096
+
+// The marker appended to the truncated log data.
+const truncatedMarker = "...(truncated)"
+
+// DefaultExitTimeout is the default maximum wait time for the exit handlers.
+const DefaultExitTimeout = time.Second * 5
+
 // The core type defines the collection of shared attributes within the log,
 // and each independent Logger shares the same core instance.
 type core struct {
-	name          string
-	level         uint32
-	formatter     Formatter
-	formatOutput  FormatOutput
-	writer        io.Writer
-	levelWriter   map[Level]io.Writer
-	pool          sync.Pool
-	hooks         HookBag
-	enableHooks   bool
-	timeFormat    string
-	nowFunc       func() time.Time
-	exitFunc      func(int)
-	panicFunc     func(string)
-	caller        *internal.CallerReporter
-	callerSkip    int
-	callerLong    bool
-	levelCaller   map[Level]*internal.CallerReporter
-	interceptor   func(Summary, io.Writer) (int, error)
-	stackPrefixes []string
+	// The 64-bit fields accessed atomically must be kept at the top of the structure
+	// to ensure 64-bit alignment on 32-bit platforms.
+	truncated uint64
+	sequence  uint64
+
+	name           string
+	level          uint32
+	formatter      Formatter
+	formatOutput   FormatOutput
+	writer         io.Writer
+	levelWriter    map[Level]io.Writer
+	pool           sync.Pool
+	hooks          HookBag
+	enableHooks    bool
+	disabledHooks  uint32 // The bit mask of the levels whose hooks are disabled.
+	timeFormat     string
+	nowFunc        func() time.Time
+	timePrecision  time.Duration
+	uptimeField    string
+	uptimeStart    time.Time
+	exitFunc       func(int)
+	exitCode       int
+	exitHandlers   []func()
+	fatalHandlers  []func(Summary)
+	exitTimeout    time.Duration
+	processMeta    bool
+	pid            int
+	hostname       string
+	panicFunc      func(string)
+	panicSummary   func(Summary)
+	panicHandlers  []func(Summary)
+	caller         *internal.CallerReporter
+	callerSkip     int
+	callerLong     bool
+	levelLong      map[Level]bool
+	callerPrefixes []string
+	levelCaller    map[Level]*internal.CallerReporter
+	interceptor    func(Summary, io.Writer) (int, error)
+	stackPrefixes  []string
+	bufferSize     int
+	maxBufferSize  int
+	streamSize     int
+	streamLocks    sync.Map // The locks of the log writers used by the streamed logs.
+	maxRecordSize  int
+	terminator     string
+	ctxExtractors  []func(context.Context) map[string]interface{}
+	owned          []io.Closer
+	events         EventCatalog
+	fieldPolicy    FieldOverwritePolicy
+	fieldDeepMerge bool
+	ring           RingSink
+	recordID       func() string
+	latency        *latencyRecorder
+	sampler        *logSampler
+	dedup          *logDeduplicator
+
+	// The temporary logger level override of Logger.SetLevelFor.
+	override levelOverride
+}
+
+// The levelOverride type stores the temporary logger level override of Logger.SetLevelFor.
+type levelOverride struct {
+	mu      sync.Mutex
+	gen     uint64
+	timer   *time.Timer
+	level   Level
+	restore Level // The stored level before the override, 0 for the inherited level.
+}
+
+// The logScope type defines the name and the level of the child logger created by
+// Logger.NewChild, the other attributes are shared with the parent logger by the core.
+type logScope struct {
+	parent   *logScope // The scope of the parent logger, nil for the children of the root logger.
+	name     string
+	level    uint32 // The level of the child logger, 0 means that the parent level is inherited.
+	override levelOverride
 }
 
 // Create a new core instance and bind the logger name.
@@ -247,33 +391,125 @@ func newCore(name string) *core {
 		timeFormat:    internal.DefaultTimeFormat,
 		nowFunc:       internal.DefaultNowFunc,
 		exitFunc:      internal.DefaultExitFunc,
+		exitCode:      1,
+		exitTimeout:   DefaultExitTimeout,
 		panicFunc:     internal.DefaultPanicFunc,
 		levelCaller:   make(map[Level]*internal.CallerReporter),
 		stackPrefixes: internal.KnownStackPrefixes,
+		maxBufferSize: defaultMaxBufferSize,
 	}
 }
 
 // Get a log entity from the pool and initialize it.
 func (c *core) getEntity(l *log, level Level, message, caller string) *logEntity {
 	o := c.pool.Get().(*logEntity)
+	if c.bufferSize > 0 && o.buffer.Cap() < c.bufferSize {
+		o.buffer.Grow(c.bufferSize)
+	}
 
-	o.name = c.name
+	o.name = l.Name()
 	o.time = c.nowFunc()
+	if c.timePrecision > 0 {
+		o.time = o.time.Truncate(c.timePrecision)
+	}
 	o.timeFormat = c.timeFormat
 	o.level = level
 	o.message = message
 	o.ctx = l.ctx
 	o.caller = caller
 	o.fields = l.fields
+	if c.uptimeField != "" {
+		o.fields = l.fields.Clone(1)
+		o.fields[c.uptimeField] = o.time.Sub(c.uptimeStart).Seconds()
+	}
+	if c.recordID != nil {
+		o.id = c.recordID()
+	}
+	if c.processMeta {
+		o.pid = c.pid
+		o.hostname = c.hostname
+		o.sequence = atomic.AddUint64(&c.sequence, 1)
+	}
 
 	return o
 }
 
+// FieldOverwritePolicy defines the policy applied when the added log field overwrites an
+// existing field of the log.
+type FieldOverwritePolicy int
+
+const (
+	// FieldOverwriteReplace indicates that the existing field is replaced silently.
+	FieldOverwriteReplace FieldOverwritePolicy = iota
+
+	// FieldOverwriteKeep indicates that the existing field is kept, the added field is ignored.
+	FieldOverwriteKeep
+
+	// FieldOverwriteWarn indicates that the existing field is replaced, and a warning is
+	// reported by the internal error handler, which is useful for catching the accidental
+	// shadowing of the important fields.
+	FieldOverwriteWarn
+)
+
+// Returns a copy of the given fields merged with the given new fields by the field
+// overwrite policy of the logger.
+func (c *core) mergeFields(fields internal.Fields, src map[string]interface{}) internal.Fields {
+	if c.fieldPolicy == FieldOverwriteReplace && !c.fieldDeepMerge {
+		return fields.With(src)
+	}
+	r := fields.Clone(len(src))
+	for k, v := range src {
+		if old, found := fields[k]; found {
+			if c.fieldDeepMerge {
+				if m, ok := mergeFieldMaps(old, v); ok {
+					r[k] = m
+					continue
+				}
+			}
+			if c.fieldPolicy == FieldOverwriteReplace {
+				r[k] = v
+				continue
+			}
+			if c.fieldPolicy == FieldOverwriteKeep {
+				continue
+			}
+			internal.EchoError("(%s) Log field %q is overwritten", c.name, k)
+		}
+		r[k] = v
+	}
+	return r
+}
+
+// Deep-merges the given field values if both of them are maps, the given maps are not modified.
+// The values in the given new map replace the values in the given old map, except the maps.
+func mergeFieldMaps(old, src interface{}) (map[string]interface{}, bool) {
+	a, ok := old.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	b, ok := src.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	r := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		r[k] = v
+	}
+	for k, v := range b {
+		if m, ok := mergeFieldMaps(r[k], v); ok {
+			r[k] = m
+		} else {
+			r[k] = v
+		}
+	}
+	return r, true
+}
+
 // Clean up and recycle the given log entity.
 func (c *core) putEntity(o *logEntity) {
-	// If the log size exceeds 4KB, we need to discard this buffer to
-	// free memory faster.
-	if o.buffer.Cap() > 4096 {
+	// If the log size exceeds the maximum buffer size (4KB by default), we need
+	// to discard this buffer to free memory faster.
+	if c.maxBufferSize > 0 && o.buffer.Cap() > c.maxBufferSize {
 		o.buffer = bytes.Buffer{}
 	} else {
 		o.buffer.Reset()
@@ -286,22 +522,139 @@ func (c *core) putEntity(o *logEntity) {
 	o.ctx = nil
 	o.caller = ""
 	o.stack = nil
+	o.pid = 0
+	o.hostname = ""
+	o.sequence = 0
+	o.id = ""
+	o.fanOut = o.fanOut[:0]
 
 	c.pool.Put(o)
 }
 
+// Truncate the log data exceeding the maximum record size, and append the truncated marker.
+// The trailing newline of the log data is retained.
+func (c *core) truncate(b *bytes.Buffer) bool {
+	if c.maxRecordSize <= 0 || b.Len() <= c.maxRecordSize {
+		return false
+	}
+	newline := b.Bytes()[b.Len()-1] == '\n'
+	n := c.maxRecordSize - len(truncatedMarker)
+	if newline {
+		n--
+	}
+	if n < 0 {
+		n = 0
+	}
+	b.Truncate(n)
+	b.WriteString(truncatedMarker)
+	if newline {
+		b.WriteByte('\n')
+	}
+	return true
+}
+
+// Replace the trailing newline of the log data with the record terminator.
+func (c *core) terminate(b *bytes.Buffer) {
+	if c.terminator == "" {
+		return
+	}
+	if n := b.Len(); n > 0 && b.Bytes()[n-1] == '\n' {
+		b.Truncate(n - 1)
+		b.WriteString(c.terminator)
+	}
+}
+
+// Writes the logs formatted by the secondary format outputs of the fan-out format output.
+func (c *core) writeFanOut(o *logEntity) {
+	for i, j := 0, len(o.fanOut); i < j; i++ {
+		r := o.fanOut[i]
+		if r.err == nil {
+			c.truncate(r.buffer)
+			c.terminate(r.buffer)
+		}
+		r.write(c.name)
+		o.fanOut[i] = nil
+	}
+	o.fanOut = o.fanOut[:0]
+}
+
+// Runs the exit handlers and closes the owned writers, and waits for them to complete
+// until the exit timeout is reached.
+func (c *core) runExitHandlers() {
+	if len(c.exitHandlers) == 0 && len(c.owned) == 0 {
+		return
+	}
+	handlers, owned := c.exitHandlers, c.owned
+	c.owned = nil
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(handlers) - 1; i >= 0; i-- {
+			c.runExitHandler(handlers[i])
+		}
+		var closed []io.Closer
+		for i := len(owned) - 1; i >= 0; i-- {
+			if !containsCloser(closed, owned[i]) {
+				closed = append(closed, owned[i])
+				if err := owned[i].Close(); err != nil {
+					internal.EchoError("(%s) Failed to close writer: %s", c.name, err)
+				}
+			}
+		}
+	}()
+	timer := time.NewTimer(c.exitTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		internal.EchoError("(%s) Exit handlers timed out after %s", c.name, c.exitTimeout)
+	}
+}
+
+// Runs the given exit handler, the panic of the exit handler is recovered and reported.
+func (c *core) runExitHandler(f func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			internal.EchoError("(%s) Exit handler panicked: %v", c.name, v)
+		}
+	}()
+	f()
+}
+
+// Runs the given handlers of the FatalLevel or PanicLevel log in the order of their
+// registration, the panics of the handlers are recovered and reported.
+func (c *core) runSummaryHandlers(handlers []func(Summary), s Summary) {
+	for i := range handlers {
+		func() {
+			defer func() {
+				if v := recover(); v != nil {
+					internal.EchoError("(%s) The %s handler panicked: %v", c.name, s.Level(), v)
+				}
+			}()
+			handlers[i](s)
+		}()
+	}
+}
+
 // Internal implementation of the Log interface.
 type log struct {
 	core   *core
+	scope  *logScope // The name and level of the child logger, nil for the root logger.
 	ctx    context.Context
 	fields internal.Fields
 	caller *internal.CallerReporter
 	prefix string
 	stack  bool
+
+	// The exit code of the FatalLevel log, if it is nil, the exit code of the logger is used.
+	exitCode *int
 }
 
 // Name returns the logger name.
 func (o *log) Name() string {
+	if o.scope != nil {
+		return o.scope.name
+	}
 	return o.core.name
 }
 
@@ -310,17 +663,24 @@ func (o *log) WithMessagePrefix(prefix string) Log {
 	if o.prefix == prefix {
 		return o
 	}
-	return &log{core: o.core, fields: o.fields, ctx: o.ctx, caller: o.caller, stack: o.stack, prefix: prefix}
+	return &log{core: o.core, scope: o.scope, fields: o.fields, ctx: o.ctx, caller: o.caller, stack: o.stack, prefix: prefix}
 }
 
 // WithField adds the given extended data to the log.
 func (o *log) WithField(key string, value interface{}) Log {
-	r := &log{core: o.core, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
+	return o.withField(key, value)
+}
+
+// Adds the given extended data to the log and returns the internal log.
+func (o *log) withField(key string, value interface{}) *log {
+	r := &log{core: o.core, scope: o.scope, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
 	if len(o.fields) == 0 {
 		r.fields = internal.Fields{key: value}
-	} else {
+	} else if o.core.fieldPolicy == FieldOverwriteReplace && !o.core.fieldDeepMerge {
 		r.fields = o.fields.Clone(1)
 		r.fields[key] = value
+	} else {
+		r.fields = o.core.mergeFields(o.fields, map[string]interface{}{key: value})
 	}
 	return r
 }
@@ -333,38 +693,113 @@ func (o *log) WithError(err error) Log {
 
 // WithFields adds the given multiple extended data to the log.
 func (o *log) WithFields(fields map[string]interface{}) Log {
+	return o.withFields(fields)
+}
+
+// Adds the given multiple extended data to the log and returns the internal log.
+func (o *log) withFields(fields map[string]interface{}) *log {
 	if len(fields) == 0 {
 		return o
 	}
-	r := &log{core: o.core, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
+	r := &log{core: o.core, scope: o.scope, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
 	if len(o.fields) == 0 {
 		r.fields = internal.MakeFields(fields)
 	} else {
-		r.fields = o.fields.With(fields)
+		r.fields = o.core.mergeFields(o.fields, fields)
 	}
 	return r
 }
 
+// Event adds the fields of the given named event registered in the event catalog of
+// the logger to the log, including the event name, the event code and the default fields.
+// If the event is not registered, only the event name is added and an error is reported.
+func (o *log) Event(name string) Log {
+	var e EventDefinition
+	found := false
+	if o.core.events != nil {
+		e, found = o.core.events.Lookup(name)
+	}
+	if !found {
+		internal.EchoError("(%s) Unknown log event %q", o.core.name, name)
+		return o.withField(DefaultEventField, name)
+	}
+	fields := make(map[string]interface{}, len(e.Fields)+2)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	fields[DefaultEventField] = e.Name
+	if e.Code != "" {
+		fields[DefaultEventCodeField] = e.Code
+	}
+	return o.withFields(fields)
+}
+
 // WithFieldPairs adds the given key-value pairs to the log.
 func (o *log) WithFieldPairs(pairs ...interface{}) Log {
+	return o.withFieldPairs(pairs...)
+}
+
+// Adds the given key-value pairs to the log and returns the internal log.
+func (o *log) withFieldPairs(pairs ...interface{}) *log {
 	if len(pairs) == 0 {
 		return o
 	}
-	r := &log{core: o.core, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
+	r := &log{core: o.core, scope: o.scope, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
 	if len(o.fields) == 0 {
 		r.fields = internal.FormatPairsToFields(pairs)
 	} else {
-		r.fields = o.fields.With(internal.FormatPairsToFields(pairs))
+		r.fields = o.core.mergeFields(o.fields, internal.FormatPairsToFields(pairs))
+	}
+	return r
+}
+
+// WithTypedFields adds the given strongly-typed fields to the log, the fields are created
+// by the typed field constructors such as Int, String, Bool and Duration.
+func (o *log) WithTypedFields(fields ...Field) Log {
+	return o.withTypedFields(fields...)
+}
+
+// Adds the given strongly-typed fields to the log and returns the internal log.
+func (o *log) withTypedFields(fields ...Field) *log {
+	if len(fields) == 0 {
+		return o
+	}
+	r := &log{core: o.core, scope: o.scope, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
+	m := make(internal.Fields, len(fields))
+	for i := range fields {
+		m[fields[i].Key] = fields[i].Value()
+	}
+	if len(o.fields) == 0 {
+		r.fields = m
+	} else {
+		r.fields = o.core.mergeFields(o.fields, m)
 	}
 	return r
 }
 
 // WithContext adds the given context to the log.
+// If the logger has registered context field extractors, the fields extracted from the
+// given context are also added to the log.
 func (o *log) WithContext(ctx context.Context) Log {
-	return &log{
-		core: o.core, fields: o.fields, caller: o.caller, prefix: o.prefix, stack: o.stack,
+	r := &log{
+		core: o.core, scope: o.scope, fields: o.fields, caller: o.caller, prefix: o.prefix, stack: o.stack,
 		ctx: ctx,
 	}
+	if ctx != nil && len(o.core.ctxExtractors) > 0 {
+		var fields map[string]interface{}
+		for i, j := 0, len(o.core.ctxExtractors); i < j; i++ {
+			for k, v := range o.core.ctxExtractors[i](ctx) {
+				if fields == nil {
+					fields = make(map[string]interface{})
+				}
+				fields[k] = v
+			}
+		}
+		if len(fields) > 0 {
+			r.fields = o.core.mergeFields(o.fields, fields)
+		}
+	}
+	return r
 }
 
 // WithCaller forces the caller report of the current log to be enabled.
@@ -378,7 +813,7 @@ func (o *log) WithCaller(skip ...int) Log {
 		return o
 	}
 	return &log{
-		core: o.core, fields: o.fields, ctx: o.ctx, prefix: o.prefix, stack: o.stack,
+		core: o.core, scope: o.scope, fields: o.fields, ctx: o.ctx, prefix: o.prefix, stack: o.stack,
 		caller: internal.NewCallerReporter(n),
 	}
 }
@@ -389,52 +824,229 @@ func (o *log) WithStack() Log {
 		return o
 	}
 	return &log{
-		core: o.core, fields: o.fields, ctx: o.ctx, caller: o.caller, prefix: o.prefix,
+		core: o.core, scope: o.scope, fields: o.fields, ctx: o.ctx, caller: o.caller, prefix: o.prefix,
 		stack: true,
 	}
 }
 
+// Timer starts and returns a timer for the operation described by the given message.
+// When the Timer.Done method is called, a log with the given message and the "elapsed"
+// field is recorded.
+func (o *log) Timer(message string) Timer {
+	return newLogTimer(o, message)
+}
+
+// Begin starts an operation with the given name and records its start log, and returns
+// the scoped log of the operation. The operation can be nested by calling the Begin
+// method of the returned operation.
+func (o *log) Begin(name string) Operation {
+	op := newLogOperation(o, name)
+	// Calling the internal log method directly ensures that the caller reported is the
+	// caller of the Begin method.
+	op.log.log(InfoLevel, name+" started")
+	return op
+}
+
+// ErrIf does nothing and returns false if the given error is nil, otherwise it records
+// an ErrorLevel log with the given message, the error and the given key-value pairs
+// attached, and returns true.
+func (o *log) ErrIf(err error, message string, pairs ...interface{}) bool {
+	if err == nil {
+		return false
+	}
+	o.withFieldPairs(pairs...).withField("error", err).log(ErrorLevel, message)
+	return true
+}
+
+// Wrap returns nil if the given error is nil, otherwise it records an ErrorLevel log
+// with the given message and the error attached, and returns the error wrapped with
+// the given message, so that the error is logged exactly once at the point of wrapping.
+func (o *log) Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	o.withField("error", err).log(ErrorLevel, message)
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// Recover recovers the panic of the current goroutine and records it as a PanicLevel
+// log with the call stack, the panic is not propagated, and the panic function of the
+// logger is not called. It must be called directly by a deferred function.
+func (o *log) Recover() {
+	if v := recover(); v != nil {
+		o.recovered(PanicLevel, v)
+	}
+}
+
+// RecoverWith is like Recover, but records the panic with the given log level, and
+// re-panics with the original panic value after recording if rethrow is true.
+// The exit function and the panic function of the logger are never called.
+// It must be called directly by a deferred function.
+func (o *log) RecoverWith(level Level, rethrow bool) {
+	if v := recover(); v != nil {
+		o.recovered(level, v)
+		if rethrow {
+			panic(v)
+		}
+	}
+}
+
+// Records the given recovered panic value with the given log level.
+func (o *log) recovered(level Level, v interface{}) {
+	if !o.isRecorded(level) {
+		return
+	}
+	r := &log{core: o.core, scope: o.scope, fields: o.fields, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: true}
+	if err, ok := v.(error); ok {
+		r = r.withField("error", err)
+	}
+	r.record(level, fmt.Sprintf("recovered from panic: %v", v), false)
+}
+
 // Format and record the current log.
-func (o *log) record(level Level, message string) {
+// The raise parameter determines whether the exit function or the panic function is called
+// after the FatalLevel or PanicLevel log is recorded.
+// The returned error is the format error or the write error of the log, and it has been
+// reported by the internal error handler.
+func (o *log) record(level Level, message string, raise bool) (failure error) {
 	entity := o.core.getEntity(o, level, o.prefix+message, o.getCaller(level))
 	defer o.core.putEntity(entity)
 
 	if o.stack {
 		entity.stack = internal.GetStack(o.core.stackPrefixes)
 	}
+	if o.core.ring != nil {
+		o.core.ring.Add(entity)
+		if !o.isEnabled(level) {
+			return nil
+		}
+	}
+	if o.core.dedup != nil {
+		if level >= ErrorLevel {
+			ok, pending := o.core.dedup.check(o, level, message, entity)
+			pending.record()
+			if !ok {
+				return nil
+			}
+		} else {
+			o.core.dedup.flush()
+		}
+	}
+	if o.core.sampler != nil && level >= ErrorLevel {
+		ok, suppressed, summary := o.core.sampler.sample(o, level, entity.message, entity.time)
+		summary.record()
+		if !ok {
+			return nil
+		}
+		if suppressed > 0 {
+			entity.fields = internal.Fields(entity.fields).Clone(2)
+			entity.fields[SampledField] = true
+			entity.fields[SuppressedField] = suppressed
+		}
+	}
+	return o.emit(entity, message, raise)
+}
 
-	var (
-		err error
-		w   io.Writer
-	)
-	if o.core.formatOutput == nil {
-		err = o.core.formatter.Format(entity, entity.Buffer())
+// Format and write the given log entity, the given message is passed to the panic function.
+func (o *log) emit(entity *logEntity, message string, raise bool) (failure error) {
+	level := entity.level
+	var start time.Time
+	latency := o.core.latency
+	if latency != nil && latency.sample() {
+		start = time.Now()
 	} else {
-		w, err = o.core.formatOutput.Format(entity, entity.Buffer())
+		latency = nil
+	}
+	w, streamed, werr, err := o.format(entity)
+	if latency != nil {
+		latency.format.observe(time.Since(start))
+	}
+	if werr != nil {
+		failure = werr
 	}
 	if err == nil {
-		if o.core.enableHooks {
+		if !streamed {
+			if o.core.truncate(&entity.buffer) {
+				atomic.AddUint64(&o.core.truncated, 1)
+			}
+			o.core.terminate(&entity.buffer)
+		}
+		if o.core.enableHooks && atomic.LoadUint32(&o.core.disabledHooks)&(1<<level) == 0 {
 			err = o.core.hooks.Fire(entity)
 			if err != nil {
 				internal.EchoError("(%s) Failed to fire log hook: %s", o.core.name, err)
 			}
 		}
-		if err = o.write(entity, w); err != nil {
-			internal.EchoError("(%s) Failed to write log: %s", o.core.name, err)
+		// The streamed log has been written to the log writer.
+		if !streamed {
+			if latency != nil {
+				start = time.Now()
+			}
+			err = o.write(entity, w)
+			if latency != nil {
+				latency.write.observe(time.Since(start))
+			}
+			if err != nil {
+				internal.EchoError("(%s) Failed to write log: %s", o.core.name, err)
+				failure = err
+			}
 		}
 	} else {
 		// When the format log fails, we terminate the logging and report the error.
 		internal.EchoError("(%s) Failed to format log: %s", o.core.name, err)
+		failure = err
+	}
+	if len(entity.fanOut) > 0 {
+		o.core.writeFanOut(entity)
 	}
 
-	if level < ErrorLevel {
+	if raise && level < ErrorLevel {
 		switch level {
 		case FatalLevel:
-			o.core.exitFunc(1)
+			o.core.runSummaryHandlers(o.core.fatalHandlers, entity)
+			o.core.runExitHandlers()
+			if o.exitCode != nil {
+				o.core.exitFunc(*o.exitCode)
+			} else {
+				o.core.exitFunc(o.core.exitCode)
+			}
 		case PanicLevel:
-			o.core.panicFunc(message)
+			o.core.runSummaryHandlers(o.core.panicHandlers, entity)
+			if o.core.panicSummary != nil {
+				o.core.panicSummary(entity)
+			} else {
+				o.core.panicFunc(message)
+			}
+		}
+	}
+	return
+}
+
+// Format the given log entity.
+// If the log is streamed, the log data has been written to the log writer.
+func (o *log) format(entity *logEntity) (w io.Writer, streamed bool, werr, err error) {
+	if o.core.formatOutput != nil {
+		w, err = o.core.formatOutput.Format(entity, entity.Buffer())
+		return
+	}
+	if o.core.streamSize > 0 && o.core.interceptor == nil {
+		if f, ok := o.core.formatter.(StreamFormatter); ok {
+			sw := &streamWriter{
+				buffer: entity.Buffer(), writer: o.getWriter(entity), threshold: o.core.streamSize,
+				locks: &o.core.streamLocks,
+			}
+			err = f.FormatTo(entity, sw)
+			sw.release()
+			if streamed = sw.streamed; sw.err != nil {
+				// The stream write error is not a format error, and the log has been discarded.
+				internal.EchoError("(%s) Failed to write log: %s", o.core.name, sw.err)
+				err, werr = nil, sw.err
+			}
+			return
 		}
 	}
+	err = o.core.formatter.Format(entity, entity.Buffer())
+	return
 }
 
 // Get the log writer.
@@ -470,28 +1082,61 @@ func (o *log) write(entity *logEntity, w io.Writer) (err error) {
 // Get the caller report. If caller reporting is not enabled in the current
 // log, an empty string is always returned.
 func (o *log) getCaller(level Level) string {
-	if o.caller == nil {
-		if caller, found := o.core.levelCaller[level]; found {
-			return internal.GetCaller(caller.Skip()+o.core.callerSkip, o.core.callerLong)
-		}
-		if o.core.caller != nil {
-			return internal.GetCaller(o.core.caller.Skip()+o.core.callerSkip, o.core.callerLong)
-		}
+	skip := o.core.callerSkip
+	if caller, found := o.core.levelCaller[level]; found {
+		skip += caller.Skip()
+	} else if o.core.caller != nil {
+		skip += o.core.caller.Skip()
+	} else if o.caller == nil {
 		return ""
 	}
-	if caller, found := o.core.levelCaller[level]; found {
-		return internal.GetCaller(caller.Skip()+o.caller.Skip()+o.core.callerSkip, o.core.callerLong)
+	if o.caller != nil {
+		skip += o.caller.Skip()
+	}
+	long := o.core.callerLong
+	if v, found := o.core.levelLong[level]; found {
+		long = v
 	}
-	if o.core.caller != nil {
-		return internal.GetCaller(o.core.caller.Skip()+o.caller.Skip()+o.core.callerSkip, o.core.callerLong)
+	if len(o.core.callerPrefixes) > 0 {
+		return internal.GetCallerSkipping(skip, long, o.core.callerPrefixes)
 	}
-	return internal.GetCaller(o.caller.Skip()+o.core.callerSkip, o.core.callerLong)
+	return internal.GetCaller(skip, long)
 }
 
 // IsLevelEnabled checks whether the given log level is enabled.
 // Always returns false if the given log level is invalid.
 func (o *log) IsLevelEnabled(level Level) bool {
-	return Level(atomic.LoadUint32(&o.core.level)).IsEnabled(level)
+	return o.isEnabled(level)
+}
+
+// Determines whether the log of the given level needs to be recorded, the logs that are
+// not enabled are still recorded into the ring sink.
+func (o *log) isRecorded(level Level) bool {
+	return o.isEnabled(level) || (o.core.ring != nil && level.IsValid())
+}
+
+// Returns the logger level of the current log.
+func (o *log) getLevel() Level {
+	for s := o.scope; s != nil; s = s.parent {
+		if level := atomic.LoadUint32(&s.level); level != 0 {
+			return Level(level)
+		}
+	}
+	return Level(atomic.LoadUint32(&o.core.level))
+}
+
+// Determines whether the given log level is enabled for the current log.
+func (o *log) isEnabled(level Level) bool {
+	if o.getLevel().IsEnabled(level) {
+		return true
+	}
+	// The minimum level carried by the log context bypasses the logger level.
+	if o.ctx != nil {
+		if min, found := GetMinLevel(o.ctx); found {
+			return min.IsEnabled(level)
+		}
+	}
+	return false
 }
 
 // IsPanicLevelEnabled checks whether the PanicLevel is enabled.
@@ -541,10 +1186,45 @@ func (o *log) Log(level Level, args ...interface{}) {
 
 // Uses the given parameters to record a log of the specified level.
 func (o *log) log(level Level, args ...interface{}) {
-	if !Level(atomic.LoadUint32(&o.core.level)).IsEnabled(level) {
+	if !o.isRecorded(level) {
 		return
 	}
-	o.record(level, fmt.Sprint(args...))
+	o.record(level, fmt.Sprint(args...), true)
+}
+
+// Check returns a checked entry of the given log level and message if the given log
+// level is enabled, otherwise it returns nil.
+// The logs of the disabled levels are still recorded into the ring sink.
+func (o *log) Check(level Level, message string) CheckedEntry {
+	if !o.isEnabled(level) {
+		if o.isRecorded(level) {
+			o.recordRing(level, message)
+		}
+		return nil
+	}
+	return &checkedEntry{log: o, level: level, message: message}
+}
+
+// Records the log of the disabled level into the ring sink only, this keeps the call
+// depth the same as Log.Info.
+func (o *log) recordRing(level Level, message string) {
+	o.record(level, message, false)
+}
+
+// LogE is like Log, but returns the format error or the write error of the log to
+// the caller, which is required by the applications that must treat the log failure
+// as a hard error, such as audit logs. If the given log level is not enabled, the log
+// is discarded and nil is returned.
+func (o *log) LogE(level Level, args ...interface{}) error {
+	return o.logE(level, args...)
+}
+
+// Uses the given parameters to record a log of the specified level and returns the error.
+func (o *log) logE(level Level, args ...interface{}) error {
+	if !o.isRecorded(level) {
+		return nil
+	}
+	return o.record(level, fmt.Sprint(args...), true)
 }
 
 // Logln uses the given parameters to record a log of the specified level.
@@ -559,11 +1239,11 @@ func (o *log) Logln(level Level, args ...interface{}) {
 
 // Uses the given parameters to record a log of the specified level.
 func (o *log) logln(level Level, args ...interface{}) {
-	if !Level(atomic.LoadUint32(&o.core.level)).IsEnabled(level) {
+	if !o.isRecorded(level) {
 		return
 	}
 	s := fmt.Sprintln(args...)
-	o.record(level, s[:len(s)-1])
+	o.record(level, s[:len(s)-1], true)
 }
 
 // Logf uses the given parameters to record a log of the specified level.
@@ -578,10 +1258,10 @@ func (o *log) Logf(level Level, format string, args ...interface{}) {
 
 // Uses the given parameters to record a log of the specified level.
 func (o *log) logf(level Level, format string, args ...interface{}) {
-	if !Level(atomic.LoadUint32(&o.core.level)).IsEnabled(level) {
+	if !o.isRecorded(level) {
 		return
 	}
-	o.record(level, fmt.Sprintf(format, args...))
+	o.record(level, fmt.Sprintf(format, args...), true)
 }
 
 // Trace uses the given parameters to record a TraceLevel log.
@@ -725,6 +1405,25 @@ func (o *log) Fatalf(format string, args ...interface{}) {
 	o.logf(FatalLevel, format, args...)
 }
 
+// FatalWithCode uses the given parameters to record a FatalLevel log.
+// After the log record is completed, the system will automatically call
+// the exit function given in advance with the given exit code.
+func (o *log) FatalWithCode(code int, args ...interface{}) {
+	r := &log{core: o.core, scope: o.scope, fields: o.fields, ctx: o.ctx, caller: o.caller, prefix: o.prefix, stack: o.stack}
+	r.exitCode = &code
+	r.log(FatalLevel, args...)
+}
+
+// InfoE is like Info, but returns the format error or the write error of the log.
+func (o *log) InfoE(args ...interface{}) error {
+	return o.logE(InfoLevel, args...)
+}
+
+// ErrorE is like Error, but returns the format error or the write error of the log.
+func (o *log) ErrorE(args ...interface{}) error {
+	return o.logE(ErrorLevel, args...)
+}
+
 // Panic uses the given parameters to record a PanicLevel log.
 // After the log record is completed, the system will automatically call
 // the panic function given in advance.
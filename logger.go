@@ -15,9 +15,11 @@
 package logger
 
 import (
+	"context"
 	"io"
 	stdlog "log"
 	"os"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -42,6 +44,22 @@ type Logger interface {
 	// When the given log level string is invalid, this method does nothing.
 	ForceSetLevelString(s string) Logger
 
+	// NewChild creates and returns a child logger named by joining the name of the current
+	// logger and the given name with ChildNameSeparator, such as "app.db". The child logger
+	// inherits the level of the current logger until its own level is set by SetLevel, which
+	// enables the per-module verbosity control. The child logger inherits the fields and the
+	// context of the current logger, and shares all other settings (such as the formatter,
+	// the writers and the hooks) with it, so the setters other than the level setters of the
+	// child logger also apply to the current logger.
+	NewChild(string) Logger
+
+	// SetLevelFor sets the current logger level temporarily, and the previous logger level
+	// is restored automatically after the given duration, or when the returned cancel
+	// function is called. This is useful for the live debugging sessions.
+	// If the logger level is changed by others during the override, it will not be restored.
+	// When the given log level is invalid, this method does nothing.
+	SetLevelFor(time.Duration, Level) (cancel func())
+
 	// SetOutput sets the current logger output writer.
 	// If the given writer is nil, os.Stdout is used.
 	SetOutput(io.Writer) Logger
@@ -56,6 +74,29 @@ type Logger interface {
 	// If the given writer is nil, the levels writer will be disabled.
 	SetLevelsOutput([]Level, io.Writer) Logger
 
+	// SetLevelRangeOutput sets the output writer for all levels between the given two levels
+	// (inclusive, and the order of the given levels does not matter).
+	// For example, SetLevelRangeOutput(WarnLevel, PanicLevel, os.Stderr) writes the warning
+	// and higher level logs to os.Stderr.
+	// If the given writer is nil, the levels writer will be disabled.
+	SetLevelRangeOutput(Level, Level, io.Writer) Logger
+
+	// SetHighPriorityOutput sets the output writer for all high priority levels.
+	// If the given writer is nil, the levels writer will be disabled.
+	SetHighPriorityOutput(io.Writer) Logger
+
+	// SetLowPriorityOutput sets the output writer for all low priority levels.
+	// If the given writer is nil, the levels writer will be disabled.
+	SetLowPriorityOutput(io.Writer) Logger
+
+	// SetOwnedOutput sets the current logger output writer, and the writer is owned by the
+	// current logger, which means that it will be closed by Logger.Close.
+	SetOwnedOutput(io.WriteCloser) Logger
+
+	// SetOwnedLevelOutput sets the current logger level output writer, and the writer is
+	// owned by the current logger, which means that it will be closed by Logger.Close.
+	SetOwnedLevelOutput(Level, io.WriteCloser) Logger
+
 	// SetOutputInterceptor sets the output interceptor for the current logger.
 	// If the given interceptor is nil, the log data is written to the output writer.
 	SetOutputInterceptor(func(Summary, io.Writer) (int, error)) Logger
@@ -64,18 +105,64 @@ type Logger interface {
 	// If the given function is nil, time.Now is used.
 	SetNowFunc(func() time.Time) Logger
 
+	// SetTimePrecision sets the precision of the log time, the log time is truncated to a
+	// multiple of the given precision before it is formatted, such as time.Millisecond.
+	// If the given precision is less than or equal to 0, the log time is not truncated.
+	SetTimePrecision(time.Duration) Logger
+
+	// SetUptimeField adds the elapsed seconds since the given start time to every log as a
+	// numeric field with the given key, which is handy for correlating early-startup issues
+	// across restarts. If the given start time is zero, the process start time is used.
+	// If the given key is empty, the uptime field is disabled.
+	SetUptimeField(string, time.Time) Logger
+
 	// SetExitFunc sets the exit function of the current logger.
 	// If the given function is nil, the exit function is disabled.
 	// The exit function is called automatically after the FatalLevel level log is recorded.
 	// By default, the exit function we use is os.Exit.
 	SetExitFunc(func(int)) Logger
 
+	// SetExitCode sets the exit code passed to the exit function after the FatalLevel
+	// log is recorded, the default exit code is 1.
+	// The exit code can be overridden for a single log by Log.FatalWithCode.
+	SetExitCode(int) Logger
+
+	// AddExitHandler adds a cleanup function called before the exit function after the
+	// FatalLevel log is recorded, such as flushing writers and syncing files.
+	// The exit handlers are called in the reverse order of their registration, and then
+	// the output writers owned by the current logger are closed (see Logger.Close).
+	// The exit function is called after all exit handlers are completed or the exit
+	// timeout is reached, whichever comes first.
+	AddExitHandler(func()) Logger
+
+	// SetExitTimeout sets the maximum wait time for the exit handlers.
+	// If the given timeout is less than or equal to 0, DefaultExitTimeout is used.
+	SetExitTimeout(time.Duration) Logger
+
+	// OnFatal adds a callback called with the summary of the FatalLevel log after the log is
+	// formatted and written, and before the exit handlers and the exit function are called,
+	// so the file writers, the async queues and the remote shippers can be flushed.
+	// The callbacks are called synchronously in the order of their registration, and the
+	// summary is recycled after they return, use Summary.Clone to hold it.
+	OnFatal(func(Summary)) Logger
+
+	// OnPanic adds a callback called with the summary of the PanicLevel log after the log is
+	// formatted and written, and before the panic function is called, see Logger.OnFatal.
+	OnPanic(func(Summary)) Logger
+
 	// SetPanicFunc sets the panic function of the current logger.
 	// If the given function is nil, the panic function is disabled.
 	// The panic function is called automatically after the PanicLevel level log is recorded.
 	// By default, the panic function we use is func(s string) { panic(s) }.
 	SetPanicFunc(func(string)) Logger
 
+	// SetPanicSummaryFunc sets the panic function that receives the summary of the PanicLevel
+	// log, so the custom panic handlers can forward the full structured log to crash reporters.
+	// When it is set, it is called instead of the panic function set by SetPanicFunc.
+	// The summary is recycled after the function returns, use Summary.Clone to hold it.
+	// If the given function is nil, the panic function set by SetPanicFunc is used.
+	SetPanicSummaryFunc(func(Summary)) Logger
+
 	// SetFormatter sets the log formatter for the current logger.
 	// If the given log formatter is nil, we will record the log in JSON format.
 	SetFormatter(Formatter) Logger
@@ -103,18 +190,90 @@ type Logger interface {
 	// This method only takes effect for the log with caller enabled.
 	SetCallerSkip(int) Logger
 
+	// SetCallerSkipPrefixes sets the function name prefixes (such as the package paths of the
+	// logger wrappers) skipped by the caller reporter, the caller reporter walks the call stack
+	// upward until it leaves the functions with the given prefixes, so the wrapper packages do
+	// not have to tune the skipped callers at every level. If no prefix is given, it is disabled.
+	SetCallerSkipPrefixes(...string) Logger
+
 	// SetLongCaller sets whether to enable or disable long caller name (with parent directory name).
 	SetLongCaller(long bool) Logger
 
+	// SetLevelsLongCaller sets whether to enable or disable long caller name on logs of the
+	// given levels, such as the long caller name only for the ErrorLevel and above, it takes
+	// precedence over SetLongCaller. The invalid levels are ignored.
+	SetLevelsLongCaller([]Level, bool) Logger
+
 	// AddHook adds the given log hook to the current logger.
 	AddHook(Hook) Logger
 
 	// AddHookFunc adds the given log hook function to the current logger.
 	AddHookFunc([]Level, func(Summary) error) Logger
 
+	// AddAsyncHook adds the given log hook to the current logger, the hook is fired in a pool
+	// of background workers with a bounded queue of the given size, see NewAsyncHook.
+	// The async hook is owned by the current logger, which means that the queued log
+	// summaries are drained and the hook is closed by Logger.Close.
+	AddAsyncHook(Hook, int) Logger
+
 	// EnableHook enables or disables the log hook.
 	EnableHook(bool) Logger
 
+	// EnableLevelHooks enables or disables the log hooks of the given levels at runtime, so
+	// the expensive hooks can be suppressed for the chatty levels while staying active for
+	// the important levels. It only takes effect when the log hooks are enabled by EnableHook.
+	EnableLevelHooks([]Level, bool) Logger
+
+	// EnableProcessMetadata enables or disables the process metadata of the logs, including
+	// the process id, the host name and the log sequence number (see Entity.PID, Entity.Hostname
+	// and Entity.Sequence). The process id and the host name are queried only once when enabled.
+	EnableProcessMetadata(bool) Logger
+
+	// SetRecordIDFunc sets the function that generates the unique id of every log (see Entity.ID),
+	// such as NewCorrelationID which generates the time-ordered UUIDs, so that the individual logs
+	// can be referenced exactly across systems. If the given function is nil, the record id is disabled.
+	SetRecordIDFunc(func() string) Logger
+
+	// SetEventCatalog sets the event catalog used by Log.Event.
+	SetEventCatalog(EventCatalog) Logger
+
+	// SetFieldOverwritePolicy sets the policy applied when the added log field overwrites an
+	// existing field of the log, such as WithField("error", err) on a log with the error field.
+	// By default, the existing field is replaced silently.
+	SetFieldOverwritePolicy(FieldOverwritePolicy) Logger
+
+	// EnableFieldDeepMerge enables or disables the deep merge of the log fields, when both the
+	// existing and the added field values are map[string]interface{}, they are merged recursively
+	// instead of being replaced, so the layered enrichment from different middlewares composes.
+	// The field overwrite policy is not applied to the merged fields.
+	EnableFieldDeepMerge(bool) Logger
+
+	// SetRingSink sets the ring sink that keeps the last logs of all levels, regardless of the
+	// logger level. When it is set, the logs below the logger level are built and recorded into
+	// the ring sink, but they are not formatted, written or passed to the hooks.
+	// If the given ring sink is nil, the ring sink is disabled.
+	SetRingSink(RingSink) Logger
+
+	// SetSampler sets the log sampler that throttles the repeated logs (the logs with the same
+	// level and message) within a sampling window, see SamplerConfig for details.
+	// If both the Initial and the Thereafter of the given config are 0, the sampler is disabled.
+	SetSampler(SamplerConfig) Logger
+
+	// SetDeduplication sets the window in which the identical consecutive logs (the logs with
+	// the same level and message) are collapsed. The first log of a run is recorded as usual,
+	// and the repeated logs are recorded as a single log carrying the RepeatCountField field
+	// (the number of the repeated logs) when the run is broken by another log or the window
+	// ends. The FatalLevel and PanicLevel logs are never collapsed.
+	// If the given window is less than or equal to 0, the deduplication is disabled.
+	SetDeduplication(time.Duration) Logger
+
+	// Replay records the given log summary (such as the one deserialized by UnmarshalSummary)
+	// with its original time, level, message, fields, caller and stack, by the formatter, the
+	// hooks and the writer of the current logger. The logs below the logger level are discarded.
+	// Replaying the FatalLevel and PanicLevel logs never calls the exit or panic function.
+	// The returned error is the format error or the write error of the log.
+	Replay(Summary) error
+
 	// AsLog converts current Logger to Log instances, which is unidirectional.
 	AsLog() Log
 
@@ -123,6 +282,84 @@ type Logger interface {
 
 	// SetStackPrefixFilter sets the call stack prefix filter rules.
 	SetStackPrefixFilter(...string) Logger
+
+	// SetInitialBufferSize sets the initial size of the log entity buffer.
+	// If the given size is less than or equal to 0, the buffer is not pre-allocated.
+	SetInitialBufferSize(int) Logger
+
+	// SetMaxBufferSize sets the maximum size of the log entity buffer retained for reuse.
+	// Buffers larger than this size are discarded after the log is written.
+	// If the given size is 0, the default size (4KB) is used, and if the given size is
+	// less than 0, the buffers are never discarded.
+	SetMaxBufferSize(int) Logger
+
+	// SetStreamThreshold sets the size threshold for streaming logs.
+	// When the formatter implements the StreamFormatter interface and the size of the log
+	// exceeds the given threshold, the log will be written directly to the log writer in
+	// chunks instead of being buffered. The log hooks do not see the content of the streamed
+	// logs, they will receive an empty log content. The chunks of one log are written while
+	// holding a lock of the log writer (the lock of the writer created by NewMutexWriter), so
+	// the concurrent streamed logs are not interleaved.
+	// Streaming is not used when the format output or output interceptor is set.
+	// If the given threshold is less than or equal to 0, streaming is disabled.
+	SetStreamThreshold(int) Logger
+
+	// SetMaxRecordSize sets the maximum size of a single formatted log.
+	// The log data exceeding the maximum size will be truncated and a truncation marker
+	// will be appended to it, which is applied after formatting and before the log hooks.
+	// The streamed logs are never truncated.
+	// If the given size is less than or equal to 0, the log size is not limited.
+	SetMaxRecordSize(int) Logger
+
+	// GetTruncatedCount returns the number of logs truncated by the maximum record size.
+	GetTruncatedCount() uint64
+
+	// SetLatencySampling enables the format and write latency instrumentation, one of every
+	// given number of records is measured. The given durations are the upper bounds of the
+	// histogram buckets, and DefaultLatencyBuckets is used if they are not given.
+	// If the given number is less than or equal to 0, the instrumentation is disabled.
+	SetLatencySampling(int, ...time.Duration) Logger
+
+	// GetLatencyStats returns the sampled format and write latency statistics.
+	// If the latency instrumentation is disabled, the zero statistics are returned.
+	GetLatencyStats() LatencyStats
+
+	// AddContextFieldExtractor adds a context field extractor to the current logger.
+	// When calling Log.WithContext, the fields returned by the extractors are added to the log
+	// immediately, so that they are visible to all formatters and hooks.
+	AddContextFieldExtractor(func(context.Context) map[string]interface{}) Logger
+
+	// AddContextField registers a context value as a log field.
+	// When calling Log.WithContext, if the given context carries a non-nil value of the given key,
+	// the value is added to the log as a field with the given name.
+	AddContextField(name string, key interface{}) Logger
+
+	// EnableCorrelationID adds the correlation id carried by the log context to the log fields.
+	// The correlation id is added when calling Log.WithContext, see WithCorrelationID and
+	// EnsureCorrelationID for how to install the correlation id into the context.
+	// If the given field name is empty, DefaultCorrelationIDField is used.
+	EnableCorrelationID(field string) Logger
+
+	// EnableTraceContext adds the trace context carried by the log context to the log fields.
+	// The trace context is added when calling Log.WithContext, see WithTraceContext and
+	// WithTraceparent for how to install the trace context into the context.
+	EnableTraceContext() Logger
+
+	// SetRecordTerminator sets the terminator of the log records, such as "\r\n" or "\x00".
+	// The trailing newline of each formatted log will be replaced by the given terminator.
+	// The streamed logs are never changed.
+	// If the given terminator is empty string or "\n", the newline is used.
+	SetRecordTerminator(string) Logger
+
+	// Rotate rotates all output writers of the current logger that implement the Rotator
+	// interface, such as the log file writers, and returns the first error encountered.
+	Rotate() error
+
+	// Close closes all output writers owned by the current logger in the reverse order
+	// of their registration, and returns the first error encountered.
+	// Each owned writer is closed only once, and the logger should not be used after closing.
+	// The pending summary logs of the sampler are recorded before the writers are closed.
+	Close() error
 }
 
 // New creates a new Logger instance.
@@ -138,18 +375,87 @@ type logger struct {
 
 // GetLevel returns the current logger level.
 func (o *logger) GetLevel() Level {
-	return Level(atomic.LoadUint32(&o.core.level))
+	return o.getLevel()
 }
 
 // SetLevel sets the current logger level.
 // When the given log level is invalid, this method does nothing.
 func (o *logger) SetLevel(level Level) Logger {
 	if level.IsValid() {
-		atomic.StoreUint32(&o.core.level, uint32(level))
+		o.storeLevel(level)
 	}
 	return o
 }
 
+// Stores the given level of the current logger without validation, the level 0 of the
+// child logger means that the parent level is inherited.
+func (o *logger) storeLevel(level Level) {
+	if o.scope != nil {
+		atomic.StoreUint32(&o.scope.level, uint32(level))
+	} else {
+		atomic.StoreUint32(&o.core.level, uint32(level))
+	}
+}
+
+// Returns the stored level of the current logger, which is 0 for the child logger that
+// inherits the parent level.
+func (o *logger) loadLevel() Level {
+	if o.scope != nil {
+		return Level(atomic.LoadUint32(&o.scope.level))
+	}
+	return Level(atomic.LoadUint32(&o.core.level))
+}
+
+// SetLevelFor sets the current logger level temporarily, and the previous logger level
+// is restored automatically after the given duration, or when the returned cancel
+// function is called. This is useful for the live debugging sessions.
+// If the logger level is changed by others during the override, it will not be restored.
+// When the given log level is invalid, this method does nothing.
+func (o *logger) SetLevelFor(d time.Duration, level Level) (cancel func()) {
+	if !level.IsValid() {
+		return func() {}
+	}
+	c := o.getLevelOverride()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer == nil {
+		c.restore = o.loadLevel()
+	} else {
+		// The later override replaces the current override, but the original logger
+		// level is still restored.
+		c.timer.Stop()
+	}
+	c.gen++
+	gen := c.gen
+	c.level = level
+	o.SetLevel(level)
+	c.timer = time.AfterFunc(d, func() { o.restoreLevel(gen) })
+	return func() { o.restoreLevel(gen) }
+}
+
+// Returns the temporary logger level override of the current logger.
+func (o *logger) getLevelOverride() *levelOverride {
+	if o.scope != nil {
+		return &o.scope.override
+	}
+	return &o.core.override
+}
+
+// Restores the logger level overridden by SetLevelFor of the given generation.
+func (o *logger) restoreLevel(gen uint64) {
+	c := o.getLevelOverride()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer == nil || c.gen != gen {
+		return
+	}
+	c.timer.Stop()
+	c.timer = nil
+	if o.GetLevel() == c.level {
+		o.storeLevel(c.restore)
+	}
+}
+
 // SetLevelString sets the current logger level by string.
 func (o *logger) SetLevelString(s string) error {
 	level, err := ParseLevel(s)
@@ -169,6 +475,28 @@ func (o *logger) ForceSetLevelString(s string) Logger {
 	return o
 }
 
+// ChildNameSeparator is the separator between the names of the parent and the child loggers.
+const ChildNameSeparator = "."
+
+// NewChild creates and returns a child logger named by joining the name of the current
+// logger and the given name with ChildNameSeparator, such as "app.db". The child logger
+// inherits the level of the current logger until its own level is set by SetLevel, which
+// enables the per-module verbosity control. The child logger inherits the fields and the
+// context of the current logger, and shares all other settings (such as the formatter,
+// the writers and the hooks) with it, so the setters other than the level setters of the
+// child logger also apply to the current logger.
+func (o *logger) NewChild(name string) Logger {
+	if parent := o.Name(); parent != "" && name != "" {
+		name = parent + ChildNameSeparator + name
+	} else if name == "" {
+		name = parent
+	}
+	return &logger{log{
+		core: o.core, scope: &logScope{parent: o.scope, name: name}, fields: o.fields, ctx: o.ctx,
+		caller: o.caller, prefix: o.prefix, stack: o.stack,
+	}}
+}
+
 // SetOutput sets the current logger output writer.
 // If the given writer is nil, os.Stdout is used.
 func (o *logger) SetOutput(w io.Writer) Logger {
@@ -202,6 +530,53 @@ func (o *logger) SetLevelsOutput(levels []Level, w io.Writer) Logger {
 	return o
 }
 
+// SetLevelRangeOutput sets the output writer for all levels between the given two levels
+// (inclusive, and the order of the given levels does not matter).
+// For example, SetLevelRangeOutput(WarnLevel, PanicLevel, os.Stderr) writes the warning
+// and higher level logs to os.Stderr.
+// If the given writer is nil, the levels writer will be disabled.
+func (o *logger) SetLevelRangeOutput(min, max Level, w io.Writer) Logger {
+	if min > max {
+		min, max = max, min
+	}
+	for _, level := range GetAllLevels() {
+		if level >= min && level <= max {
+			o.SetLevelOutput(level, w)
+		}
+	}
+	return o
+}
+
+// SetHighPriorityOutput sets the output writer for all high priority levels.
+// If the given writer is nil, the levels writer will be disabled.
+func (o *logger) SetHighPriorityOutput(w io.Writer) Logger {
+	return o.SetLevelsOutput(GetHighPriorityLevels(), w)
+}
+
+// SetLowPriorityOutput sets the output writer for all low priority levels.
+// If the given writer is nil, the levels writer will be disabled.
+func (o *logger) SetLowPriorityOutput(w io.Writer) Logger {
+	return o.SetLevelsOutput(GetLowPriorityLevels(), w)
+}
+
+// SetOwnedOutput sets the current logger output writer, and the writer is owned by the
+// current logger, which means that it will be closed by Logger.Close.
+func (o *logger) SetOwnedOutput(w io.WriteCloser) Logger {
+	if w != nil {
+		o.core.owned = append(o.core.owned, w)
+	}
+	return o.SetOutput(w)
+}
+
+// SetOwnedLevelOutput sets the current logger level output writer, and the writer is
+// owned by the current logger, which means that it will be closed by Logger.Close.
+func (o *logger) SetOwnedLevelOutput(level Level, w io.WriteCloser) Logger {
+	if w != nil {
+		o.core.owned = append(o.core.owned, w)
+	}
+	return o.SetLevelOutput(level, w)
+}
+
 // SetOutputInterceptor sets the output interceptor for the current logger.
 // If the given interceptor is nil, the log data is written to the output writer.
 func (o *logger) SetOutputInterceptor(f func(Summary, io.Writer) (int, error)) Logger {
@@ -220,6 +595,30 @@ func (o *logger) SetNowFunc(f func() time.Time) Logger {
 	return o
 }
 
+// SetTimePrecision sets the precision of the log time, the log time is truncated to a
+// multiple of the given precision before it is formatted, such as time.Millisecond.
+// If the given precision is less than or equal to 0, the log time is not truncated.
+func (o *logger) SetTimePrecision(d time.Duration) Logger {
+	if d > 0 {
+		o.core.timePrecision = d
+	} else {
+		o.core.timePrecision = 0
+	}
+	return o
+}
+
+// SetUptimeField adds the elapsed seconds since the given start time to every log as a
+// numeric field with the given key, which is handy for correlating early-startup issues
+// across restarts. If the given start time is zero, the process start time is used.
+// If the given key is empty, the uptime field is disabled.
+func (o *logger) SetUptimeField(key string, start time.Time) Logger {
+	if start.IsZero() {
+		start = processStartTime
+	}
+	o.core.uptimeField, o.core.uptimeStart = key, start
+	return o
+}
+
 // SetExitFunc sets the exit function of the current logger.
 // If the given function is nil, the exit function is disabled.
 // The exit function is called automatically after the FatalLevel level log is recorded.
@@ -233,6 +632,59 @@ func (o *logger) SetExitFunc(f func(int)) Logger {
 	return o
 }
 
+// SetExitCode sets the exit code passed to the exit function after the FatalLevel
+// log is recorded, the default exit code is 1.
+// The exit code can be overridden for a single log by Log.FatalWithCode.
+func (o *logger) SetExitCode(code int) Logger {
+	o.core.exitCode = code
+	return o
+}
+
+// AddExitHandler adds a cleanup function called before the exit function after the
+// FatalLevel log is recorded, such as flushing writers and syncing files.
+// The exit handlers are called in the reverse order of their registration, and then
+// the output writers owned by the current logger are closed (see Logger.Close).
+// The exit function is called after all exit handlers are completed or the exit
+// timeout is reached, whichever comes first.
+func (o *logger) AddExitHandler(f func()) Logger {
+	if f != nil {
+		o.core.exitHandlers = append(o.core.exitHandlers, f)
+	}
+	return o
+}
+
+// SetExitTimeout sets the maximum wait time for the exit handlers.
+// If the given timeout is less than or equal to 0, DefaultExitTimeout is used.
+func (o *logger) SetExitTimeout(timeout time.Duration) Logger {
+	if timeout <= 0 {
+		o.core.exitTimeout = DefaultExitTimeout
+	} else {
+		o.core.exitTimeout = timeout
+	}
+	return o
+}
+
+// OnFatal adds a callback called with the summary of the FatalLevel log after the log is
+// formatted and written, and before the exit handlers and the exit function are called,
+// so the file writers, the async queues and the remote shippers can be flushed.
+// The callbacks are called synchronously in the order of their registration, and the
+// summary is recycled after they return, use Summary.Clone to hold it.
+func (o *logger) OnFatal(f func(Summary)) Logger {
+	if f != nil {
+		o.core.fatalHandlers = append(o.core.fatalHandlers, f)
+	}
+	return o
+}
+
+// OnPanic adds a callback called with the summary of the PanicLevel log after the log is
+// formatted and written, and before the panic function is called, see Logger.OnFatal.
+func (o *logger) OnPanic(f func(Summary)) Logger {
+	if f != nil {
+		o.core.panicHandlers = append(o.core.panicHandlers, f)
+	}
+	return o
+}
+
 // SetPanicFunc sets the panic function of the current logger.
 // If the given function is nil, the panic function is disabled.
 // The panic function is called automatically after the PanicLevel level log is recorded.
@@ -246,6 +698,16 @@ func (o *logger) SetPanicFunc(f func(string)) Logger {
 	return o
 }
 
+// SetPanicSummaryFunc sets the panic function that receives the summary of the PanicLevel
+// log, so the custom panic handlers can forward the full structured log to crash reporters.
+// When it is set, it is called instead of the panic function set by SetPanicFunc.
+// The summary is recycled after the function returns, use Summary.Clone to hold it.
+// If the given function is nil, the panic function set by SetPanicFunc is used.
+func (o *logger) SetPanicSummaryFunc(f func(Summary)) Logger {
+	o.core.panicSummary = f
+	return o
+}
+
 // SetFormatter sets the log formatter for the current logger.
 // If the given log formatter is nil, we will record the log in JSON format.
 func (o *logger) SetFormatter(formatter Formatter) Logger {
@@ -307,12 +769,36 @@ func (o *logger) SetCallerSkip(skip int) Logger {
 	return o
 }
 
+// SetCallerSkipPrefixes sets the function name prefixes (such as the package paths of the
+// logger wrappers) skipped by the caller reporter, the caller reporter walks the call stack
+// upward until it leaves the functions with the given prefixes, so the wrapper packages do
+// not have to tune the skipped callers at every level. If no prefix is given, it is disabled.
+func (o *logger) SetCallerSkipPrefixes(prefixes ...string) Logger {
+	o.core.callerPrefixes = append([]string(nil), prefixes...)
+	return o
+}
+
 // SetLongCaller sets whether to enable or disable long caller name (with parent directory name).
 func (o *logger) SetLongCaller(long bool) Logger {
 	o.core.callerLong = long
 	return o
 }
 
+// SetLevelsLongCaller sets whether to enable or disable long caller name on logs of the
+// given levels, such as the long caller name only for the ErrorLevel and above, it takes
+// precedence over SetLongCaller. The invalid levels are ignored.
+func (o *logger) SetLevelsLongCaller(levels []Level, long bool) Logger {
+	if o.core.levelLong == nil {
+		o.core.levelLong = make(map[Level]bool, len(levels))
+	}
+	for _, level := range levels {
+		if level.IsValid() {
+			o.core.levelLong[level] = long
+		}
+	}
+	return o
+}
+
 // EnableLevelsCaller enables caller reporting on logs of the given levels.
 func (o *logger) EnableLevelsCaller(levels []Level, skip ...int) Logger {
 	for i, j := 0, len(levels); i < j; i++ {
@@ -332,12 +818,166 @@ func (o *logger) AddHookFunc(levels []Level, hook func(Summary) error) Logger {
 	return o.AddHook(NewHookFromFunc(levels, hook))
 }
 
+// AddAsyncHook adds the given log hook to the current logger, the hook is fired in a pool
+// of background workers with a bounded queue of the given size, see NewAsyncHook.
+// The async hook is owned by the current logger, which means that the queued log
+// summaries are drained and the hook is closed by Logger.Close.
+func (o *logger) AddAsyncHook(hook Hook, size int) Logger {
+	h := NewAsyncHook(hook, size, 0)
+	o.core.owned = append(o.core.owned, h)
+	return o.AddHook(h)
+}
+
 // EnableHook enables or disables the log hook.
 func (o *logger) EnableHook(ok bool) Logger {
 	o.core.enableHooks = ok
 	return o
 }
 
+// EnableLevelHooks enables or disables the log hooks of the given levels at runtime, so
+// the expensive hooks can be suppressed for the chatty levels while staying active for
+// the important levels. It only takes effect when the log hooks are enabled by EnableHook.
+func (o *logger) EnableLevelHooks(levels []Level, enable bool) Logger {
+	var mask uint32
+	for _, level := range levels {
+		if level.IsValid() {
+			mask |= 1 << level
+		}
+	}
+	for {
+		old := atomic.LoadUint32(&o.core.disabledHooks)
+		v := old | mask
+		if enable {
+			v = old &^ mask
+		}
+		if atomic.CompareAndSwapUint32(&o.core.disabledHooks, old, v) {
+			return o
+		}
+	}
+}
+
+// EnableProcessMetadata enables or disables the process metadata of the logs, including
+// the process id, the host name and the log sequence number (see Entity.PID, Entity.Hostname
+// and Entity.Sequence). The process id and the host name are queried only once when enabled.
+func (o *logger) EnableProcessMetadata(enable bool) Logger {
+	if enable && !o.core.processMeta {
+		o.core.pid = os.Getpid()
+		if hostname, err := os.Hostname(); err == nil {
+			o.core.hostname = hostname
+		} else {
+			internal.EchoError("(%s) Failed to get host name: %s", o.core.name, err)
+		}
+	}
+	o.core.processMeta = enable
+	return o
+}
+
+// SetRecordIDFunc sets the function that generates the unique id of every log (see Entity.ID),
+// such as NewCorrelationID which generates the time-ordered UUIDs, so that the individual logs
+// can be referenced exactly across systems. If the given function is nil, the record id is disabled.
+func (o *logger) SetRecordIDFunc(f func() string) Logger {
+	o.core.recordID = f
+	return o
+}
+
+// SetEventCatalog sets the event catalog used by Log.Event.
+func (o *logger) SetEventCatalog(c EventCatalog) Logger {
+	o.core.events = c
+	return o
+}
+
+// SetFieldOverwritePolicy sets the policy applied when the added log field overwrites an
+// existing field of the log, such as WithField("error", err) on a log with the error field.
+// By default, the existing field is replaced silently.
+func (o *logger) SetFieldOverwritePolicy(policy FieldOverwritePolicy) Logger {
+	o.core.fieldPolicy = policy
+	return o
+}
+
+// EnableFieldDeepMerge enables or disables the deep merge of the log fields, when both the
+// existing and the added field values are map[string]interface{}, they are merged recursively
+// instead of being replaced, so the layered enrichment from different middlewares composes.
+// The field overwrite policy is not applied to the merged fields.
+func (o *logger) EnableFieldDeepMerge(enable bool) Logger {
+	o.core.fieldDeepMerge = enable
+	return o
+}
+
+// SetRingSink sets the ring sink that keeps the last logs of all levels, regardless of the
+// logger level. When it is set, the logs below the logger level are built and recorded into
+// the ring sink, but they are not formatted, written or passed to the hooks.
+// If the given ring sink is nil, the ring sink is disabled.
+func (o *logger) SetRingSink(r RingSink) Logger {
+	o.core.ring = r
+	return o
+}
+
+// SetSampler sets the log sampler that throttles the repeated logs (the logs with the same
+// level and message) within a sampling window, see SamplerConfig for details.
+// If both the Initial and the Thereafter of the given config are 0, the sampler is disabled.
+func (o *logger) SetSampler(c SamplerConfig) Logger {
+	if s := o.core.sampler; s != nil {
+		s.flush()
+	}
+	if c.Initial == 0 && c.Thereafter == 0 {
+		o.core.sampler = nil
+	} else {
+		o.core.sampler = newLogSampler(c)
+	}
+	return o
+}
+
+// SetDeduplication sets the window in which the identical consecutive logs (the logs with
+// the same level and message) are collapsed. The first log of a run is recorded as usual,
+// and the repeated logs are recorded as a single log carrying the RepeatCountField field
+// (the number of the repeated logs) when the run is broken by another log or the window
+// ends. The FatalLevel and PanicLevel logs are never collapsed.
+// If the given window is less than or equal to 0, the deduplication is disabled.
+func (o *logger) SetDeduplication(window time.Duration) Logger {
+	if d := o.core.dedup; d != nil {
+		d.flush()
+	}
+	if window <= 0 {
+		o.core.dedup = nil
+	} else {
+		o.core.dedup = newLogDeduplicator(window)
+	}
+	return o
+}
+
+// Replay records the given log summary (such as the one deserialized by UnmarshalSummary)
+// with its original time, level, message, fields, caller and stack, by the formatter, the
+// hooks and the writer of the current logger. The logs below the logger level are discarded.
+// Replaying the FatalLevel and PanicLevel logs never calls the exit or panic function.
+// The returned error is the format error or the write error of the log.
+func (o *logger) Replay(s Summary) error {
+	level := s.Level()
+	if !level.IsValid() || !o.isEnabled(level) {
+		return nil
+	}
+	r := &log{core: o.core, scope: o.scope}
+	if s.HasContext() {
+		r.ctx = s.Context()
+	}
+	if s.HasFields() {
+		r.fields = internal.MakeFields(s.Fields())
+	}
+	entity := o.core.getEntity(r, level, s.Message(), s.Caller())
+	defer o.core.putEntity(entity)
+
+	entity.time = s.Time()
+	if stack := s.Stack(); len(stack) > 0 {
+		entity.stack = append([]string(nil), stack...)
+	}
+	if seq := s.Sequence(); seq > 0 {
+		entity.pid, entity.hostname, entity.sequence = s.PID(), s.Hostname(), seq
+	}
+	if id := s.ID(); id != "" {
+		entity.id = id
+	}
+	return r.emit(entity, s.Message(), false)
+}
+
 // AsLog converts current Logger to Log instances, which is unidirectional.
 func (o *logger) AsLog() Log {
 	return &o.log
@@ -353,3 +993,188 @@ func (o *logger) SetStackPrefixFilter(prefixes ...string) Logger {
 	o.core.stackPrefixes = internal.FormatKnownStackPrefixes(prefixes...)
 	return o
 }
+
+// SetInitialBufferSize sets the initial size of the log entity buffer.
+// If the given size is less than or equal to 0, the buffer is not pre-allocated.
+func (o *logger) SetInitialBufferSize(n int) Logger {
+	if n < 0 {
+		n = 0
+	}
+	o.core.bufferSize = n
+	return o
+}
+
+// SetMaxBufferSize sets the maximum size of the log entity buffer retained for reuse.
+// Buffers larger than this size are discarded after the log is written.
+// If the given size is 0, the default size (4KB) is used, and if the given size is
+// less than 0, the buffers are never discarded.
+func (o *logger) SetMaxBufferSize(n int) Logger {
+	switch {
+	case n == 0:
+		o.core.maxBufferSize = defaultMaxBufferSize
+	case n < 0:
+		o.core.maxBufferSize = 0
+	default:
+		o.core.maxBufferSize = n
+	}
+	return o
+}
+
+// SetStreamThreshold sets the size threshold for streaming logs.
+// When the formatter implements the StreamFormatter interface and the size of the log
+// exceeds the given threshold, the log will be written directly to the log writer in
+// chunks instead of being buffered. The log hooks do not see the content of the streamed
+// logs, they will receive an empty log content. The chunks of one log are written while
+// holding a lock of the log writer (the lock of the writer created by NewMutexWriter), so
+// the concurrent streamed logs are not interleaved.
+// Streaming is not used when the format output or output interceptor is set.
+// If the given threshold is less than or equal to 0, streaming is disabled.
+func (o *logger) SetStreamThreshold(n int) Logger {
+	if n < 0 {
+		n = 0
+	}
+	o.core.streamSize = n
+	return o
+}
+
+// SetMaxRecordSize sets the maximum size of a single formatted log.
+// The log data exceeding the maximum size will be truncated and a truncation marker
+// will be appended to it, which is applied after formatting and before the log hooks.
+// The streamed logs are never truncated.
+// If the given size is less than or equal to 0, the log size is not limited.
+func (o *logger) SetMaxRecordSize(n int) Logger {
+	if n < 0 {
+		n = 0
+	}
+	o.core.maxRecordSize = n
+	return o
+}
+
+// GetTruncatedCount returns the number of logs truncated by the maximum record size.
+func (o *logger) GetTruncatedCount() uint64 {
+	return atomic.LoadUint64(&o.core.truncated)
+}
+
+// SetLatencySampling enables the format and write latency instrumentation, one of every
+// given number of records is measured. The given durations are the upper bounds of the
+// histogram buckets, and DefaultLatencyBuckets is used if they are not given.
+// If the given number is less than or equal to 0, the instrumentation is disabled.
+func (o *logger) SetLatencySampling(every int, buckets ...time.Duration) Logger {
+	if every <= 0 {
+		o.core.latency = nil
+		return o
+	}
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	bounds := make([]time.Duration, len(buckets))
+	copy(bounds, buckets)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+	o.core.latency = newLatencyRecorder(every, bounds)
+	return o
+}
+
+// GetLatencyStats returns the sampled format and write latency statistics.
+// If the latency instrumentation is disabled, the zero statistics are returned.
+func (o *logger) GetLatencyStats() LatencyStats {
+	if o.core.latency == nil {
+		return LatencyStats{}
+	}
+	return o.core.latency.stats()
+}
+
+// AddContextFieldExtractor adds a context field extractor to the current logger.
+// When calling Log.WithContext, the fields returned by the extractors are added to the log
+// immediately, so that they are visible to all formatters and hooks.
+func (o *logger) AddContextFieldExtractor(f func(context.Context) map[string]interface{}) Logger {
+	if f != nil {
+		o.core.ctxExtractors = append(o.core.ctxExtractors, f)
+	}
+	return o
+}
+
+// AddContextField registers a context value as a log field.
+// When calling Log.WithContext, if the given context carries a non-nil value of the given key,
+// the value is added to the log as a field with the given name.
+func (o *logger) AddContextField(name string, key interface{}) Logger {
+	return o.AddContextFieldExtractor(func(ctx context.Context) map[string]interface{} {
+		if v := ctx.Value(key); v != nil {
+			return map[string]interface{}{name: v}
+		}
+		return nil
+	})
+}
+
+// EnableCorrelationID adds the correlation id carried by the log context to the log fields.
+// The correlation id is added when calling Log.WithContext, see WithCorrelationID and
+// EnsureCorrelationID for how to install the correlation id into the context.
+// If the given field name is empty, DefaultCorrelationIDField is used.
+func (o *logger) EnableCorrelationID(field string) Logger {
+	if field == "" {
+		field = DefaultCorrelationIDField
+	}
+	return o.AddContextFieldExtractor(func(ctx context.Context) map[string]interface{} {
+		if id := GetCorrelationID(ctx); id != "" {
+			return map[string]interface{}{field: id}
+		}
+		return nil
+	})
+}
+
+// EnableTraceContext adds the trace context carried by the log context to the log fields.
+// The trace context is added when calling Log.WithContext, see WithTraceContext and
+// WithTraceparent for how to install the trace context into the context.
+func (o *logger) EnableTraceContext() Logger {
+	return o.AddContextFieldExtractor(func(ctx context.Context) map[string]interface{} {
+		if tc, found := GetTraceContext(ctx); found {
+			return tc.Fields()
+		}
+		return nil
+	})
+}
+
+// SetRecordTerminator sets the terminator of the log records, such as "\r\n" or "\x00".
+// The trailing newline of each formatted log will be replaced by the given terminator.
+// The streamed logs are never changed.
+// If the given terminator is empty string or "\n", the newline is used.
+func (o *logger) SetRecordTerminator(terminator string) Logger {
+	if terminator == "\n" {
+		terminator = ""
+	}
+	o.core.terminator = terminator
+	return o
+}
+
+// Rotate rotates all output writers of the current logger that implement the Rotator
+// interface, such as the log file writers, and returns the first error encountered.
+func (o *logger) Rotate() error {
+	writers := []io.Writer{o.core.writer}
+	levels := GetAllLevels()
+	for i, j := 0, len(levels); i < j; i++ {
+		if w, found := o.core.levelWriter[levels[i]]; found {
+			writers = append(writers, w)
+		}
+	}
+	return rotateWriters(writers)
+}
+
+// Close closes all output writers owned by the current logger in the reverse order
+// of their registration, and returns the first error encountered.
+// Each owned writer is closed only once, and the logger should not be used after closing.
+// The pending summary logs of the sampler are recorded before the writers are closed.
+func (o *logger) Close() (err error) {
+	if s := o.core.sampler; s != nil {
+		s.flush()
+	}
+	var closed []io.Closer
+	for i := len(o.core.owned) - 1; i >= 0; i-- {
+		if c := o.core.owned[i]; !containsCloser(closed, c) {
+			closed = append(closed, c)
+			if e := c.Close(); err == nil {
+				err = e
+			}
+		}
+	}
+	o.core.owned = nil
+	return
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testCallerWrapperLog(o Log, msg string) {
+	o.Info(msg)
+}
+
+func testCallerWrapperInfo(o Log, msg string) {
+	testCallerWrapperLog(o, msg)
+}
+
+func TestLogger_SetCallerSkipPrefixes(t *testing.T) {
+	w := new(bytes.Buffer)
+	o := New("test")
+	o.SetOutput(w)
+	o.EnableCaller()
+	if o.SetCallerSkipPrefixes("github.com/edoger/zkits-logger.testCallerWrapper") == nil {
+		t.Fatal("Logger.SetCallerSkipPrefixes(): nil")
+	}
+
+	testCallerWrapperInfo(o, "test") // LINE 0
+
+	got := w.String()
+	if !strings.Contains(got, "logger_caller_skip_test.go:"+testCallerLine(t, "// LINE 0")) {
+		t.Fatalf("Logger caller: %s", got)
+	}
+
+	w.Reset()
+	o.SetCallerSkipPrefixes()
+	testCallerWrapperInfo(o, "test")
+
+	got = w.String()
+	if !strings.Contains(got, "logger_caller_skip_test.go:"+testCallerLine(t, "\to.Info(msg)")) {
+		t.Fatalf("Logger caller: %s", got)
+	}
+}
+
+// Returns the line number of the first line containing the given text in this file.
+func testCallerLine(t *testing.T, text string) string {
+	data, err := os.ReadFile("logger_caller_skip_test.go")
+	if err != nil {
+		t.Fatalf("os.ReadFile(): error %s", err)
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, text) {
+			return strconv.Itoa(i + 1)
+		}
+	}
+	t.Fatalf("testCallerLine(): %q not found", text)
+	return ""
+}
+
+func TestLogger_SetLevelsLongCaller(t *testing.T) {
+	w := new(bytes.Buffer)
+	o := New("test")
+	o.SetOutput(w)
+	o.EnableCaller()
+	o.SetFormatter(FormatterFunc(func(e Entity, b *bytes.Buffer) error {
+		b.WriteString(e.Caller())
+		return nil
+	}))
+	if o.SetLevelsLongCaller([]Level{ErrorLevel, Level(100)}, true) == nil {
+		t.Fatal("Logger.SetLevelsLongCaller(): nil")
+	}
+
+	o.Info("test")
+	if got := w.String(); !strings.HasPrefix(got, "logger_caller_skip_test.go:") {
+		t.Fatalf("Logger.SetLevelsLongCaller(): %s", got)
+	}
+	w.Reset()
+	o.Error("test")
+	if got := w.String(); !strings.Contains(got, "/logger_caller_skip_test.go:") {
+		t.Fatalf("Logger.SetLevelsLongCaller(): %s", got)
+	}
+
+	o.SetLongCaller(true).SetLevelsLongCaller([]Level{ErrorLevel}, false)
+	w.Reset()
+	o.Error("test")
+	if got := w.String(); !strings.HasPrefix(got, "logger_caller_skip_test.go:") {
+		t.Fatalf("Logger.SetLevelsLongCaller(): %s", got)
+	}
+	w.Reset()
+	o.Info("test")
+	if got := w.String(); !strings.Contains(got, "/logger_caller_skip_test.go:") {
+		t.Fatalf("Logger.SetLevelsLongCaller(): %s", got)
+	}
+}
@@ -95,6 +95,47 @@ func TestLogger_SetLevelsOutput(t *testing.T) {
 	}
 }
 
+func TestLogger_SetLevelRangeOutput(t *testing.T) {
+	o := New("test")
+	w := new(bytes.Buffer)
+
+	if o.SetLevelRangeOutput(InfoLevel, ErrorLevel, w) == nil {
+		t.Fatal("Logger.SetLevelRangeOutput(): nil")
+	}
+	c := o.(*logger).core
+	if len(c.levelWriter) != 3 || c.levelWriter[ErrorLevel] != w || c.levelWriter[InfoLevel] != w {
+		t.Fatalf("Logger.SetLevelRangeOutput(): %v", c.levelWriter)
+	}
+	if o.SetLevelRangeOutput(TraceLevel, WarnLevel, nil) == nil {
+		t.Fatal("Logger.SetLevelRangeOutput(Level, Level, nil): nil")
+	}
+	if len(c.levelWriter) != 1 || c.levelWriter[ErrorLevel] != w {
+		t.Fatalf("Logger.SetLevelRangeOutput(): %v", c.levelWriter)
+	}
+}
+
+func TestLogger_SetPriorityOutput(t *testing.T) {
+	o := New("test")
+	w1, w2 := new(bytes.Buffer), new(bytes.Buffer)
+
+	if o.SetHighPriorityOutput(w1) == nil {
+		t.Fatal("Logger.SetHighPriorityOutput(): nil")
+	}
+	if o.SetLowPriorityOutput(w2) == nil {
+		t.Fatal("Logger.SetLowPriorityOutput(): nil")
+	}
+	o.SetExitFunc(nil).SetPanicFunc(nil).SetFormatter(MustNewTextFormatter("{message}", false))
+	for _, level := range GetAllLevels() {
+		o.Log(level, level.String())
+	}
+	if got := w1.String(); got != "panic\nfatal\nerror\n" {
+		t.Fatalf("Logger.SetHighPriorityOutput(): %q", got)
+	}
+	if got := w2.String(); got != "warn\ninfo\ndebug\ntrace\n" {
+		t.Fatalf("Logger.SetLowPriorityOutput(): %q", got)
+	}
+}
+
 func TestLogger_SetNowFunc(t *testing.T) {
 	o := New("test")
 	f := func() time.Time { return time.Now() }
@@ -107,6 +148,62 @@ func TestLogger_SetNowFunc(t *testing.T) {
 	}
 }
 
+func TestLogger_SetTimePrecision(t *testing.T) {
+	o := New("test")
+	buf := new(bytes.Buffer)
+	o.SetOutput(buf)
+	o.SetDefaultTimeFormat(time.RFC3339Nano)
+	o.SetNowFunc(func() time.Time { return time.Date(2023, 1, 2, 3, 4, 5, 123456789, time.UTC) })
+
+	if o.SetTimePrecision(time.Millisecond) == nil {
+		t.Fatal("Logger.SetTimePrecision(): nil")
+	}
+	o.Info("test")
+	if got := buf.String(); !strings.Contains(got, `"time":"2023-01-02T03:04:05.123Z"`) {
+		t.Fatalf("Logger.SetTimePrecision(): %q", got)
+	}
+
+	buf.Reset()
+	if o.SetTimePrecision(0) == nil {
+		t.Fatal("Logger.SetTimePrecision(0): nil")
+	}
+	o.Info("test")
+	if got := buf.String(); !strings.Contains(got, `"time":"2023-01-02T03:04:05.123456789Z"`) {
+		t.Fatalf("Logger.SetTimePrecision(0): %q", got)
+	}
+}
+
+func TestLogger_SetUptimeField(t *testing.T) {
+	o := New("test")
+	buf := new(bytes.Buffer)
+	o.SetOutput(buf)
+	start := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	o.SetNowFunc(func() time.Time { return start.Add(time.Millisecond * 1500) })
+
+	if o.SetUptimeField("uptime", start) == nil {
+		t.Fatal("Logger.SetUptimeField(): nil")
+	}
+	o.WithField("foo", 1).Info("test")
+	if got := buf.String(); !strings.Contains(got, `"fields":{"foo":1,"uptime":1.5}`) {
+		t.Fatalf("Logger.SetUptimeField(): %q", got)
+	}
+
+	buf.Reset()
+	o.SetNowFunc(nil)
+	o.SetUptimeField("uptime", time.Time{})
+	o.Info("test")
+	if got := buf.String(); !strings.Contains(got, `"uptime":`) {
+		t.Fatalf("Logger.SetUptimeField(): %q", got)
+	}
+
+	buf.Reset()
+	o.SetUptimeField("", time.Time{})
+	o.Info("test")
+	if got := buf.String(); strings.Contains(got, `"uptime":`) {
+		t.Fatalf("Logger.SetUptimeField(): %q", got)
+	}
+}
+
 func TestLogger_SetExitFunc(t *testing.T) {
 	o := New("test")
 	f := func(int) {}
@@ -946,3 +1043,723 @@ func TestLogger_EnableHook(t *testing.T) {
 		t.Fatal(msg)
 	}
 }
+
+func TestLogger_EnableLevelHooks(t *testing.T) {
+	o := New("test")
+	o.SetOutput(io.Discard)
+	o.SetLevel(TraceLevel)
+
+	if o.EnableLevelHooks([]Level{DebugLevel, InfoLevel}, false) == nil {
+		t.Fatal("Logger.EnableLevelHooks(): return nil.")
+	}
+
+	var got []string
+	o.AddHookFunc(GetAllLevels(), func(su Summary) error {
+		got = append(got, su.Message())
+		return nil
+	})
+
+	o.Debug("debug")
+	o.Info("info")
+	o.Error("error")
+	if len(got) != 1 || got[0] != "error" {
+		t.Fatalf("Logger.EnableLevelHooks(): %v", got)
+	}
+
+	got = nil
+	o.EnableLevelHooks([]Level{InfoLevel}, true)
+	o.Debug("debug")
+	o.Info("info")
+	if len(got) != 1 || got[0] != "info" {
+		t.Fatalf("Logger.EnableLevelHooks(): %v", got)
+	}
+}
+
+func TestLogger_SetInitialBufferSize(t *testing.T) {
+	o := New("test")
+	o.SetOutput(io.Discard)
+
+	if o.SetInitialBufferSize(8192) == nil {
+		t.Fatal("Logger.SetInitialBufferSize(): return nil.")
+	}
+
+	var size int
+	o.AddHookFunc(GetAllLevels(), func(su Summary) error {
+		size = su.Buffer().Cap()
+		return nil
+	})
+
+	o.Info("test")
+	if size < 8192 {
+		t.Fatalf("Logger.SetInitialBufferSize(): buffer cap %d", size)
+	}
+
+	o.SetInitialBufferSize(-1)
+	if got := o.(*logger).core.bufferSize; got != 0 {
+		t.Fatalf("Logger.SetInitialBufferSize(): %d", got)
+	}
+}
+
+func TestLogger_SetMaxBufferSize(t *testing.T) {
+	o := New("test")
+
+	if o.SetMaxBufferSize(8192) == nil {
+		t.Fatal("Logger.SetMaxBufferSize(): return nil.")
+	}
+	c := o.(*logger).core
+	if c.maxBufferSize != 8192 {
+		t.Fatalf("Logger.SetMaxBufferSize(): %d", c.maxBufferSize)
+	}
+
+	e := c.getEntity(&o.(*logger).log, InfoLevel, "test", "")
+	e.buffer.Grow(6000)
+	c.putEntity(e)
+	if e.buffer.Cap() < 6000 {
+		t.Fatalf("Logger.SetMaxBufferSize(): buffer discarded, cap %d", e.buffer.Cap())
+	}
+
+	c.maxBufferSize = 0
+	o.SetMaxBufferSize(0)
+	if c.maxBufferSize != defaultMaxBufferSize {
+		t.Fatalf("Logger.SetMaxBufferSize(0): %d", c.maxBufferSize)
+	}
+	e = c.getEntity(&o.(*logger).log, InfoLevel, "test", "")
+	e.buffer.Grow(6000)
+	c.putEntity(e)
+	if e.buffer.Cap() != 0 {
+		t.Fatalf("Logger.SetMaxBufferSize(0): buffer retained, cap %d", e.buffer.Cap())
+	}
+
+	o.SetMaxBufferSize(-1)
+	if c.maxBufferSize != 0 {
+		t.Fatalf("Logger.SetMaxBufferSize(-1): %d", c.maxBufferSize)
+	}
+}
+
+func TestLogger_SetMaxRecordSize(t *testing.T) {
+	w := new(bytes.Buffer)
+	o := New("test").SetOutput(w).SetFormatter(MustNewTextFormatter("{message}", false))
+
+	if o.SetMaxRecordSize(20) == nil {
+		t.Fatal("Logger.SetMaxRecordSize(): return nil.")
+	}
+
+	o.Info("foo")
+	if got := w.String(); got != "foo\n" {
+		t.Fatalf("Logger.SetMaxRecordSize(): %q", got)
+	}
+	if got := o.GetTruncatedCount(); got != 0 {
+		t.Fatalf("Logger.GetTruncatedCount(): %d", got)
+	}
+
+	w.Reset()
+	o.Info(strings.Repeat("x", 30))
+	if got, want := w.String(), "xxxxx"+truncatedMarker+"\n"; got != want {
+		t.Fatalf("Logger.SetMaxRecordSize(): want %q, got %q", want, got)
+	}
+	if got := o.GetTruncatedCount(); got != 1 {
+		t.Fatalf("Logger.GetTruncatedCount(): %d", got)
+	}
+
+	w.Reset()
+	o.SetMaxRecordSize(0)
+	o.Info(strings.Repeat("x", 30))
+	if got := w.Len(); got != 31 {
+		t.Fatalf("Logger.SetMaxRecordSize(0): %d", got)
+	}
+}
+
+type testContextKey string
+
+func TestLogger_AddContextField(t *testing.T) {
+	o := New("test")
+
+	if o.AddContextField("request_id", testContextKey("rid")) == nil {
+		t.Fatal("Logger.AddContextField(): nil")
+	}
+	if o.AddContextFieldExtractor(nil) == nil {
+		t.Fatal("Logger.AddContextFieldExtractor(nil): nil")
+	}
+	o.AddContextFieldExtractor(func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{"foo": "bar"}
+	})
+
+	var fields map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		fields = s.Fields()
+		return nil
+	})
+	o.SetOutput(io.Discard)
+
+	ctx := context.WithValue(context.Background(), testContextKey("rid"), "123")
+	o.WithField("key", 1).WithContext(ctx).Info("test")
+	if len(fields) != 3 || fields["request_id"] != "123" || fields["foo"] != "bar" || fields["key"] != 1 {
+		t.Fatalf("Logger.AddContextField(): %v", fields)
+	}
+
+	o.WithContext(context.Background()).Info("test")
+	if len(fields) != 1 || fields["foo"] != "bar" {
+		t.Fatalf("Logger.AddContextField(): %v", fields)
+	}
+
+	o.WithContext(nil).Info("test")
+	if len(fields) != 0 {
+		t.Fatalf("Logger.AddContextField(): %v", fields)
+	}
+}
+
+func TestLogger_Rotate(t *testing.T) {
+	rw1 := new(testRotateWriter)
+	rw2 := new(testRotateWriter)
+	l := New("test").SetOutput(rw1).SetHighPriorityOutput(rw2).SetLevelOutput(DebugLevel, rw1)
+	if err := l.Rotate(); err != nil {
+		t.Fatalf("Logger.Rotate(): %s", err)
+	}
+	if rw1.rotated != 1 || rw2.rotated != 1 {
+		t.Fatalf("Logger.Rotate(): rotated %d, %d", rw1.rotated, rw2.rotated)
+	}
+
+	rw2.err = errors.New("test")
+	if err := l.Rotate(); err == nil {
+		t.Fatal("Logger.Rotate(): nil error")
+	}
+	if err := New("test").Rotate(); err != nil {
+		t.Fatalf("Logger.Rotate(): %s", err)
+	}
+}
+
+type testOrderCloser struct {
+	name  string
+	order *[]string
+	err   error
+}
+
+func (w *testOrderCloser) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *testOrderCloser) Close() error {
+	*w.order = append(*w.order, w.name)
+	return w.err
+}
+
+func TestLogger_Close(t *testing.T) {
+	var order []string
+	w1 := &testOrderCloser{name: "w1", order: &order}
+	w2 := &testOrderCloser{name: "w2", order: &order, err: errors.New("test")}
+	w3 := &testOrderCloser{name: "w3", order: &order}
+	l := New("test").
+		SetOwnedOutput(w1).
+		SetOwnedLevelOutput(ErrorLevel, w2).
+		SetOwnedLevelOutput(WarnLevel, w3).
+		SetOwnedLevelOutput(DebugLevel, w1).
+		SetOwnedOutput(nil)
+
+	if err := l.Close(); err == nil || err.Error() != "test" {
+		t.Fatalf("Logger.Close(): %v", err)
+	}
+	if got := strings.Join(order, ","); got != "w1,w3,w2" {
+		t.Fatalf("Logger.Close(): order %s", got)
+	}
+	// The owned writers are closed only once.
+	if err := l.Close(); err != nil {
+		t.Fatalf("Logger.Close(): %s", err)
+	}
+	if len(order) != 3 {
+		t.Fatalf("Logger.Close(): order %v", order)
+	}
+}
+
+func TestLogger_SetLevelFor(t *testing.T) {
+	l := New("test").SetLevel(InfoLevel)
+	l.SetLevelFor(time.Millisecond*20, DebugLevel)
+	if got := l.GetLevel(); got != DebugLevel {
+		t.Fatalf("Logger.SetLevelFor(): %s", got)
+	}
+	for i := 0; i < 100 && l.GetLevel() != InfoLevel; i++ {
+		time.Sleep(time.Millisecond * 5)
+	}
+	if got := l.GetLevel(); got != InfoLevel {
+		t.Fatalf("Logger.SetLevelFor(): not restored %s", got)
+	}
+
+	// The later override replaces the current override.
+	cancel := l.SetLevelFor(time.Hour, DebugLevel)
+	cancel2 := l.SetLevelFor(time.Hour, TraceLevel)
+	cancel()
+	if got := l.GetLevel(); got != TraceLevel {
+		t.Fatalf("Logger.SetLevelFor(): replaced override cancelled %s", got)
+	}
+	cancel2()
+	if got := l.GetLevel(); got != InfoLevel {
+		t.Fatalf("Logger.SetLevelFor(): cancel %s", got)
+	}
+	cancel2()
+
+	// The logger level changed by others is not restored.
+	cancel = l.SetLevelFor(time.Hour, DebugLevel)
+	l.SetLevel(ErrorLevel)
+	cancel()
+	if got := l.GetLevel(); got != ErrorLevel {
+		t.Fatalf("Logger.SetLevelFor(): %s", got)
+	}
+
+	l.SetLevelFor(time.Hour, Level(0))()
+	if got := l.GetLevel(); got != ErrorLevel {
+		t.Fatalf("Logger.SetLevelFor(): invalid level %s", got)
+	}
+}
+
+func TestLogger_NewChild(t *testing.T) {
+	var names []string
+	o := New("app").SetOutput(new(bytes.Buffer)).SetLevel(InfoLevel)
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		names = append(names, s.Name())
+		return nil
+	})
+
+	db := o.NewChild("db")
+	if db == nil || db.Name() != "app.db" || db.GetLevel() != InfoLevel {
+		t.Fatalf("Logger.NewChild(): %v", db)
+	}
+	sql := db.NewChild("sql")
+	if sql.Name() != "app.db.sql" || New("").NewChild("db").Name() != "db" || o.NewChild("").Name() != "app" {
+		t.Fatalf("Logger.NewChild(): %s", sql.Name())
+	}
+
+	// The child level overrides the inherited level.
+	db.SetLevel(DebugLevel)
+	if o.GetLevel() != InfoLevel || db.GetLevel() != DebugLevel || sql.GetLevel() != DebugLevel {
+		t.Fatalf("Logger.NewChild(): %s %s %s", o.GetLevel(), db.GetLevel(), sql.GetLevel())
+	}
+	o.Debug("test")
+	db.Debug("test")
+	sql.WithField("a", 1).Debug("test")
+	o.SetLevel(WarnLevel)
+	db.Info("test")
+	if got := strings.Join(names, ","); got != "app.db,app.db.sql,app.db" {
+		t.Fatalf("Logger.NewChild(): %s", got)
+	}
+
+	// The temporary level override of the child does not change the parent level.
+	cancel := db.SetLevelFor(time.Hour, TraceLevel)
+	if o.GetLevel() != WarnLevel || db.GetLevel() != TraceLevel || sql.GetLevel() != TraceLevel {
+		t.Fatalf("Logger.NewChild(): %s %s %s", o.GetLevel(), db.GetLevel(), sql.GetLevel())
+	}
+	cancel()
+	if db.GetLevel() != DebugLevel {
+		t.Fatalf("Logger.NewChild(): %s", db.GetLevel())
+	}
+
+	// The child logger inheriting the parent level keeps inheriting it after the override.
+	cancel = sql.SetLevelFor(time.Hour, ErrorLevel)
+	if sql.GetLevel() != ErrorLevel || db.GetLevel() != DebugLevel {
+		t.Fatalf("Logger.NewChild(): %s %s", sql.GetLevel(), db.GetLevel())
+	}
+	cancel()
+	db.SetLevel(InfoLevel)
+	if sql.GetLevel() != InfoLevel {
+		t.Fatalf("Logger.NewChild(): %s", sql.GetLevel())
+	}
+	sql.SetLevelFor(time.Millisecond*10, ErrorLevel)
+	for i := 0; i < 100 && sql.GetLevel() == ErrorLevel; i++ {
+		time.Sleep(time.Millisecond * 5)
+	}
+	db.SetLevel(TraceLevel)
+	if sql.GetLevel() != TraceLevel {
+		t.Fatalf("Logger.NewChild(): %s", sql.GetLevel())
+	}
+}
+
+func TestLogger_ErrIf(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetFormatter(DefaultJSONFormatter()).EnableCaller()
+
+	if l.ErrIf(nil, "test") {
+		t.Fatal("Logger.ErrIf(): nil error returns true")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Logger.ErrIf(): nil error logged %q", buf.String())
+	}
+	if !l.ErrIf(errors.New("foo"), "test", "key", "value") {
+		t.Fatal("Logger.ErrIf(): error returns false")
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("Logger.ErrIf(): %s", err)
+	}
+	fields, _ := m["fields"].(map[string]interface{})
+	if m["level"] != "error" || m["message"] != "test" || fields["error"] != "foo" || fields["key"] != "value" {
+		t.Fatalf("Logger.ErrIf(): %v", m)
+	}
+	if caller, _ := m["caller"].(string); !strings.Contains(caller, "logger_test.go") {
+		t.Fatalf("Logger.ErrIf(): caller %q", caller)
+	}
+}
+
+func TestLogger_Wrap(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetFormatter(DefaultJSONFormatter()).EnableCaller()
+
+	if err := l.Wrap(nil, "test"); err != nil {
+		t.Fatalf("Logger.Wrap(): %s", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Logger.Wrap(): nil error logged %q", buf.String())
+	}
+	cause := errors.New("foo")
+	err := l.WithField("key", "value").Wrap(cause, "test")
+	if err == nil || err.Error() != "test: foo" || !errors.Is(err, cause) {
+		t.Fatalf("Logger.Wrap(): %v", err)
+	}
+	m := make(map[string]interface{})
+	if err = json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("Logger.Wrap(): %s", err)
+	}
+	fields, _ := m["fields"].(map[string]interface{})
+	if m["level"] != "error" || m["message"] != "test" || fields["error"] != "foo" || fields["key"] != "value" {
+		t.Fatalf("Logger.Wrap(): %v", m)
+	}
+	if caller, _ := m["caller"].(string); !strings.Contains(caller, "logger_test.go") {
+		t.Fatalf("Logger.Wrap(): caller %q", caller)
+	}
+}
+
+func TestLogger_Recover(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetFormatter(DefaultJSONFormatter())
+
+	func() {
+		defer l.Recover()
+		panic(errors.New("foo"))
+	}()
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("Logger.Recover(): %s", err)
+	}
+	fields, _ := m["fields"].(map[string]interface{})
+	if m["level"] != "panic" || m["message"] != "recovered from panic: foo" || fields["error"] != "foo" {
+		t.Fatalf("Logger.Recover(): %v", m)
+	}
+	if stack, _ := m["stack"].([]interface{}); len(stack) == 0 {
+		t.Fatalf("Logger.Recover(): no stack %v", m)
+	}
+
+	// No panic.
+	buf.Reset()
+	func() {
+		defer l.Recover()
+	}()
+	if buf.Len() != 0 {
+		t.Fatalf("Logger.Recover(): %q", buf.String())
+	}
+}
+
+func TestLogger_RecoverWith(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetLevel(ErrorLevel)
+
+	func() {
+		defer l.RecoverWith(ErrorLevel, false)
+		panic("foo")
+	}()
+	if got := buf.String(); !strings.Contains(got, "recovered from panic: foo") {
+		t.Fatalf("Logger.RecoverWith(): %q", got)
+	}
+
+	buf.Reset()
+	got := func() (v interface{}) {
+		defer func() { v = recover() }()
+		defer l.RecoverWith(ErrorLevel, true)
+		panic("bar")
+	}()
+	if got != "bar" {
+		t.Fatalf("Logger.RecoverWith(): rethrow %v", got)
+	}
+	if !strings.Contains(buf.String(), "recovered from panic: bar") {
+		t.Fatalf("Logger.RecoverWith(): %q", buf.String())
+	}
+
+	// The disabled level is not recorded.
+	buf.Reset()
+	func() {
+		defer l.RecoverWith(DebugLevel, false)
+		panic("baz")
+	}()
+	if buf.Len() != 0 {
+		t.Fatalf("Logger.RecoverWith(): %q", buf.String())
+	}
+}
+
+func TestLogger_SetPanicSummaryFunc(t *testing.T) {
+	var got Summary
+	o := New("test").SetOutput(new(bytes.Buffer)).SetPanicFunc(func(string) {
+		t.Fatal("Logger.SetPanicSummaryFunc(): panic function called")
+	})
+	o.SetPanicSummaryFunc(func(s Summary) { got = s.Clone() })
+
+	o.WithField("key", "value").Panic("test")
+	if got == nil || got.Message() != "test" || got.Fields()["key"] != "value" || got.Size() == 0 {
+		t.Fatalf("Logger.SetPanicSummaryFunc(): %v", got)
+	}
+
+	var message string
+	o.SetPanicSummaryFunc(nil).SetPanicFunc(func(s string) { message = s })
+	o.Panic("foo")
+	if message != "foo" {
+		t.Fatalf("Logger.SetPanicSummaryFunc(nil): %q", message)
+	}
+}
+
+func TestLogger_SetExitCode(t *testing.T) {
+	var code int
+	o := New("test").SetOutput(new(bytes.Buffer)).SetExitFunc(func(c int) { code = c })
+
+	o.Fatal("test")
+	if code != 1 {
+		t.Fatalf("Logger.Fatal(): exit code %d", code)
+	}
+	o.SetExitCode(3).Fatalln("test")
+	if code != 3 {
+		t.Fatalf("Logger.SetExitCode(): exit code %d", code)
+	}
+	o.WithField("key", "value").FatalWithCode(0, "test")
+	if code != 0 {
+		t.Fatalf("Logger.FatalWithCode(): exit code %d", code)
+	}
+	o.Fatalf("test")
+	if code != 3 {
+		t.Fatalf("Logger.Fatalf(): exit code %d", code)
+	}
+}
+
+func TestLogger_AddExitHandler(t *testing.T) {
+	var order []string
+	var exited bool
+	o := New("test").SetOutput(new(bytes.Buffer)).SetExitFunc(func(int) {
+		order = append(order, "exit")
+		exited = true
+	})
+	o.SetOwnedLevelOutput(DebugLevel, &testOrderCloser{name: "writer", order: &order})
+	o.AddExitHandler(func() { order = append(order, "first") }).
+		AddExitHandler(nil).
+		AddExitHandler(func() { panic("test") }).
+		AddExitHandler(func() { order = append(order, "last") })
+
+	o.Fatal("test")
+	if got := strings.Join(order, ","); !exited || got != "last,first,writer,exit" {
+		t.Fatalf("Logger.AddExitHandler(): %s", got)
+	}
+	// The owned writers are closed only once.
+	order = nil
+	o.Fatal("test")
+	if got := strings.Join(order, ","); got != "last,first,exit" {
+		t.Fatalf("Logger.AddExitHandler(): %s", got)
+	}
+}
+
+func TestLogger_OnFatal(t *testing.T) {
+	var order []string
+	buf := new(bytes.Buffer)
+	o := New("test").SetOutput(buf).SetExitFunc(func(int) { order = append(order, "exit") })
+	o.AddExitHandler(func() { order = append(order, "handler") })
+	o.OnFatal(func(s Summary) {
+		if buf.Len() == 0 {
+			t.Fatal("Logger.OnFatal(): the log is not written")
+		}
+		order = append(order, "fatal:"+s.Message())
+	}).OnFatal(nil).OnFatal(func(Summary) { panic("test") }).OnFatal(func(Summary) { order = append(order, "last") })
+
+	o.Error("test")
+	o.Fatal("test")
+	if got := strings.Join(order, ","); got != "fatal:test,last,handler,exit" {
+		t.Fatalf("Logger.OnFatal(): %s", got)
+	}
+}
+
+func TestLogger_OnPanic(t *testing.T) {
+	var order []string
+	o := New("test").SetOutput(new(bytes.Buffer)).SetPanicFunc(func(string) { order = append(order, "panic") })
+	o.OnPanic(func(s Summary) { order = append(order, "panic:"+s.Message()) }).OnPanic(nil)
+
+	o.Error("test")
+	o.Panic("test")
+	if got := strings.Join(order, ","); got != "panic:test,panic" {
+		t.Fatalf("Logger.OnPanic(): %s", got)
+	}
+}
+
+func TestLogger_SetExitTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	var exited bool
+	o := New("test").SetOutput(new(bytes.Buffer)).SetExitFunc(func(int) { exited = true })
+	o.SetExitTimeout(time.Millisecond * 10).AddExitHandler(func() { <-block })
+
+	start := time.Now()
+	o.Fatal("test")
+	if !exited || time.Since(start) > time.Second {
+		t.Fatalf("Logger.SetExitTimeout(): exited %v after %s", exited, time.Since(start))
+	}
+	if o.SetExitTimeout(0) == nil {
+		t.Fatal("Logger.SetExitTimeout(0): nil")
+	}
+}
+
+func TestLogger_LogE(t *testing.T) {
+	o := New("test").SetOutput(new(bytes.Buffer)).SetLevel(InfoLevel)
+	if err := o.InfoE("test"); err != nil {
+		t.Fatalf("Logger.InfoE(): %s", err)
+	}
+	if err := o.LogE(DebugLevel, "test"); err != nil {
+		t.Fatalf("Logger.LogE(): disabled level %s", err)
+	}
+
+	o.SetOutput(testErrorWriter("test"))
+	if err := o.ErrorE("test"); err == nil || err.Error() != "test" {
+		t.Fatalf("Logger.ErrorE(): %v", err)
+	}
+	if err := o.LogE(WarnLevel, "test"); err == nil {
+		t.Fatal("Logger.LogE(): nil error")
+	}
+
+	// The streamed log write error.
+	o.SetStreamThreshold(1)
+	if err := o.InfoE("test"); err == nil {
+		t.Fatal("Logger.InfoE(): streamed nil error")
+	}
+
+	o.SetOutput(new(bytes.Buffer)).SetFormatter(FormatterFunc(func(Entity, *bytes.Buffer) error {
+		return errors.New("format")
+	}))
+	if err := o.InfoE("test"); err == nil || err.Error() != "format" {
+		t.Fatalf("Logger.InfoE(): format error %v", err)
+	}
+}
+
+func TestLogger_EnableProcessMetadata(t *testing.T) {
+	var entities []Summary
+	o := New("test").SetOutput(new(bytes.Buffer)).AddHookFunc(GetAllLevels(), func(s Summary) error {
+		entities = append(entities, s.Clone())
+		return nil
+	})
+	o.Info("test")
+	o.EnableProcessMetadata(true)
+	o.Info("test")
+	o.Info("test")
+	o.EnableProcessMetadata(false)
+	o.Info("test")
+
+	if len(entities) != 4 {
+		t.Fatalf("Logger.EnableProcessMetadata(): %d logs", len(entities))
+	}
+	hostname, _ := os.Hostname()
+	for i, want := range []uint64{0, 1, 2, 0} {
+		e := entities[i]
+		if e.Sequence() != want {
+			t.Fatalf("Logger.EnableProcessMetadata(): log %d sequence %d", i, e.Sequence())
+		}
+		if want > 0 && (e.PID() != os.Getpid() || e.Hostname() != hostname) {
+			t.Fatalf("Logger.EnableProcessMetadata(): log %d pid %d hostname %q", i, e.PID(), e.Hostname())
+		}
+		if want == 0 && (e.PID() != 0 || e.Hostname() != "") {
+			t.Fatalf("Logger.EnableProcessMetadata(): log %d pid %d hostname %q", i, e.PID(), e.Hostname())
+		}
+	}
+}
+
+func TestLogger_SetRecordIDFunc(t *testing.T) {
+	var ids []string
+	o := New("test").SetOutput(new(bytes.Buffer)).AddHookFunc(GetAllLevels(), func(s Summary) error {
+		ids = append(ids, s.Clone().ID())
+		return nil
+	})
+	o.Info("test")
+	if o.SetRecordIDFunc(NewCorrelationID) == nil {
+		t.Fatal("Logger.SetRecordIDFunc(): nil")
+	}
+	o.Info("test")
+	o.Info("test")
+	o.SetRecordIDFunc(nil)
+	o.Info("test")
+
+	if len(ids) != 4 || ids[0] != "" || ids[1] == "" || ids[2] == "" || ids[1] == ids[2] || ids[3] != "" {
+		t.Fatalf("Logger.SetRecordIDFunc(): %v", ids)
+	}
+}
+
+func TestLogger_SetFieldOverwritePolicy(t *testing.T) {
+	errBuf := new(bytes.Buffer)
+	internal.ErrorWriter = errBuf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	o := New("test")
+	buf := new(bytes.Buffer)
+	o.SetOutput(buf)
+	o.SetDefaultTimeFormat("test")
+
+	items := []struct {
+		Policy FieldOverwritePolicy
+		Want   string
+		Warned bool
+	}{
+		{FieldOverwriteReplace, `"fields":{"a":2,"b":2,"c":2,"d":2}`, false},
+		{FieldOverwriteKeep, `"fields":{"a":1,"b":1,"c":1,"d":1}`, false},
+		{FieldOverwriteWarn, `"fields":{"a":2,"b":2,"c":2,"d":2}`, true},
+	}
+
+	for _, item := range items {
+		if o.SetFieldOverwritePolicy(item.Policy) == nil {
+			t.Fatal("Logger.SetFieldOverwritePolicy(): nil")
+		}
+		buf.Reset()
+		errBuf.Reset()
+		o.WithFieldPairs("a", 1, "b", 1, "c", 1, "d", 1).
+			WithField("a", 2).
+			WithFields(map[string]interface{}{"b": 2}).
+			WithFieldPairs("c", 2).
+			WithFields(map[string]interface{}{"d": 2}).
+			Info("test")
+
+		if got := buf.String(); !strings.Contains(got, item.Want) {
+			t.Fatalf("Logger.SetFieldOverwritePolicy(): want %s, got %q", item.Want, got)
+		}
+		if got := strings.Count(errBuf.String(), "is overwritten"); (got == 4) != item.Warned || (got != 0 && got != 4) {
+			t.Fatalf("Logger.SetFieldOverwritePolicy(): %q", errBuf.String())
+		}
+	}
+}
+
+func TestLogger_EnableFieldDeepMerge(t *testing.T) {
+	o := New("test")
+	buf := new(bytes.Buffer)
+	o.SetOutput(buf)
+	o.SetDefaultTimeFormat("test")
+
+	if o.EnableFieldDeepMerge(true) == nil {
+		t.Fatal("Logger.EnableFieldDeepMerge(): nil")
+	}
+
+	http := map[string]interface{}{"method": "GET", "req": map[string]interface{}{"id": 1}}
+	l := o.WithField("http", http).WithField("x", 1)
+	l.WithField("http", map[string]interface{}{"status": 200, "req": map[string]interface{}{"size": 2}}).
+		WithFields(map[string]interface{}{"x": map[string]interface{}{"y": 1}}).
+		Info("test")
+
+	want := `"fields":{"http":{"method":"GET","req":{"id":1,"size":2},"status":200},"x":{"y":1}}`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Fatalf("Logger.EnableFieldDeepMerge(): want %s, got %q", want, got)
+	}
+	// The merged maps are not modified.
+	if len(http) != 2 || len(http["req"].(map[string]interface{})) != 1 {
+		t.Fatalf("Logger.EnableFieldDeepMerge(): %v", http)
+	}
+
+	buf.Reset()
+	o.EnableFieldDeepMerge(false)
+	l.WithField("http", map[string]interface{}{"status": 200}).Info("test")
+	want = `"fields":{"http":{"status":200},"x":1}`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Fatalf("Logger.EnableFieldDeepMerge(): want %s, got %q", want, got)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/edoger/zkits-logger/logr
+
+go 1.18
+
+require (
+	github.com/edoger/zkits-logger v0.0.0
+	github.com/go-logr/logr v1.4.2
+)
+
+// The adapter is developed against the logger in the parent directory.
+replace github.com/edoger/zkits-logger => ../
@@ -0,0 +1,103 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logr provides the logr.LogSink adapter of the zkits-logger, so the controllers and
+// the client-go based programs can log by the zkits-logger.
+package logr
+
+import (
+	"github.com/go-logr/logr"
+
+	logger "github.com/edoger/zkits-logger"
+)
+
+// NameSeparator is the separator of the names added by logr.Logger.WithName.
+const NameSeparator = "/"
+
+// NameField is the field of the names added by logr.Logger.WithName.
+const NameField = "logger"
+
+// New creates and returns a logr.Logger that records the logs by the given log.
+func New(l logger.Log) logr.Logger {
+	return logr.New(NewLogSink(l))
+}
+
+// NewLogSink creates and returns a logr.LogSink that records the logs by the given log.
+// The V-level 0 logs are recorded at the InfoLevel, the V-level 1 logs are recorded at the
+// DebugLevel, and the V-level 2 and above logs are recorded at the TraceLevel. The error
+// logs are recorded at the ErrorLevel with the "error" field, and the key-value pairs are
+// recorded as the log fields.
+func NewLogSink(l logger.Log) logr.LogSink {
+	return &logSink{log: l}
+}
+
+// The logSink type is the logr.LogSink implementation of the zkits-logger.
+type logSink struct {
+	log  logger.Log
+	name string
+}
+
+// Init receives optional information about the logr library.
+func (s *logSink) Init(logr.RuntimeInfo) {}
+
+// Enabled tests whether this LogSink is enabled at the specified V-level.
+func (s *logSink) Enabled(level int) bool {
+	return s.log.IsLevelEnabled(toLevel(level))
+}
+
+// Info logs a non-error message with the given key-value pairs as context.
+func (s *logSink) Info(level int, msg string, kvs ...interface{}) {
+	s.with(kvs).Log(toLevel(level), msg)
+}
+
+// Error logs an error, with the given message and key-value pairs as context.
+func (s *logSink) Error(err error, msg string, kvs ...interface{}) {
+	l := s.with(kvs)
+	if err != nil {
+		l = l.WithError(err)
+	}
+	l.Error(msg)
+}
+
+// WithValues returns a new LogSink with additional key-value pairs.
+func (s *logSink) WithValues(kvs ...interface{}) logr.LogSink {
+	return &logSink{log: s.log.WithFieldPairs(kvs...), name: s.name}
+}
+
+// WithName returns a new LogSink with the specified name appended.
+func (s *logSink) WithName(name string) logr.LogSink {
+	if s.name != "" {
+		name = s.name + NameSeparator + name
+	}
+	return &logSink{log: s.log.WithField(NameField, name), name: name}
+}
+
+// Returns the log with the given key-value pairs.
+func (s *logSink) with(kvs []interface{}) logger.Log {
+	if len(kvs) > 0 {
+		return s.log.WithFieldPairs(kvs...)
+	}
+	return s.log
+}
+
+// Converts the given logr V-level to the log level.
+func toLevel(level int) logger.Level {
+	switch {
+	case level <= 0:
+		return logger.InfoLevel
+	case level == 1:
+		return logger.DebugLevel
+	}
+	return logger.TraceLevel
+}
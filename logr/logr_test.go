@@ -0,0 +1,94 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	logger "github.com/edoger/zkits-logger"
+)
+
+type testRecord struct {
+	level   logger.Level
+	message string
+	fields  map[string]interface{}
+}
+
+func newTestLogger() (logger.Logger, *[]testRecord) {
+	records := new([]testRecord)
+	o := logger.New("test")
+	o.SetLevel(logger.DebugLevel)
+	o.SetFormatter(logger.FormatterFunc(func(e logger.Entity, b *bytes.Buffer) error {
+		*records = append(*records, testRecord{level: e.Level(), message: e.Message(), fields: e.Fields()})
+		return nil
+	}))
+	return o, records
+}
+
+func TestNew(t *testing.T) {
+	o, records := newTestLogger()
+	l := New(o)
+
+	l.Info("info", "a", 1)
+	l.V(1).Info("debug")
+	l.V(2).Info("trace")
+	l.Error(errors.New("test"), "error", "b", "b")
+	l.Error(nil, "error")
+
+	want := []testRecord{
+		{logger.InfoLevel, "info", map[string]interface{}{"a": 1}},
+		{logger.DebugLevel, "debug", nil},
+		{logger.ErrorLevel, "error", map[string]interface{}{"b": "b", "error": "test"}},
+		{logger.ErrorLevel, "error", nil},
+	}
+	if len(*records) != len(want) {
+		t.Fatalf("New(): %v", *records)
+	}
+	for i, r := range *records {
+		if r.level != want[i].level || r.message != want[i].message || len(r.fields) != len(want[i].fields) {
+			t.Fatalf("New(): %v", r)
+		}
+		for k, v := range want[i].fields {
+			if got := r.fields[k]; got != v {
+				if err, ok := got.(error); !ok || err.Error() != v {
+					t.Fatalf("New(): %v", r)
+				}
+			}
+		}
+	}
+}
+
+func TestLogSink_Enabled(t *testing.T) {
+	o, _ := newTestLogger()
+	s := NewLogSink(o)
+	if !s.Enabled(0) || !s.Enabled(1) || s.Enabled(2) {
+		t.Fatal("LogSink.Enabled(): unexpected")
+	}
+}
+
+func TestLogSink_WithValuesAndName(t *testing.T) {
+	o, records := newTestLogger()
+	l := New(o).WithName("a").WithValues("k", "v").WithName("b")
+
+	l.Info("test")
+	if len(*records) != 1 {
+		t.Fatalf("LogSink.WithName(): %v", *records)
+	}
+	if r := (*records)[0]; r.fields[NameField] != "a/b" || r.fields["k"] != "v" {
+		t.Fatalf("LogSink.WithName(): %v", r)
+	}
+}
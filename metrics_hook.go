@@ -0,0 +1,212 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMetricsNamespace is the default namespace of the metrics of the metrics hook.
+const DefaultMetricsNamespace = "logger"
+
+// MetricsSnapshot defines the log counts recorded by the metrics hook.
+// The MetricsSnapshot implements the expvar.Var interface, so it can be published directly:
+//
+//	expvar.Publish("logger", expvar.Func(func() interface{} { return h.Snapshot() }))
+type MetricsSnapshot struct {
+	// Levels is the number of the logs of each level, the keys are the level strings.
+	Levels map[string]uint64 `json:"levels"`
+
+	// Names is the number of the logs of each level of each logger name, it is only
+	// available if the logs are counted by the logger name.
+	Names map[string]map[string]uint64 `json:"names,omitempty"`
+}
+
+// String returns the JSON string of the current snapshot.
+func (s MetricsSnapshot) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// MetricsHookOptions defines the options of the metrics hook.
+type MetricsHookOptions struct {
+	// Levels is the log levels counted by the metrics hook, all levels are counted if it is empty.
+	Levels []Level
+
+	// ByName determines whether to count the logs by the logger name in addition to the level.
+	ByName bool
+
+	// Namespace is the prefix of the metric names, DefaultMetricsNamespace is used if it is empty.
+	// The exposed metric is named "<namespace>_logs_total".
+	Namespace string
+}
+
+// MetricsHook interface defines the log hook that counts the logs, so the applications can
+// alert on the error rate spikes without parsing the logs.
+type MetricsHook interface {
+	Hook
+
+	// Snapshot returns the current log counts.
+	Snapshot() MetricsSnapshot
+
+	// WritePrometheus writes the current log counts to the given writer in the Prometheus
+	// text exposition format, which can be served directly as a scrape target.
+	WritePrometheus(io.Writer) error
+}
+
+// NewMetricsHook creates and returns a log hook that counts the logs by level (and optionally
+// by logger name) with the given options.
+func NewMetricsHook(opts MetricsHookOptions) MetricsHook {
+	h := &metricsHook{levels: opts.Levels, byName: opts.ByName, namespace: opts.Namespace}
+	if len(h.levels) == 0 {
+		h.levels = GetAllLevels()
+	}
+	if h.namespace == "" {
+		h.namespace = DefaultMetricsNamespace
+	}
+	if h.byName {
+		h.names = make(map[string]*levelCounts)
+	}
+	return h
+}
+
+// The levelCounts type stores the log counts indexed by the log level.
+type levelCounts [TraceLevel + 1]uint64
+
+// Adds one to the count of the given level.
+func (c *levelCounts) add(level Level) {
+	atomic.AddUint64(&c[level], 1)
+}
+
+// Returns the non-zero counts keyed by the level strings.
+func (c *levelCounts) snapshot() map[string]uint64 {
+	m := make(map[string]uint64, len(c))
+	for _, level := range GetAllLevels() {
+		if n := atomic.LoadUint64(&c[level]); n > 0 {
+			m[level.String()] = n
+		}
+	}
+	return m
+}
+
+// The built-in metrics hook.
+type metricsHook struct {
+	counts    levelCounts
+	levels    []Level
+	byName    bool
+	namespace string
+	mu        sync.RWMutex
+	names     map[string]*levelCounts
+}
+
+// Levels returns the log levels associated with the current log hook.
+func (h *metricsHook) Levels() []Level {
+	return h.levels
+}
+
+// Fire counts the given log summary.
+func (h *metricsHook) Fire(s Summary) error {
+	level := s.Level()
+	if !level.IsValid() {
+		return nil
+	}
+	h.counts.add(level)
+	if h.byName {
+		h.nameCounts(s.Name()).add(level)
+	}
+	return nil
+}
+
+// Returns the log counts of the given logger name.
+func (h *metricsHook) nameCounts(name string) *levelCounts {
+	h.mu.RLock()
+	c := h.names[name]
+	h.mu.RUnlock()
+	if c != nil {
+		return c
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c = h.names[name]; c == nil {
+		c = new(levelCounts)
+		h.names[name] = c
+	}
+	return c
+}
+
+// Snapshot returns the current log counts.
+func (h *metricsHook) Snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{Levels: h.counts.snapshot()}
+	if h.byName {
+		h.mu.RLock()
+		s.Names = make(map[string]map[string]uint64, len(h.names))
+		for name, c := range h.names {
+			s.Names[name] = c.snapshot()
+		}
+		h.mu.RUnlock()
+	}
+	return s
+}
+
+// WritePrometheus writes the current log counts to the given writer in the Prometheus
+// text exposition format, which can be served directly as a scrape target.
+func (h *metricsHook) WritePrometheus(w io.Writer) error {
+	metric := h.namespace + "_logs_total"
+	b := bufio.NewWriter(w)
+	b.WriteString("# HELP " + metric + " The total number of the logs by level.\n")
+	b.WriteString("# TYPE " + metric + " counter\n")
+	if !h.byName {
+		h.writePrometheusCounts(b, metric, "", &h.counts)
+		return b.Flush()
+	}
+	h.mu.RLock()
+	names := make([]string, 0, len(h.names))
+	for name := range h.names {
+		names = append(names, name)
+	}
+	h.mu.RUnlock()
+	sort.Strings(names)
+	for _, name := range names {
+		h.writePrometheusCounts(b, metric, `name="`+escapePrometheusLabel(name)+`",`, h.nameCounts(name))
+	}
+	return b.Flush()
+}
+
+// Writes the samples of the given log counts with the given extra labels.
+func (h *metricsHook) writePrometheusCounts(b *bufio.Writer, metric, labels string, c *levelCounts) {
+	for _, level := range h.levels {
+		if !level.IsValid() {
+			continue
+		}
+		b.WriteString(metric + "{" + labels + `level="` + level.String() + `"} `)
+		b.WriteString(strconv.FormatUint(atomic.LoadUint64(&c[level]), 10))
+		b.WriteByte('\n')
+	}
+}
+
+// The replacer of the Prometheus label values.
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Escapes the given Prometheus label value.
+func escapePrometheusLabel(s string) string {
+	return prometheusLabelReplacer.Replace(s)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewMetricsHook(t *testing.T) {
+	h := NewMetricsHook(MetricsHookOptions{})
+	if h == nil {
+		t.Fatal("NewMetricsHook(): nil")
+	}
+	if len(h.Levels()) != len(GetAllLevels()) {
+		t.Fatalf("MetricsHook.Levels(): %v", h.Levels())
+	}
+
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	o.AddHook(h)
+	o.SetLevel(InfoLevel)
+	o.Error("test")
+	o.Error("test")
+	o.Info("test")
+	o.Debug("test") // Not recorded.
+
+	s := h.Snapshot()
+	if len(s.Levels) != 2 || s.Levels["error"] != 2 || s.Levels["info"] != 1 || s.Names != nil {
+		t.Fatalf("MetricsHook.Snapshot(): %+v", s)
+	}
+	if got, want := s.String(), `{"levels":{"error":2,"info":1}}`; got != want {
+		t.Fatalf("MetricsSnapshot.String(): want %q, got %q", want, got)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := NewMetricsHook(MetricsHookOptions{Levels: []Level{ErrorLevel, InfoLevel}}).WritePrometheus(buf); err != nil {
+		t.Fatalf("MetricsHook.WritePrometheus(): error %s", err)
+	}
+	want := "# HELP logger_logs_total The total number of the logs by level.\n" +
+		"# TYPE logger_logs_total counter\n" +
+		"logger_logs_total{level=\"error\"} 0\n" +
+		"logger_logs_total{level=\"info\"} 0\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("MetricsHook.WritePrometheus(): want %q, got %q", want, got)
+	}
+}
+
+func TestNewMetricsHook_ByName(t *testing.T) {
+	h := NewMetricsHook(MetricsHookOptions{Levels: []Level{ErrorLevel}, ByName: true, Namespace: "app"})
+	for _, name := range []string{"b", "a\"", "b"} {
+		o := New(name)
+		o.SetOutput(new(testSyncBuffer))
+		o.AddHook(h)
+		o.Error("test")
+		o.Warn("test")
+	}
+
+	s := h.Snapshot()
+	if s.Levels["error"] != 3 || len(s.Names) != 2 || s.Names["b"]["error"] != 2 || s.Names["a\""]["error"] != 1 {
+		t.Fatalf("MetricsHook.Snapshot(): %+v", s)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := h.WritePrometheus(buf); err != nil {
+		t.Fatalf("MetricsHook.WritePrometheus(): error %s", err)
+	}
+	want := "# HELP app_logs_total The total number of the logs by level.\n" +
+		"# TYPE app_logs_total counter\n" +
+		"app_logs_total{name=\"a\\\"\",level=\"error\"} 1\n" +
+		"app_logs_total{name=\"b\",level=\"error\"} 2\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("MetricsHook.WritePrometheus(): want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+// DefaultMmapChunkSize is the default preallocation size of the memory-mapped file writer.
+const DefaultMmapChunkSize = 64 * 1024 * 1024
+
+// ErrMmapNotSupported is returned when the memory-mapped file writer is not supported
+// on the current platform.
+var ErrMmapNotSupported = errors.New("memory-mapped file writer is not supported on this platform")
+
+// NewMmapWriter creates and returns a memory-mapped file writer from the given path, which
+// is a high-throughput alternative to the write system call per log.
+// The file is preallocated in chunks of the given size, if the chunk size is less than or
+// equal to 0, DefaultMmapChunkSize is used. The mapped data is synchronized to the disk at
+// the given interval, if the interval is less than or equal to 0, the data is synchronized
+// only when the writer is closed. When the writer is closed, the file is truncated to the
+// actual size of the written data.
+// If the process crashes before the writer is closed, the file may contain trailing zero
+// bytes of the preallocated space.
+// The memory-mapped file writer is only supported on linux, darwin and freebsd, and
+// ErrMmapNotSupported is returned on other platforms.
+// The returned writer is safe for concurrent use.
+func NewMmapWriter(name string, chunk int64, interval time.Duration) (io.WriteCloser, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	if chunk <= 0 {
+		chunk = DefaultMmapChunkSize
+	}
+	return newMmapWriter(abs, chunk, interval)
+}
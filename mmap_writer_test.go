@@ -0,0 +1,63 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewMmapWriter(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(name, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewMmapWriter(name, 8, time.Millisecond)
+	if err == ErrMmapNotSupported {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("NewMmapWriter(): %s", err)
+	}
+
+	data := bytes.Repeat([]byte("0123456789\n"), 3)
+	for i := 0; i < 3; i++ {
+		if n, err := w.Write(data); err != nil || n != len(data) {
+			t.Fatalf("MmapWriter.Write(): %d %v", n, err)
+		}
+	}
+	time.Sleep(time.Millisecond * 5)
+	if err = w.Close(); err != nil {
+		t.Fatalf("MmapWriter.Close(): %s", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("MmapWriter.Close(): %s", err)
+	}
+	if _, err = w.Write(data); err == nil {
+		t.Fatal("MmapWriter.Write(): nil error after closed")
+	}
+
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte("old\n"), bytes.Repeat(data, 3)...)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("MmapWriter: want %q, got %q", want, got)
+	}
+}
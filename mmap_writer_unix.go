@@ -0,0 +1,172 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin || freebsd
+
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// Creates the memory-mapped file writer.
+func newMmapWriter(name string, chunk int64, interval time.Duration) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(name), dirPerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	w := &mmapWriter{file: file, size: info.Size(), chunk: chunk}
+	if err = w.remap(w.size + chunk); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if interval > 0 {
+		w.done = make(chan struct{})
+		go w.syncer(interval)
+	}
+	return w, nil
+}
+
+// The built-in memory-mapped file writer.
+type mmapWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	data   []byte
+	size   int64 // The actual size of the written data.
+	chunk  int64 // The preallocation size.
+	done   chan struct{}
+	closed bool
+}
+
+// Write is the implementation of io.Writer interface.
+func (w *mmapWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if end := w.size + int64(len(p)); end > int64(len(w.data)) {
+		n := int64(len(w.data)) + w.chunk
+		if n < end {
+			n = end + w.chunk
+		}
+		if err := w.remap(n); err != nil {
+			return 0, err
+		}
+	}
+	copy(w.data[w.size:], p)
+	w.size += int64(len(p))
+	return len(p), nil
+}
+
+// Close is the implementation of io.Closer interface.
+func (w *mmapWriter) Close() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.done != nil {
+		close(w.done)
+	}
+	err = w.sync()
+	if err2 := w.unmap(); err == nil {
+		err = err2
+	}
+	if err2 := w.file.Truncate(w.size); err == nil {
+		err = err2
+	}
+	if err2 := w.file.Close(); err == nil {
+		err = err2
+	}
+	return
+}
+
+// Resizes the file to the given size and maps it into memory, the caller must hold the lock.
+func (w *mmapWriter) remap(size int64) error {
+	if err := w.unmap(); err != nil {
+		return err
+	}
+	if err := w.file.Truncate(size); err != nil {
+		return err
+	}
+	data, err := syscall.Mmap(int(w.file.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	w.data = data
+	return nil
+}
+
+// Unmaps the mapped memory, the caller must hold the lock.
+func (w *mmapWriter) unmap() error {
+	if w.data == nil {
+		return nil
+	}
+	if err := w.sync(); err != nil {
+		return err
+	}
+	err := syscall.Munmap(w.data)
+	w.data = nil
+	return err
+}
+
+// Synchronizes the mapped memory to the disk, the caller must hold the lock.
+func (w *mmapWriter) sync() error {
+	if len(w.data) == 0 {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&w.data[0])), uintptr(len(w.data)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Synchronizes the mapped memory periodically until the writer is closed.
+func (w *mmapWriter) syncer(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if !w.closed {
+				if err := w.sync(); err != nil {
+					internal.EchoError("Failed to sync %s: %s.", w.file.Name(), err)
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
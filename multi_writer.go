@@ -15,33 +15,82 @@
 package logger
 
 import (
+	"errors"
 	"io"
+	"os"
+	"time"
 )
 
+// ErrWriteTimeout is reported when the writer does not complete writing within the wait time.
+var ErrWriteTimeout = errors.New("write timeout")
+
 // NewMultiWriter creates a writer that duplicates its writes to all the provided writers.
 // If any writer fails to write, it will continue to try to write to other writers.
 // If you need to interrupt writing when any writer fails to write, please use io.MultiWriter.
-func NewMultiWriter(writers ...io.Writer) io.Writer {
+// Closing the returned writer closes all the provided writers that implement io.Closer,
+// except os.Stdout and os.Stderr, which are never closed.
+func NewMultiWriter(writers ...io.Writer) io.WriteCloser {
+	return NewMultiWriterWithOptions(MultiWriterOptions{}, writers...)
+}
+
+// MultiWriterOptions defines the options of the multiple channel writer.
+type MultiWriterOptions struct {
+	// ErrorHandler is called for each writer that fails to write, it receives the failed
+	// writer, the error and the number of bytes written to the writer.
+	// This is useful to find out which writer is unhealthy.
+	ErrorHandler func(w io.Writer, err error, n int)
+
+	// Parallel determines whether to write to all writers concurrently, so that a slow
+	// writer does not delay other writers. In parallel mode, the writers may be called
+	// concurrently by multiple logs, so they must be safe for concurrent use.
+	Parallel bool
+
+	// Timeout is the maximum wait time for parallel writing, and it is only used in
+	// parallel mode. The writers that do not complete writing within the wait time will
+	// continue to write in the background, and ErrWriteTimeout will be reported.
+	// If it is less than or equal to 0, we will wait for all writers to complete.
+	Timeout time.Duration
+}
+
+// NewMultiWriterWithOptions creates a writer that duplicates its writes to all the provided
+// writers with the given options.
+// Closing the returned writer closes all the provided writers that implement io.Closer,
+// except os.Stdout and os.Stderr, which are never closed.
+func NewMultiWriterWithOptions(opts MultiWriterOptions, writers ...io.Writer) io.WriteCloser {
 	ws := make([]io.Writer, 0, len(writers))
 	for i, j := 0, len(writers); i < j; i++ {
-		if w, ok := writers[i].(*multiWriter); ok {
+		// The nested multiple channel writer can only be flattened when it has no options.
+		if w, ok := writers[i].(*multiWriter); ok && w.errorHandler == nil && !w.parallel {
 			ws = append(ws, w.writers...)
 		} else {
 			ws = append(ws, writers[i])
 		}
 	}
-	return &multiWriter{ws}
+	return &multiWriter{writers: ws, errorHandler: opts.ErrorHandler, parallel: opts.Parallel, timeout: opts.Timeout}
 }
 
 // This is a multiple channel writer.
 type multiWriter struct {
-	writers []io.Writer
+	writers      []io.Writer
+	errorHandler func(io.Writer, error, int)
+	parallel     bool
+	timeout      time.Duration
+}
+
+// The result of a single writer in parallel mode.
+type multiWriterResult struct {
+	index int
+	n     int
+	err   error
 }
 
 // Write is the implementation of io.Writer interface.
 // If there are no writers available, we always return success. We only return the first
 // error encountered. We only return the maximum number of bytes written.
 func (w *multiWriter) Write(p []byte) (int, error) {
+	if w.parallel && len(w.writers) > 1 {
+		return w.writeParallel(p)
+	}
 	if j := len(w.writers); j > 0 {
 		var err error
 		var n int
@@ -50,6 +99,9 @@ func (w *multiWriter) Write(p []byte) (int, error) {
 			if e == nil && k != len(p) {
 				e = io.ErrShortWrite
 			}
+			if e != nil && w.errorHandler != nil {
+				w.errorHandler(w.writers[i], e, k)
+			}
 			if err == nil {
 				err = e
 			}
@@ -61,3 +113,87 @@ func (w *multiWriter) Write(p []byte) (int, error) {
 	}
 	return len(p), nil
 }
+
+// Write to all writers concurrently.
+func (w *multiWriter) writeParallel(p []byte) (n int, err error) {
+	if w.timeout > 0 {
+		// The writers may still be writing after we return, and the caller is allowed
+		// to reuse the given data, so we need to copy it.
+		p = append([]byte(nil), p...)
+	}
+	// The buffered channel ensures that the writers will never be blocked.
+	results := make(chan multiWriterResult, len(w.writers))
+	for i, j := 0, len(w.writers); i < j; i++ {
+		go func(i int) {
+			k, e := w.writers[i].Write(p)
+			results <- multiWriterResult{index: i, n: k, err: e}
+		}(i)
+	}
+	var timeout <-chan time.Time
+	if w.timeout > 0 {
+		timer := time.NewTimer(w.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	done := make([]bool, len(w.writers))
+	for i, j := 0, len(w.writers); i < j; i++ {
+		select {
+		case r := <-results:
+			done[r.index] = true
+			if r.err == nil && r.n != len(p) {
+				r.err = io.ErrShortWrite
+			}
+			if r.err != nil && w.errorHandler != nil {
+				w.errorHandler(w.writers[r.index], r.err, r.n)
+			}
+			if err == nil {
+				err = r.err
+			}
+			if r.n > n {
+				n = r.n
+			}
+		case <-timeout:
+			for k := range done {
+				if !done[k] && w.errorHandler != nil {
+					w.errorHandler(w.writers[k], ErrWriteTimeout, 0)
+				}
+			}
+			if err == nil {
+				err = ErrWriteTimeout
+			}
+			return
+		}
+	}
+	return
+}
+
+// Close is the implementation of io.Closer interface.
+// All writers that implement the io.Closer interface are closed only once, and we only
+// return the first error encountered. The standard streams are not closed.
+func (w *multiWriter) Close() (err error) {
+	var closed []io.Closer
+	for i, j := 0, len(w.writers); i < j; i++ {
+		c, ok := w.writers[i].(io.Closer)
+		if !ok || isStandardStream(c) || containsCloser(closed, c) {
+			continue
+		}
+		closed = append(closed, c)
+		if e := c.Close(); err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// Determines whether the given closer is os.Stdout or os.Stderr, which belong to the
+// process and must not be closed by the writers.
+func isStandardStream(c io.Closer) bool {
+	return c == io.Closer(os.Stdout) || c == io.Closer(os.Stderr)
+}
+
+// Rotate is an implementation of the Rotator interface.
+// All writers that implement the Rotator interface are rotated, and we only return the
+// first error encountered.
+func (w *multiWriter) Rotate() error {
+	return rotateWriters(w.writers)
+}
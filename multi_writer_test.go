@@ -17,7 +17,10 @@ package logger
 import (
 	"bytes"
 	"errors"
+	"io"
+	"os"
 	"testing"
+	"time"
 )
 
 func TestNewMultiWriter(t *testing.T) {
@@ -101,3 +104,128 @@ func TestMultiWriter_Write_WithErrorWriter(t *testing.T) {
 		t.Fatalf("NewMultiWriter().Write(): got %s", got)
 	}
 }
+
+func TestNewMultiWriterWithOptions(t *testing.T) {
+	var failed []io.Writer
+	var errs []error
+	opts := MultiWriterOptions{ErrorHandler: func(w io.Writer, err error, n int) {
+		failed = append(failed, w)
+		errs = append(errs, err)
+	}}
+	w1 := &testFixedReturnValueWriter{n: 2}
+	w2 := bytes.NewBufferString("w2")
+	w3 := &testFixedReturnValueWriter{err: errors.New("error")}
+	w := NewMultiWriterWithOptions(opts, w1, w2, NewMultiWriter(w3))
+
+	if n, err := w.Write([]byte("test")); err != io.ErrShortWrite || n != 4 {
+		t.Fatalf("NewMultiWriterWithOptions().Write(): %d %v", n, err)
+	}
+	if len(failed) != 2 || failed[0] != w1 || failed[1] != w3 {
+		t.Fatalf("NewMultiWriterWithOptions().Write(): failed writers %v", failed)
+	}
+	if errs[0] != io.ErrShortWrite || errs[1].Error() != "error" {
+		t.Fatalf("NewMultiWriterWithOptions().Write(): errors %v", errs)
+	}
+
+	// The multiple channel writer with options is not flattened.
+	if n := len(NewMultiWriter(w, w2).(*multiWriter).writers); n != 2 {
+		t.Fatalf("NewMultiWriter(): %d writers", n)
+	}
+}
+
+type testSlowWriter struct {
+	d time.Duration
+}
+
+func (w *testSlowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.d)
+	return len(p), nil
+}
+
+func TestMultiWriter_WriteParallel(t *testing.T) {
+	b := new(testSyncBuffer)
+	w1 := &testFixedReturnValueWriter{err: errors.New("error")}
+	var failed []io.Writer
+	opts := MultiWriterOptions{Parallel: true, ErrorHandler: func(w io.Writer, err error, n int) {
+		failed = append(failed, w)
+	}}
+	w := NewMultiWriterWithOptions(opts, b, w1)
+
+	if n, err := w.Write([]byte("test")); err == nil || n != 4 {
+		t.Fatalf("MultiWriter.Write(): %d %v", n, err)
+	}
+	if got := string(b.Bytes()); got != "test" {
+		t.Fatalf("MultiWriter.Write(): %q", got)
+	}
+	if len(failed) != 1 || failed[0] != w1 {
+		t.Fatalf("MultiWriter.Write(): failed writers %v", failed)
+	}
+}
+
+func TestMultiWriter_WriteParallelTimeout(t *testing.T) {
+	b := new(testSyncBuffer)
+	slow := &testSlowWriter{time.Second}
+	var failed []io.Writer
+	var errs []error
+	opts := MultiWriterOptions{Parallel: true, Timeout: time.Millisecond * 50, ErrorHandler: func(w io.Writer, err error, n int) {
+		failed = append(failed, w)
+		errs = append(errs, err)
+	}}
+	w := NewMultiWriterWithOptions(opts, slow, b)
+
+	start := time.Now()
+	if n, err := w.Write([]byte("test")); err != ErrWriteTimeout || n != 4 {
+		t.Fatalf("MultiWriter.Write(): %d %v", n, err)
+	}
+	if d := time.Since(start); d >= time.Second {
+		t.Fatalf("MultiWriter.Write(): blocked %s", d)
+	}
+	if len(failed) != 1 || failed[0] != slow || errs[0] != ErrWriteTimeout {
+		t.Fatalf("MultiWriter.Write(): failed writers %v %v", failed, errs)
+	}
+}
+
+func TestMultiWriter_Rotate(t *testing.T) {
+	rw1 := new(testRotateWriter)
+	rw2 := &testRotateWriter{err: errors.New("test")}
+	w := NewMultiWriter(rw1, new(bytes.Buffer), rw2, rw1)
+	if err := w.(Rotator).Rotate(); err == nil {
+		t.Fatal("MultiWriter.Rotate(): nil error")
+	}
+	if rw1.rotated != 1 || rw2.rotated != 1 {
+		t.Fatalf("MultiWriter.Rotate(): rotated %d, %d", rw1.rotated, rw2.rotated)
+	}
+}
+
+func TestMultiWriter_Close(t *testing.T) {
+	w1, w2 := new(testSyncBuffer), new(testSyncBuffer)
+	w3 := &testCloseErrorWriter{err: errors.New("test")}
+	// The nested writer is flattened, and the duplicate writers are closed once.
+	w := NewMultiWriter(w1, NewMultiWriter(w2, w1), new(bytes.Buffer), w3)
+	if err := w.Close(); err == nil {
+		t.Fatal("MultiWriter.Close(): nil error")
+	}
+	if !w1.closed || !w2.closed {
+		t.Fatal("MultiWriter.Close(): writers not closed")
+	}
+	if err := NewMultiWriter().Close(); err != nil {
+		t.Fatalf("MultiWriter.Close(): %s", err)
+	}
+}
+
+func TestMultiWriter_Close_StandardStreams(t *testing.T) {
+	w1 := new(testSyncBuffer)
+	w := NewMultiWriter(os.Stdout, os.Stderr, w1)
+	if err := w.Close(); err != nil {
+		t.Fatalf("MultiWriter.Close(): %s", err)
+	}
+	if !w1.closed {
+		t.Fatal("MultiWriter.Close(): writer not closed")
+	}
+	// The standard streams are still available.
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if _, err := f.Stat(); err != nil {
+			t.Fatalf("MultiWriter.Close(): %s", err)
+		}
+	}
+}
@@ -43,3 +43,14 @@ func (w *mutexWriter) Write(p []byte) (n int, err error) {
 	n, err = w.w.Write(p)
 	return
 }
+
+// Rotate is an implementation of the Rotator interface.
+// If the wrapped writer does not implement the Rotator interface, nothing will be done.
+func (w *mutexWriter) Rotate() error {
+	if r, ok := w.w.(Rotator); ok {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return r.Rotate()
+	}
+	return nil
+}
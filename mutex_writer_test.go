@@ -70,3 +70,28 @@ func TestMutexWriter_Write(t *testing.T) {
 		t.Fatalf("MutexWriter: want %d, got %d", 10, iw.n)
 	}
 }
+
+type testRotateWriter struct {
+	testSyncBuffer
+	rotated int
+	err     error
+}
+
+func (w *testRotateWriter) Rotate() error {
+	w.rotated++
+	return w.err
+}
+
+func TestMutexWriter_Rotate(t *testing.T) {
+	rw := new(testRotateWriter)
+	if err := NewMutexWriter(rw).(Rotator).Rotate(); err != nil {
+		t.Fatalf("MutexWriter.Rotate(): %s", err)
+	}
+	if rw.rotated != 1 {
+		t.Fatalf("MutexWriter.Rotate(): rotated %d", rw.rotated)
+	}
+	// The writers that do not support rotation are ignored.
+	if err := NewMutexWriter(new(bytes.Buffer)).(Rotator).Rotate(); err != nil {
+		t.Fatalf("MutexWriter.Rotate(): %s", err)
+	}
+}
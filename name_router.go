@@ -0,0 +1,142 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NewNameRouter creates and returns a router that selects the destination writer by the
+// logger name, so services using sub-logger names can split the output with one configuration:
+//
+//	NewNameRouter(map[string]io.Writer{"db.*": dbWriter, "http": httpWriter, "*": appWriter})
+//
+// The rule "db.*" matches the logger named "db" and all loggers whose names start with "db.",
+// the rule "http" only matches the logger named "http", and the rule "*" matches all loggers.
+// The exact rules take precedence over the prefix rules, and the longer prefix rules take
+// precedence over the shorter ones. If no rule matches, the log is written to the default writer.
+// Like the logger output writers, the routed writers are not serialized by the router.
+func NewNameRouter(rules map[string]io.Writer) (Router, error) {
+	r := &nameRouter{exact: make(map[string]io.Writer)}
+	for rule, w := range rules {
+		if w == nil {
+			return nil, fmt.Errorf("nil writer for name router rule %q", rule)
+		}
+		switch {
+		case rule == "*":
+			r.fallback = w
+		case strings.HasSuffix(rule, ".*"):
+			r.prefixes = append(r.prefixes, nameRouterPrefix{prefix: rule[:len(rule)-2], writer: w})
+		case rule == "" || strings.Contains(rule, "*"):
+			return nil, fmt.Errorf("invalid name router rule %q", rule)
+		default:
+			r.exact[rule] = w
+		}
+	}
+	sort.Slice(r.prefixes, func(i, j int) bool {
+		return len(r.prefixes[i].prefix) > len(r.prefixes[j].prefix)
+	})
+	return r, nil
+}
+
+// MustNewNameRouter is like NewNameRouter, but triggers a panic when an error occurs.
+func MustNewNameRouter(rules map[string]io.Writer) Router {
+	r, err := NewNameRouter(rules)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// The built-in name router.
+type nameRouter struct {
+	exact    map[string]io.Writer
+	prefixes []nameRouterPrefix // Sorted from the longest prefix to the shortest.
+	fallback io.Writer
+}
+
+// The prefix rule of the name router.
+type nameRouterPrefix struct {
+	prefix string
+	writer io.Writer
+}
+
+// Intercept is an implementation of the Router interface.
+func (r *nameRouter) Intercept(s Summary, w io.Writer) (int, error) {
+	return r.match(s.Name(), w).Write(s.Bytes())
+}
+
+// Close is an implementation of the Router interface, used to close all routed writers
+// that implement the io.Closer interface, and each writer is closed only once.
+// The standard streams are not closed.
+func (r *nameRouter) Close() (err error) {
+	writers := make([]io.Writer, 0, len(r.exact)+len(r.prefixes)+1)
+	for _, w := range r.exact {
+		writers = append(writers, w)
+	}
+	for i, j := 0, len(r.prefixes); i < j; i++ {
+		writers = append(writers, r.prefixes[i].writer)
+	}
+	if r.fallback != nil {
+		writers = append(writers, r.fallback)
+	}
+	var closed []io.Closer
+	for i, j := 0, len(writers); i < j; i++ {
+		c, ok := writers[i].(io.Closer)
+		if !ok || isStandardStream(c) || containsCloser(closed, c) {
+			continue
+		}
+		closed = append(closed, c)
+		if e := c.Close(); err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// Returns the writer of the given logger name.
+func (r *nameRouter) match(name string, w io.Writer) io.Writer {
+	if found, ok := r.exact[name]; ok {
+		return found
+	}
+	for i, j := 0, len(r.prefixes); i < j; i++ {
+		p := r.prefixes[i].prefix
+		if name == p || (strings.HasPrefix(name, p) && name[len(p)] == '.') {
+			return r.prefixes[i].writer
+		}
+	}
+	if r.fallback != nil {
+		return r.fallback
+	}
+	return w
+}
+
+// Determines if the given closer is in the given list.
+func containsCloser(closers []io.Closer, c io.Closer) bool {
+	// Comparing uncomparable values will panic.
+	if !reflect.TypeOf(c).Comparable() {
+		return false
+	}
+	for i, j := 0, len(closers); i < j; i++ {
+		if closers[i] == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestNewNameRouter(t *testing.T) {
+	db, dbPool, http, app, def := new(testSyncBuffer), new(testSyncBuffer), new(testSyncBuffer),
+		new(testSyncBuffer), new(bytes.Buffer)
+	r, err := NewNameRouter(map[string]io.Writer{
+		"db.*":      db,
+		"db.pool.*": dbPool,
+		"http":      http,
+		"*":         app,
+	})
+	if err != nil {
+		t.Fatalf("NewNameRouter(): %s", err)
+	}
+	items := map[string]*testSyncBuffer{
+		"db":           db,
+		"db.query":     db,
+		"db.pool":      dbPool,
+		"db.pool.conn": dbPool,
+		"dbx":          app,
+		"http":         http,
+		"http.server":  app,
+		"":             app,
+	}
+	for name, want := range items {
+		before := want.Len()
+		New(name).SetOutput(def).SetOutputInterceptor(r.Intercept).Info("test")
+		if want.Len() == before {
+			t.Fatalf("NameRouter.Intercept(): %q not routed", name)
+		}
+	}
+	if def.Len() != 0 {
+		t.Fatalf("NameRouter.Intercept(): default writer %q", def.String())
+	}
+
+	// Each writer is closed only once.
+	if err = r.Close(); err != nil {
+		t.Fatalf("NameRouter.Close(): %s", err)
+	}
+	if !db.closed || !dbPool.closed || !http.closed || !app.closed {
+		t.Fatal("NameRouter.Close(): writers not closed")
+	}
+}
+
+func TestNameRouter_Default(t *testing.T) {
+	def := new(bytes.Buffer)
+	r := MustNewNameRouter(map[string]io.Writer{"db.*": new(bytes.Buffer)})
+	New("app").SetOutput(def).SetOutputInterceptor(r.Intercept).Info("test")
+	if def.Len() == 0 {
+		t.Fatal("NameRouter.Intercept(): default writer not used")
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("NameRouter.Close(): %s", err)
+	}
+}
+
+func TestNameRouter_CloseError(t *testing.T) {
+	w := &testSyncBuffer{}
+	r := MustNewNameRouter(map[string]io.Writer{"a": w, "b": &testCloseErrorWriter{errors.New("test")}})
+	if err := r.Close(); err == nil {
+		t.Fatal("NameRouter.Close(): nil error")
+	}
+	if !w.closed {
+		t.Fatal("NameRouter.Close(): writer not closed")
+	}
+}
+
+func TestNameRouter_Close_StandardStreams(t *testing.T) {
+	w := &testSyncBuffer{}
+	r := MustNewNameRouter(map[string]io.Writer{"a": w, "b": os.Stdout, "*": os.Stderr})
+	if err := r.Close(); err != nil {
+		t.Fatalf("NameRouter.Close(): %s", err)
+	}
+	if !w.closed {
+		t.Fatal("NameRouter.Close(): writer not closed")
+	}
+	// The standard streams are still available.
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if _, err := f.Stat(); err != nil {
+			t.Fatalf("NameRouter.Close(): %s", err)
+		}
+	}
+}
+
+type testCloseErrorWriter struct {
+	err error
+}
+
+func (w *testCloseErrorWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *testCloseErrorWriter) Close() error {
+	return w.err
+}
+
+func TestNewNameRouter_Error(t *testing.T) {
+	for _, rule := range []string{"", "db*", "*.db", "a.*.b"} {
+		if _, err := NewNameRouter(map[string]io.Writer{rule: new(bytes.Buffer)}); err == nil {
+			t.Fatalf("NewNameRouter(): %q nil error", rule)
+		}
+	}
+	if _, err := NewNameRouter(map[string]io.Writer{"db": nil}); err == nil {
+		t.Fatal("NewNameRouter(): nil writer nil error")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustNewNameRouter(): no panic")
+		}
+	}()
+	MustNewNameRouter(map[string]io.Writer{"": new(bytes.Buffer)})
+}
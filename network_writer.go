@@ -0,0 +1,224 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+const (
+	// DefaultNetworkBufferSize is the default number of logs buffered by the network writer
+	// while the connection is down.
+	DefaultNetworkBufferSize = 1000
+
+	// DefaultNetworkRetryInterval is the default minimum interval between the reconnections
+	// of the network writer.
+	DefaultNetworkRetryInterval = time.Second
+
+	// DefaultNetworkDialTimeout is the default dial timeout of the network writer.
+	DefaultNetworkDialTimeout = time.Second * 5
+)
+
+// NetworkWriterOptions defines the options of the network writer.
+type NetworkWriterOptions struct {
+	// BufferSize is the maximum number of logs buffered while the connection is down, the
+	// oldest logs are dropped when the buffer is full. If it is 0, DefaultNetworkBufferSize
+	// is used, and if it is less than 0, the logs are not buffered.
+	BufferSize int
+
+	// RetryInterval is the minimum interval between the reconnections, if it is less than
+	// or equal to 0, DefaultNetworkRetryInterval is used.
+	RetryInterval time.Duration
+
+	// DialTimeout is the dial timeout, if it is less than or equal to 0,
+	// DefaultNetworkDialTimeout is used.
+	DialTimeout time.Duration
+
+	// WriteTimeout is the write timeout of each log, if it is less than or equal to 0, the
+	// write is not timed out.
+	WriteTimeout time.Duration
+}
+
+// NewNetworkWriter creates and returns a writer that ships the logs over the given network
+// and address, such as "tcp" and "127.0.0.1:5000", which is useful for the log ingestion of
+// Logstash, Fluentd or Vector. The connection is established on the first write.
+// When the connection fails, the writer reconnects transparently on the later writes (at
+// most once per retry interval), and the logs written while the connection is down are
+// buffered and sent in order after the reconnection. So the writes never fail unless the
+// writer is closed, and the dropped logs are reported by the internal error handler.
+// When the returned writer is closed, the buffered logs are sent if the connection can
+// be established. The returned writer is safe for concurrent use, and it implements the
+// QueueStatsProvider interface.
+func NewNetworkWriter(network, address string, opts NetworkWriterOptions) io.WriteCloser {
+	w := &networkWriter{network: network, address: address, size: opts.BufferSize}
+	if w.size == 0 {
+		w.size = DefaultNetworkBufferSize
+	}
+	if w.retry = opts.RetryInterval; w.retry <= 0 {
+		w.retry = DefaultNetworkRetryInterval
+	}
+	if w.dialTimeout = opts.DialTimeout; w.dialTimeout <= 0 {
+		w.dialTimeout = DefaultNetworkDialTimeout
+	}
+	w.writeTimeout = opts.WriteTimeout
+	return w
+}
+
+// The networkRecord type is the buffered log of the network writer.
+type networkRecord struct {
+	data []byte
+	time time.Time
+}
+
+// The built-in network writer.
+type networkWriter struct {
+	mu           sync.Mutex
+	network      string
+	address      string
+	size         int
+	retry        time.Duration
+	dialTimeout  time.Duration
+	writeTimeout time.Duration
+	conn         net.Conn
+	dialed       time.Time // The time of the last dial.
+	buffer       []networkRecord
+	closed       bool
+	stats        QueueStats
+}
+
+// Write is the implementation of io.Writer interface.
+func (w *networkWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if w.connect(false) && w.flush() && w.send(p) {
+		return len(p), nil
+	}
+	w.push(p)
+	return len(p), nil
+}
+
+// Close is the implementation of io.Closer interface.
+func (w *networkWriter) Close() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.buffer) > 0 && w.connect(true) && !w.flush() {
+		internal.EchoError("(%s) Dropped %d network logs on close.", w.address, len(w.buffer))
+	}
+	w.buffer = nil
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	return
+}
+
+// Stats returns the current queue statistics.
+// The flushes of the network writer are the sends of the buffered logs after reconnection.
+func (w *networkWriter) Stats() QueueStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats := w.stats
+	if stats.Depth = len(w.buffer); stats.Depth > 0 {
+		stats.OldestAge = time.Since(w.buffer[0].time)
+	}
+	return stats
+}
+
+// Connects to the remote address if the connection is down, the caller must hold the lock.
+// Unless forced, the connection is only retried once per retry interval.
+func (w *networkWriter) connect(force bool) bool {
+	if w.conn != nil {
+		return true
+	}
+	if !force && !w.dialed.IsZero() && time.Since(w.dialed) < w.retry {
+		return false
+	}
+	w.dialed = time.Now()
+	conn, err := net.DialTimeout(w.network, w.address, w.dialTimeout)
+	if err != nil {
+		internal.EchoError("(%s) Failed to connect: %s.", w.address, err)
+		return false
+	}
+	w.conn = conn
+	return true
+}
+
+// Sends the given log, the caller must hold the lock.
+// If the sending fails, the connection is closed.
+func (w *networkWriter) send(p []byte) bool {
+	if w.writeTimeout > 0 {
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+	}
+	n, err := w.conn.Write(p)
+	if err == nil && n != len(p) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		internal.EchoError("(%s) Failed to write network log: %s.", w.address, err)
+		_ = w.conn.Close()
+		w.conn = nil
+		return false
+	}
+	return true
+}
+
+// Sends the buffered logs in order, the caller must hold the lock.
+func (w *networkWriter) flush() bool {
+	if len(w.buffer) == 0 {
+		return true
+	}
+	start := time.Now()
+	for len(w.buffer) > 0 {
+		if !w.send(w.buffer[0].data) {
+			return false
+		}
+		w.buffer[0] = networkRecord{}
+		w.buffer = w.buffer[1:]
+	}
+	w.buffer = nil
+	w.stats.Flushes++
+	w.stats.LastFlushLatency = time.Since(start)
+	if w.stats.LastFlushLatency > w.stats.MaxFlushLatency {
+		w.stats.MaxFlushLatency = w.stats.LastFlushLatency
+	}
+	return true
+}
+
+// Buffers the given log, the oldest log is dropped if the buffer is full.
+// The caller must hold the lock.
+func (w *networkWriter) push(p []byte) {
+	if w.size < 0 {
+		internal.EchoError("(%s) Dropped network log, the connection is down.", w.address)
+		return
+	}
+	if len(w.buffer) >= w.size {
+		w.buffer[0] = networkRecord{}
+		w.buffer = w.buffer[1:]
+		internal.EchoError("(%s) Dropped the oldest network log, the buffer is full.", w.address)
+	}
+	w.buffer = append(w.buffer, networkRecord{data: append([]byte(nil), p...), time: time.Now()})
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestNewNetworkWriter(t *testing.T) {
+	w := NewNetworkWriter("tcp", "127.0.0.1:0", NetworkWriterOptions{}).(*networkWriter)
+	if w.size != DefaultNetworkBufferSize || w.retry != DefaultNetworkRetryInterval || w.dialTimeout != DefaultNetworkDialTimeout {
+		t.Fatalf("NewNetworkWriter(): %+v", w)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("NetworkWriter.Close(): %s", err)
+	}
+}
+
+func TestNetworkWriter_Write(t *testing.T) {
+	buf := new(bytes.Buffer)
+	internal.ErrorWriter = buf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	_ = ln.Close()
+
+	w := NewNetworkWriter("tcp", address, NetworkWriterOptions{BufferSize: 2, RetryInterval: time.Millisecond})
+	// The connection is down, the logs are buffered and the oldest log is dropped.
+	for _, s := range []string{"a\n", "b\n", "c\n"} {
+		if n, err := w.Write([]byte(s)); err != nil || n != 2 {
+			t.Fatalf("NetworkWriter.Write(): %d %v", n, err)
+		}
+		time.Sleep(time.Millisecond * 2)
+	}
+	if s := w.(QueueStatsProvider).Stats(); s.Depth != 2 || s.OldestAge <= 0 {
+		t.Fatalf("NetworkWriter.Stats(): %+v", s)
+	}
+	if got := buf.String(); !strings.Contains(got, "Failed to connect") || !strings.Contains(got, "buffer is full") {
+		t.Fatalf("NetworkWriter.Write(): %q", got)
+	}
+
+	if ln, err = net.Listen("tcp", address); err != nil {
+		t.Skipf("net.Listen(): %s", err)
+	}
+	defer ln.Close()
+	time.Sleep(time.Millisecond * 2)
+	if _, err = w.Write([]byte("d\n")); err != nil {
+		t.Fatalf("NetworkWriter.Write(): %s", err)
+	}
+	if s := w.(QueueStatsProvider).Stats(); s.Depth != 0 || s.Flushes != 1 {
+		t.Fatalf("NetworkWriter.Stats(): %+v", s)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("NetworkWriter.Close(): %s", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("NetworkWriter.Close(): %s", err)
+	}
+	if _, err = w.Write([]byte("e\n")); err != io.ErrClosedPipe {
+		t.Fatalf("NetworkWriter.Write(): %v", err)
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	got, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "b\nc\nd\n" {
+		t.Fatalf("NetworkWriter.Write(): %q", got)
+	}
+}
+
+func TestNetworkWriter_Close(t *testing.T) {
+	buf := new(bytes.Buffer)
+	internal.ErrorWriter = buf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	_ = ln.Close()
+
+	w := NewNetworkWriter("tcp", address, NetworkWriterOptions{BufferSize: -1, RetryInterval: time.Hour})
+	if _, err = w.Write([]byte("a\n")); err != nil {
+		t.Fatalf("NetworkWriter.Write(): %s", err)
+	}
+	if s := w.(QueueStatsProvider).Stats(); s.Depth != 0 {
+		t.Fatalf("NetworkWriter.Stats(): %+v", s)
+	}
+	if got := buf.String(); !strings.Contains(got, "Dropped network log") {
+		t.Fatalf("NetworkWriter.Write(): %q", got)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("NetworkWriter.Close(): %s", err)
+	}
+}
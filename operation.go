@@ -0,0 +1,85 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Operation interface defines a scoped log of an operation, which is a lightweight tracing
+// for applications without a tracer.
+// All logs recorded by the operation carry the "operation" and "operation_id" fields, and the
+// nested operations also carry the "parent_operation_id" field.
+type Operation interface {
+	Log
+
+	// ID returns the unique id of the current operation.
+	ID() string
+
+	// End records the end log of the current operation with the "elapsed" field, and returns
+	// the elapsed time of the operation.
+	// If the given error is nil, the end log is recorded at InfoLevel, otherwise the end log
+	// is recorded at ErrorLevel with the "error" field.
+	End(error) time.Duration
+}
+
+// The built-in operation.
+type logOperation struct {
+	*log
+	id    string
+	name  string
+	start time.Time
+}
+
+// Creates a new operation and records its start log.
+func newLogOperation(o *log, name string) *logOperation {
+	id := newOperationID()
+	fields := map[string]interface{}{"operation": name, "operation_id": id}
+	if parent, found := o.fields["operation_id"]; found {
+		fields["parent_operation_id"] = parent
+	}
+	return &logOperation{log: o.withFields(fields), id: id, name: name, start: o.core.nowFunc()}
+}
+
+// ID returns the unique id of the current operation.
+func (o *logOperation) ID() string {
+	return o.id
+}
+
+// End records the end log of the current operation with the "elapsed" field, and returns
+// the elapsed time of the operation.
+// If the given error is nil, the end log is recorded at InfoLevel, otherwise the end log
+// is recorded at ErrorLevel with the "error" field.
+func (o *logOperation) End(err error) time.Duration {
+	d := o.log.core.nowFunc().Sub(o.start)
+	// Calling the internal log method directly ensures that the caller reported is the
+	// caller of the End method.
+	if err == nil {
+		o.log.withField("elapsed", d).log(InfoLevel, o.name+" finished")
+	} else {
+		o.log.withFields(map[string]interface{}{"elapsed": d, "error": err}).log(ErrorLevel, o.name+" failed")
+	}
+	return d
+}
+
+// Generates a new random operation id.
+func newOperationID() string {
+	var b [8]byte
+	// The crypto/rand.Read never fails on the supported platforms.
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
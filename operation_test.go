@@ -0,0 +1,73 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog_Begin(t *testing.T) {
+	now := time.Now()
+	o := New("test").SetOutput(io.Discard).SetNowFunc(func() time.Time { return now }).EnableCaller()
+
+	var summaries []Summary
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		summaries = append(summaries, s.Clone())
+		return nil
+	})
+
+	op := o.Begin("load")
+	if op == nil || len(op.ID()) != 16 {
+		t.Fatalf("Log.Begin(): %v", op)
+	}
+	sub := op.Begin("query")
+	sub.Info("running")
+	now = now.Add(time.Second)
+	if d := sub.End(errors.New("test")); d != time.Second {
+		t.Fatalf("Operation.End(): %s", d)
+	}
+	if d := op.End(nil); d != time.Second {
+		t.Fatalf("Operation.End(): %s", d)
+	}
+
+	if len(summaries) != 5 {
+		t.Fatalf("Log.Begin(): %d logs", len(summaries))
+	}
+	messages := []string{"load started", "query started", "running", "query failed", "load finished"}
+	for i, s := range summaries {
+		if s.Message() != messages[i] {
+			t.Fatalf("Log.Begin(): message %s", s.Message())
+		}
+		if !strings.HasPrefix(s.Caller(), "operation_test.go:") {
+			t.Fatalf("Log.Begin(): caller %s", s.Caller())
+		}
+	}
+	if f := summaries[0].Fields(); f["operation"] != "load" || f["operation_id"] != op.ID() || len(f) != 2 {
+		t.Fatalf("Log.Begin(): %v", f)
+	}
+	if f := summaries[2].Fields(); f["operation_id"] != sub.ID() || f["parent_operation_id"] != op.ID() {
+		t.Fatalf("Log.Begin(): %v", f)
+	}
+	if s := summaries[3]; s.Level() != ErrorLevel || s.Fields()["elapsed"] != time.Second || s.Fields()["error"] == nil {
+		t.Fatalf("Operation.End(): %s %v", s.Level(), s.Fields())
+	}
+	if s := summaries[4]; s.Level() != InfoLevel || s.Fields()["elapsed"] != time.Second {
+		t.Fatalf("Operation.End(): %s %v", s.Level(), s.Fields())
+	}
+}
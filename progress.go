@@ -0,0 +1,141 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The interval at which the progress counter is polled by Progress.Run.
+const progressPollInterval = time.Millisecond * 100
+
+// ProgressOptions defines the options of the progress log.
+type ProgressOptions struct {
+	// Total is the total amount of work, it must be greater than 0.
+	Total int64
+
+	// Counter returns the amount of completed work, it must be safe for concurrent use.
+	Counter func() int64
+
+	// Interval is the minimum time interval between progress logs, the progress log is
+	// recorded when the interval has elapsed and the progress has changed.
+	// If it is less than or equal to 0, the time interval is disabled.
+	Interval time.Duration
+
+	// Percent is the minimum progress percentage (0-100) between progress logs, the progress
+	// log is recorded when the progress has advanced by at least the given percentage.
+	// If it is less than or equal to 0, the percentage step is disabled.
+	Percent float64
+
+	// Level is the level of the progress log, the default level is InfoLevel.
+	Level Level
+}
+
+// Progress interface defines a periodic progress log of long-running batch jobs.
+// The progress logs carry the "done", "total", "percent" and "elapsed" fields.
+type Progress interface {
+	// Check checks the progress counter and records a progress log if necessary, and returns
+	// whether the progress log is recorded. When the work is completed, a final progress log
+	// is recorded only once.
+	Check() bool
+
+	// Run checks the progress periodically until the work is completed or the given context
+	// is done, it blocks the current goroutine.
+	Run(context.Context)
+}
+
+// NewProgress creates and returns a progress log with the given message and options.
+func NewProgress(l Log, message string, opts ProgressOptions) Progress {
+	if !opts.Level.IsValid() {
+		opts.Level = InfoLevel
+	}
+	if opts.Total <= 0 {
+		opts.Total = 1
+	}
+	return &progress{log: l, message: message, opts: opts, start: time.Now(), last: time.Now()}
+}
+
+// The built-in progress log.
+type progress struct {
+	mu       sync.Mutex
+	log      Log
+	message  string
+	opts     ProgressOptions
+	start    time.Time
+	last     time.Time
+	done     int64
+	finished bool
+}
+
+// Check checks the progress counter and records a progress log if necessary, and returns
+// whether the progress log is recorded. When the work is completed, a final progress log
+// is recorded only once.
+func (p *progress) Check() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished || p.opts.Counter == nil {
+		return false
+	}
+	done, now := p.opts.Counter(), time.Now()
+	if done >= p.opts.Total {
+		p.finished = true
+	} else {
+		if done == p.done {
+			return false
+		}
+		byTime := p.opts.Interval > 0 && now.Sub(p.last) >= p.opts.Interval
+		byPercent := p.opts.Percent > 0 && p.percent(done)-p.percent(p.done) >= p.opts.Percent
+		if !byTime && !byPercent {
+			return false
+		}
+	}
+	p.done, p.last = done, now
+	p.log.WithFields(map[string]interface{}{
+		"done": done, "total": p.opts.Total, "percent": p.percent(done), "elapsed": now.Sub(p.start),
+	}).Log(p.opts.Level, p.message)
+	return true
+}
+
+// Run checks the progress periodically until the work is completed or the given context
+// is done, it blocks the current goroutine.
+func (p *progress) Run(ctx context.Context) {
+	ticker := time.NewTicker(progressPollInterval)
+	defer ticker.Stop()
+	for {
+		p.Check()
+		p.mu.Lock()
+		finished := p.finished
+		p.mu.Unlock()
+		if finished {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the progress percentage of the given amount of completed work.
+func (p *progress) percent(done int64) float64 {
+	r := float64(done) * 100 / float64(p.opts.Total)
+	if r > 100 {
+		return 100
+	}
+	return r
+}
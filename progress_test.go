@@ -0,0 +1,100 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewProgress(t *testing.T) {
+	if NewProgress(New("test"), "test", ProgressOptions{}) == nil {
+		t.Fatal("NewProgress(): nil")
+	}
+}
+
+func TestProgress_Check(t *testing.T) {
+	o := New("test").SetOutput(io.Discard)
+	var fields []map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		fields = append(fields, s.Fields())
+		return nil
+	})
+
+	var n int64
+	p := NewProgress(o, "import", ProgressOptions{Total: 100, Counter: func() int64 { return n }, Percent: 10})
+
+	if p.Check() {
+		t.Fatal("Progress.Check(): logged without progress")
+	}
+	n = 5
+	if p.Check() {
+		t.Fatal("Progress.Check(): logged below percent step")
+	}
+	n = 10
+	if !p.Check() {
+		t.Fatal("Progress.Check(): not logged")
+	}
+	if f := fields[0]; f["done"] != int64(10) || f["total"] != int64(100) || f["percent"] != float64(10) {
+		t.Fatalf("Progress.Check(): %v", f)
+	}
+	n = 19
+	if p.Check() {
+		t.Fatal("Progress.Check(): logged below percent step")
+	}
+	n = 100
+	if !p.Check() || p.Check() {
+		t.Fatal("Progress.Check(): final log")
+	}
+	if len(fields) != 2 {
+		t.Fatalf("Progress.Check(): %d logs", len(fields))
+	}
+
+	n = 0
+	p = NewProgress(o, "import", ProgressOptions{Total: 100, Counter: func() int64 { return n }, Interval: time.Millisecond})
+	n = 1
+	time.Sleep(time.Millisecond * 2)
+	if !p.Check() {
+		t.Fatal("Progress.Check(): not logged after interval")
+	}
+}
+
+func TestProgress_Run(t *testing.T) {
+	o := New("test").SetOutput(io.Discard)
+	var logs int32
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		atomic.AddInt32(&logs, 1)
+		return nil
+	})
+
+	var n int64 = 100
+	p := NewProgress(o, "import", ProgressOptions{Total: 100, Counter: func() int64 { return atomic.LoadInt64(&n) }})
+	p.Run(context.Background())
+	if got := atomic.LoadInt32(&logs); got != 1 {
+		t.Fatalf("Progress.Run(): %d logs", got)
+	}
+
+	atomic.StoreInt64(&n, 0)
+	p = NewProgress(o, "import", ProgressOptions{Total: 100, Counter: func() int64 { return atomic.LoadInt64(&n) }})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*150)
+	defer cancel()
+	p.Run(ctx)
+	if got := atomic.LoadInt32(&logs); got != 1 {
+		t.Fatalf("Progress.Run(): %d logs", got)
+	}
+}
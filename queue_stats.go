@@ -0,0 +1,56 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// QueueStats defines the runtime statistics of the writers that queue the logs, which makes
+// capacity issues visible before the logs are dropped.
+// The QueueStats implements the expvar.Var interface, so it can be published directly:
+//
+//	expvar.Publish("logger", expvar.Func(func() interface{} { return w.(QueueStatsProvider).Stats() }))
+type QueueStats struct {
+	// Depth is the number of pending logs.
+	Depth int `json:"depth"`
+
+	// OldestAge is the age of the oldest pending log.
+	OldestAge time.Duration `json:"oldest_age"`
+
+	// Flushes is the total number of flushes.
+	Flushes uint64 `json:"flushes"`
+
+	// LastFlushLatency is the latency of the last flush.
+	LastFlushLatency time.Duration `json:"last_flush_latency"`
+
+	// MaxFlushLatency is the maximum latency of all flushes.
+	MaxFlushLatency time.Duration `json:"max_flush_latency"`
+}
+
+// String returns the JSON string of the current statistics.
+func (s QueueStats) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// QueueStatsProvider interface defines the writer that provides the queue statistics.
+// The writers created by NewBatchWriter, NewAsyncWriter and NewNetworkWriter implement
+// this interface.
+type QueueStatsProvider interface {
+	// Stats returns the current queue statistics.
+	Stats() QueueStats
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestQueueStats_String(t *testing.T) {
+	s := QueueStats{Depth: 1, OldestAge: time.Second, Flushes: 2}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s.String()), &m); err != nil {
+		t.Fatalf("QueueStats.String(): %s", err)
+	}
+	if m["depth"] != float64(1) || m["oldest_age"] != float64(time.Second) || m["flushes"] != float64(2) {
+		t.Fatalf("QueueStats.String(): %v", m)
+	}
+}
+
+func TestBatchWriter_Stats(t *testing.T) {
+	w := NewBatchWriter(new(testChunkWriter), 2, 0)
+	p, ok := w.(QueueStatsProvider)
+	if !ok {
+		t.Fatal("NewBatchWriter(): not QueueStatsProvider")
+	}
+	if s := p.Stats(); s.Depth != 0 || s.OldestAge != 0 || s.Flushes != 0 {
+		t.Fatalf("BatchWriter.Stats(): %+v", s)
+	}
+	_, _ = w.Write([]byte("a\n"))
+	time.Sleep(time.Millisecond)
+	if s := p.Stats(); s.Depth != 1 || s.OldestAge <= 0 {
+		t.Fatalf("BatchWriter.Stats(): %+v", s)
+	}
+	_, _ = w.Write([]byte("b\n"))
+	if s := p.Stats(); s.Depth != 0 || s.OldestAge != 0 || s.Flushes != 1 || s.MaxFlushLatency < s.LastFlushLatency {
+		t.Fatalf("BatchWriter.Stats(): %+v", s)
+	}
+}
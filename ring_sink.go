@@ -0,0 +1,135 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// DefaultRingSinkSize is the default number of the logs kept by the ring sink.
+const DefaultRingSinkSize = 1000
+
+// RingSink interface defines a bounded in-memory sink that keeps the last logs of all levels,
+// regardless of the logger level, so that the operators can inspect the recent activity of
+// a live process on demand. The ring sink is also an http.Handler that dumps the kept logs
+// as a json array, the optional "level" query parameter filters the logs by the lowest level:
+//
+//	l.SetRingSink(sink)
+//	http.Handle("/debug/logs", sink)
+type RingSink interface {
+	http.Handler
+
+	// Add adds a copy of the given log summary to the sink, the oldest log is discarded when
+	// the sink is full.
+	Add(Summary)
+
+	// Records returns the kept logs from oldest to newest.
+	Records() []Summary
+
+	// Len returns the number of the kept logs.
+	Len() int
+
+	// Reset discards all the kept logs.
+	Reset()
+}
+
+// NewRingSink creates and returns a ring sink that keeps the last given number of logs.
+// If the given size is less than or equal to 0, DefaultRingSinkSize is used.
+func NewRingSink(size int) RingSink {
+	if size <= 0 {
+		size = DefaultRingSinkSize
+	}
+	return &ringSink{records: make([]Summary, size)}
+}
+
+// The built-in ring sink.
+type ringSink struct {
+	mu      sync.Mutex
+	records []Summary
+	next    int // The index of the next log.
+	full    bool
+}
+
+// Add adds a copy of the given log summary to the sink, the oldest log is discarded when
+// the sink is full.
+func (r *ringSink) Add(s Summary) {
+	c := s.Clone()
+	r.mu.Lock()
+	r.records[r.next] = c
+	if r.next++; r.next == len(r.records) {
+		r.next, r.full = 0, true
+	}
+	r.mu.Unlock()
+}
+
+// Records returns the kept logs from oldest to newest.
+func (r *ringSink) Records() []Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Summary(nil), r.records[:r.next]...)
+	}
+	return append(append(make([]Summary, 0, len(r.records)), r.records[r.next:]...), r.records[:r.next]...)
+}
+
+// Len returns the number of the kept logs.
+func (r *ringSink) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		return len(r.records)
+	}
+	return r.next
+}
+
+// Reset discards all the kept logs.
+func (r *ringSink) Reset() {
+	r.mu.Lock()
+	for i := range r.records {
+		r.records[i] = nil
+	}
+	r.next, r.full = 0, false
+	r.mu.Unlock()
+}
+
+// ServeHTTP dumps the kept logs as a json array.
+func (r *ringSink) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	lowest := TraceLevel
+	if s := req.URL.Query().Get("level"); s != "" {
+		level, err := ParseLevel(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		lowest = level
+	}
+
+	records := r.Records()
+	objects := make([]summaryObject, 0, len(records))
+	for i, j := 0, len(records); i < j; i++ {
+		if lowest.IsEnabled(records[i].Level()) {
+			objects = append(objects, newSummaryObject(records[i]))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(objects); err != nil {
+		internal.EchoError("Failed to dump ring sink logs: %s", err)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewRingSink(t *testing.T) {
+	if NewRingSink(0) == nil {
+		t.Fatal("NewRingSink(): nil")
+	}
+}
+
+func TestRingSink(t *testing.T) {
+	r := NewRingSink(3)
+	for _, m := range []string{"a", "b"} {
+		r.Add(&logEntity{level: InfoLevel, message: m})
+	}
+	if got := r.Len(); got != 2 {
+		t.Fatalf("RingSink.Len(): %d", got)
+	}
+	if got := r.Records(); len(got) != 2 || got[0].Message() != "a" || got[1].Message() != "b" {
+		t.Fatalf("RingSink.Records(): %v", got)
+	}
+
+	for _, m := range []string{"c", "d", "e"} {
+		r.Add(&logEntity{level: InfoLevel, message: m})
+	}
+	if got := r.Len(); got != 3 {
+		t.Fatalf("RingSink.Len(): %d", got)
+	}
+	got := r.Records()
+	if len(got) != 3 || got[0].Message() != "c" || got[1].Message() != "d" || got[2].Message() != "e" {
+		t.Fatalf("RingSink.Records(): %v", got)
+	}
+
+	r.Reset()
+	if got := r.Len(); got != 0 {
+		t.Fatalf("RingSink.Reset(): %d", got)
+	}
+}
+
+func TestRingSink_ServeHTTP(t *testing.T) {
+	r := NewRingSink(10)
+	r.Add(&logEntity{level: ErrorLevel, message: "a"})
+	r.Add(&logEntity{level: DebugLevel, message: "b", fields: map[string]interface{}{"foo": 1}})
+
+	items := []struct {
+		Query    string
+		Code     int
+		Messages []string
+	}{
+		{"", http.StatusOK, []string{"a", "b"}},
+		{"?level=warn", http.StatusOK, []string{"a"}},
+		{"?level=test", http.StatusBadRequest, nil},
+	}
+
+	for _, item := range items {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs"+item.Query, nil))
+		if rec.Code != item.Code {
+			t.Fatalf("RingSink.ServeHTTP(): %s: code %d", item.Query, rec.Code)
+		}
+		if item.Code != http.StatusOK {
+			continue
+		}
+		var objects []map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &objects); err != nil {
+			t.Fatalf("RingSink.ServeHTTP(): error %s", err)
+		}
+		if len(objects) != len(item.Messages) {
+			t.Fatalf("RingSink.ServeHTTP(): %s: %s", item.Query, rec.Body.String())
+		}
+		for i, m := range item.Messages {
+			if objects[i]["message"] != m {
+				t.Fatalf("RingSink.ServeHTTP(): %s: %s", item.Query, rec.Body.String())
+			}
+		}
+	}
+}
+
+func TestLogger_SetRingSink(t *testing.T) {
+	r := NewRingSink(10)
+	l := New("test")
+	buf := new(bytes.Buffer)
+	l.SetOutput(buf)
+	l.SetLevel(InfoLevel)
+
+	if l.SetRingSink(r) == nil {
+		t.Fatal("Logger.SetRingSink(): nil")
+	}
+
+	l.Debug("foo")
+	l.Info("bar")
+
+	if got := r.Records(); len(got) != 2 || got[0].Message() != "foo" || got[1].Message() != "bar" {
+		t.Fatalf("Logger.SetRingSink(): %v", got)
+	}
+	// The logs below the logger level are not written.
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 1 {
+		t.Fatalf("Logger.SetRingSink(): %q", buf.String())
+	}
+
+	l.SetRingSink(nil)
+	l.Debug("foo")
+	if got := r.Len(); got != 2 {
+		t.Fatalf("Logger.SetRingSink(nil): %d", got)
+	}
+}
+
+func TestLogger_SetRingSink_Check(t *testing.T) {
+	r := NewRingSink(10)
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetLevel(InfoLevel).SetRingSink(r)
+
+	if ce := l.Check(DebugLevel, "foo"); ce != nil {
+		t.Fatal("Log.Check(): disabled level returns entry")
+	}
+	if got := r.Records(); len(got) != 1 || got[0].Message() != "foo" || got[0].Level() != DebugLevel {
+		t.Fatalf("Log.Check(): %v", got)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Log.Check(): %q", buf.String())
+	}
+}
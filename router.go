@@ -0,0 +1,213 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// Router interface defines the log routers, which select the destination writer of each
+// log by the log summary. The router is used as the output interceptor of the logger:
+//
+//	logger.SetOutputInterceptor(router.Intercept)
+type Router interface {
+	io.Closer
+
+	// Intercept writes the given log to the selected writer, if no writer is selected,
+	// the log is written to the given default writer.
+	Intercept(Summary, io.Writer) (int, error)
+}
+
+// DefaultFieldRouterMaxOpenFiles is the default maximum number of files kept open by the
+// field router.
+const DefaultFieldRouterMaxOpenFiles = 64
+
+// FieldRouterOptions defines the options of the field router.
+type FieldRouterOptions struct {
+	// MaxOpenFiles is the maximum number of files kept open, the least recently used file
+	// is closed when the limit is exceeded. If it is less than or equal to 0,
+	// DefaultFieldRouterMaxOpenFiles is used.
+	MaxOpenFiles int
+
+	// Open opens the writer of the given file path.
+	// By default, NewFileWriter is used without the size limit.
+	Open func(path string) (io.WriteCloser, error)
+}
+
+// NewFieldRouter creates and returns a router that selects the destination file by the
+// given template, such as "logs/{tenant}.log", which enables per-tenant or per-job log files
+// without separate logger instances.
+// The placeholders in the template are replaced with the values of the log fields, the path
+// separators in the field values are replaced with underscores. If any field of the template
+// is missing or empty, the log is written to the default writer.
+func NewFieldRouter(template string, opts FieldRouterOptions) (Router, error) {
+	segments, fields, err := parseRouterTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxOpenFiles <= 0 {
+		opts.MaxOpenFiles = DefaultFieldRouterMaxOpenFiles
+	}
+	if opts.Open == nil {
+		opts.Open = func(path string) (io.WriteCloser, error) { return NewFileWriter(path, 0, 0) }
+	}
+	return &fieldRouter{
+		segments: segments, fields: fields, opts: opts,
+		files: make(map[string]*list.Element), lru: list.New(),
+	}, nil
+}
+
+// MustNewFieldRouter is like NewFieldRouter, but triggers a panic when an error occurs.
+func MustNewFieldRouter(template string, opts FieldRouterOptions) Router {
+	r, err := NewFieldRouter(template, opts)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// The built-in field router.
+type fieldRouter struct {
+	mu       sync.Mutex
+	segments []string // The literal segments of the template, one more than the fields.
+	fields   []string // The field names of the template.
+	opts     FieldRouterOptions
+	files    map[string]*list.Element
+	lru      *list.List // The open files, the most recently used is at the front.
+}
+
+// The open file of the field router.
+type fieldRouterFile struct {
+	path   string
+	writer io.WriteCloser
+}
+
+// Intercept is an implementation of the Router interface.
+// The writes are serialized, so the routed writers do not need to be safe for concurrent use.
+func (r *fieldRouter) Intercept(s Summary, w io.Writer) (int, error) {
+	path, ok := r.path(s.Fields())
+	if !ok {
+		return w.Write(s.Bytes())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := r.get(path)
+	if err != nil {
+		return 0, err
+	}
+	return f.Write(s.Bytes())
+}
+
+// Close is an implementation of the Router interface, used to close all open files.
+func (r *fieldRouter) Close() (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for e := r.lru.Front(); e != nil; e = e.Next() {
+		if err2 := e.Value.(*fieldRouterFile).writer.Close(); err == nil {
+			err = err2
+		}
+	}
+	r.files = make(map[string]*list.Element)
+	r.lru.Init()
+	return
+}
+
+// Builds the destination file path from the given log fields.
+func (r *fieldRouter) path(fields map[string]interface{}) (string, bool) {
+	var b strings.Builder
+	for i, j := 0, len(r.fields); i < j; i++ {
+		v, found := fields[r.fields[i]]
+		if !found || v == nil {
+			return "", false
+		}
+		s := sanitizePathValue(fmt.Sprint(v))
+		if s == "" {
+			return "", false
+		}
+		b.WriteString(r.segments[i])
+		b.WriteString(s)
+	}
+	b.WriteString(r.segments[len(r.fields)])
+	return b.String(), true
+}
+
+// Gets the writer of the given path, opens it if necessary.
+func (r *fieldRouter) get(path string) (io.Writer, error) {
+	if e, found := r.files[path]; found {
+		r.lru.MoveToFront(e)
+		return e.Value.(*fieldRouterFile).writer, nil
+	}
+	w, err := r.opts.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r.files[path] = r.lru.PushFront(&fieldRouterFile{path: path, writer: w})
+	for r.lru.Len() > r.opts.MaxOpenFiles {
+		f := r.lru.Remove(r.lru.Back()).(*fieldRouterFile)
+		delete(r.files, f.path)
+		if err = f.writer.Close(); err != nil {
+			internal.EchoError("Failed to close %s: %s.", f.path, err)
+		}
+	}
+	return w, nil
+}
+
+// Parses the given router template into the literal segments and the field names.
+func parseRouterTemplate(template string) (segments, fields []string, err error) {
+	for {
+		i := strings.IndexByte(template, '{')
+		if i < 0 {
+			if strings.IndexByte(template, '}') >= 0 {
+				return nil, nil, fmt.Errorf("unexpected '}' in router template")
+			}
+			return append(segments, template), fields, nil
+		}
+		j := strings.IndexByte(template[i:], '}')
+		if j < 0 {
+			return nil, nil, fmt.Errorf("unclosed '{' in router template")
+		}
+		name := template[i+1 : i+j]
+		if name == "" || strings.ContainsAny(name, "{") {
+			return nil, nil, fmt.Errorf("invalid field name %q in router template", name)
+		}
+		if strings.IndexByte(template[:i], '}') >= 0 {
+			return nil, nil, fmt.Errorf("unexpected '}' in router template")
+		}
+		segments, fields = append(segments, template[:i]), append(fields, name)
+		template = template[i+j+1:]
+	}
+}
+
+// Sanitizes the given field value used as a part of the file path, so that the field
+// values cannot change the directory of the file.
+func sanitizePathValue(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', 0:
+			return '_'
+		}
+		return r
+	}, s)
+	if s == "." || s == ".." {
+		return strings.Repeat("_", len(s))
+	}
+	return s
+}
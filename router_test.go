@@ -0,0 +1,124 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFieldRouter(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewFieldRouter(filepath.Join(dir, "{tenant}-{job}.log"), FieldRouterOptions{})
+	if err != nil {
+		t.Fatalf("NewFieldRouter(): %s", err)
+	}
+	buf := new(bytes.Buffer)
+	l := New("test").SetOutput(buf).SetOutputInterceptor(r.Intercept).SetDefaultTimeFormat("-")
+
+	l.WithField("tenant", "foo").WithField("job", 1).Info("a")
+	l.WithField("tenant", "bar").WithField("job", 2).Info("b")
+	l.WithField("tenant", "foo").WithField("job", 1).Info("c")
+	l.WithField("tenant", "../x").WithField("job", 3).Info("d")
+	// The logs without the template fields are written to the default writer.
+	l.WithField("tenant", "foo").Info("e")
+	l.WithField("tenant", "").WithField("job", 4).Info("f")
+
+	if err = r.Close(); err != nil {
+		t.Fatalf("Router.Close(): %s", err)
+	}
+	items := map[string]int{"foo-1.log": 2, "bar-2.log": 1, ".._x-3.log": 1}
+	for name, lines := range items {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("FieldRouter.Intercept(): %s", err)
+		}
+		if n := bytes.Count(got, []byte("\n")); n != lines {
+			t.Fatalf("FieldRouter.Intercept(): %s has %d lines: %q", name, n, got)
+		}
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != 2 {
+		t.Fatalf("FieldRouter.Intercept(): default writer has %d lines: %q", n, buf.String())
+	}
+}
+
+func TestNewFieldRouter_Error(t *testing.T) {
+	for _, s := range []string{"{tenant", "tenant}", "{}.log", "{a{b}.log", "a}{b}.log"} {
+		if _, err := NewFieldRouter(s, FieldRouterOptions{}); err == nil {
+			t.Fatalf("NewFieldRouter(): %q nil error", s)
+		}
+	}
+}
+
+func TestMustNewFieldRouter(t *testing.T) {
+	if r := MustNewFieldRouter("{tenant}.log", FieldRouterOptions{}); r == nil {
+		t.Fatal("MustNewFieldRouter(): nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustNewFieldRouter(): no panic")
+		}
+	}()
+	MustNewFieldRouter("{tenant", FieldRouterOptions{})
+}
+
+func TestFieldRouter_MaxOpenFiles(t *testing.T) {
+	writers := make(map[string]*testSyncBuffer)
+	r := MustNewFieldRouter("{id}", FieldRouterOptions{
+		MaxOpenFiles: 2,
+		Open: func(path string) (io.WriteCloser, error) {
+			if path == "bad" {
+				return nil, errors.New("test")
+			}
+			w := new(testSyncBuffer)
+			writers[path] = w
+			return w, nil
+		},
+	})
+	l := New("test").SetOutputInterceptor(r.Intercept)
+	l.WithField("id", "a").Info("test")
+	l.WithField("id", "b").Info("test")
+	l.WithField("id", "a").Info("test")
+	// The least recently used file "b" is closed.
+	l.WithField("id", "c").Info("test")
+
+	if !writers["b"].closed || writers["a"].closed || writers["c"].closed {
+		t.Fatal("FieldRouter.Intercept(): unexpected closed files")
+	}
+	s := &logEntity{fields: map[string]interface{}{"id": "bad"}}
+	if _, err := r.Intercept(s, io.Discard); err == nil {
+		t.Fatal("FieldRouter.Intercept(): nil error")
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !writers["a"].closed || !writers["c"].closed {
+		t.Fatal("FieldRouter.Close(): files not closed")
+	}
+}
+
+func TestSanitizePathValue(t *testing.T) {
+	items := map[string]string{"foo": "foo", "a/b": "a_b", `a\b`: "a_b", "c:": "c_", ".": "_", "..": "__", "...": "..."}
+	for s, want := range items {
+		if got := sanitizePathValue(s); got != want {
+			t.Fatalf("sanitizePathValue(): %q got %q, want %q", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSamplerTick is the default sampling window of the log sampler.
+	DefaultSamplerTick = time.Second
+
+	// SampledField is the field added to the sampled logs that survived the suppression.
+	SampledField = "sampled"
+
+	// SuppressedField is the field of the number of the identical logs suppressed by the
+	// log sampler since the previous surviving log.
+	SuppressedField = "suppressed"
+
+	// The maximum number of the distinct logs tracked by the log sampler.
+	maxSamplerKeys = 4096
+)
+
+// SamplerConfig defines the config of the log sampler, which throttles the repeated logs
+// (the logs with the same level and message) within a sampling window.
+// In each window, the first Initial repeated logs are recorded, and thereafter one of every
+// Thereafter repeated logs is recorded, the others are dropped. If Thereafter is less than
+// or equal to 0, all logs after the first Initial logs are dropped in the window.
+// The surviving logs following the dropped logs carry the SampledField (true) and the
+// SuppressedField (the number of the dropped logs) fields, so the downstream consumers can
+// reconstruct the true rates. The FatalLevel and PanicLevel logs are never sampled.
+type SamplerConfig struct {
+	// Initial is the number of the repeated logs always recorded in each window.
+	Initial int
+
+	// Thereafter is the sampling rate of the repeated logs after the first Initial logs.
+	Thereafter int
+
+	// Tick is the sampling window, DefaultSamplerTick is used if it is less than or equal to 0.
+	Tick time.Duration
+
+	// Summary determines whether to record a summary log when a window with the dropped logs
+	// ends, the summary log is recorded at the WarnLevel with the message "dropped N logs",
+	// and it carries the "sampled_level" and "sampled_message" fields. The summary logs are
+	// recorded directly, they are never sampled, and the pending summary logs are recorded
+	// when the logger is closed or the sampler is replaced.
+	Summary bool
+}
+
+// The logSampler type is the built-in log sampler.
+type logSampler struct {
+	mu         sync.Mutex
+	initial    int
+	thereafter int
+	tick       time.Duration
+	summary    bool
+	states     map[samplerKey]*samplerState
+}
+
+// The samplerKey type is the key of the repeated logs.
+type samplerKey struct {
+	level   Level
+	message string
+}
+
+// The samplerState type is the state of the repeated logs.
+type samplerState struct {
+	start      time.Time
+	count      int
+	dropped    uint64 // The number of the dropped logs in the current window.
+	suppressed uint64 // The number of the dropped logs since the previous surviving log.
+	gen        uint64
+	timer      *time.Timer // Records the summary log when the current window ends.
+	log        *log        // The log of the last dropped log, which records the summary log.
+}
+
+// The samplerSummary type is the summary of the logs dropped in a window.
+type samplerSummary struct {
+	log     *log
+	level   Level
+	message string
+	dropped uint64
+}
+
+// Creates a new log sampler with the given config.
+func newLogSampler(c SamplerConfig) *logSampler {
+	s := &logSampler{initial: c.Initial, thereafter: c.Thereafter, tick: c.Tick, summary: c.Summary}
+	if s.initial < 0 {
+		s.initial = 0
+	}
+	if s.tick <= 0 {
+		s.tick = DefaultSamplerTick
+	}
+	return s
+}
+
+// Determines whether the log of the given level and message should be recorded at the given
+// time, and returns the number of the logs suppressed since the previous surviving log, and
+// the summary of the ended window (only if the summary is enabled), which must be recorded
+// before the given log.
+func (s *logSampler) sample(l *log, level Level, message string, now time.Time) (ok bool, suppressed uint64, summary *samplerSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := samplerKey{level: level, message: message}
+	state, found := s.states[key]
+	if !found {
+		if s.states == nil || len(s.states) >= maxSamplerKeys {
+			// The timers of the discarded states still record their summary logs.
+			s.states = make(map[samplerKey]*samplerState)
+		}
+		state = &samplerState{start: now}
+		s.states[key] = state
+	} else if now.Sub(state.start) >= s.tick {
+		summary = s.take(key, state)
+		state.start, state.count = now, 0
+	}
+	state.count++
+	if state.count <= s.initial || (s.thereafter > 0 && (state.count-s.initial)%s.thereafter == 0) {
+		suppressed, state.suppressed = state.suppressed, 0
+		return true, suppressed, summary
+	}
+	state.suppressed++
+	if s.summary {
+		if state.dropped == 0 {
+			gen := state.gen
+			state.timer = time.AfterFunc(s.tick-now.Sub(state.start), func() { s.expire(key, state, gen) })
+		}
+		state.dropped++
+		state.log = l
+	}
+	return false, 0, summary
+}
+
+// Ends the window of the given state and returns the summary of it.
+// This method must be called with the lock held.
+func (s *logSampler) take(key samplerKey, state *samplerState) *samplerSummary {
+	if state.dropped == 0 {
+		return nil
+	}
+	p := &samplerSummary{log: state.log, level: key.level, message: key.message, dropped: state.dropped}
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+	state.dropped, state.log = 0, nil
+	state.gen++
+	return p
+}
+
+// Records the summary of the window of the given generation when the window ends.
+func (s *logSampler) expire(key samplerKey, state *samplerState, gen uint64) {
+	s.mu.Lock()
+	var p *samplerSummary
+	if state.gen == gen {
+		p = s.take(key, state)
+	}
+	s.mu.Unlock()
+	p.record()
+}
+
+// Ends the windows of all the repeated logs and records the summaries of them.
+func (s *logSampler) flush() {
+	s.mu.Lock()
+	var summaries []*samplerSummary
+	for key, state := range s.states {
+		if p := s.take(key, state); p != nil {
+			summaries = append(summaries, p)
+		}
+	}
+	s.mu.Unlock()
+	for i, j := 0, len(summaries); i < j; i++ {
+		summaries[i].record()
+	}
+}
+
+// Records the summary log at the WarnLevel directly, without the sampler.
+func (p *samplerSummary) record() {
+	if p == nil || p.log == nil || !p.log.isEnabled(WarnLevel) {
+		return
+	}
+	l := p.log.withFields(map[string]interface{}{
+		"sampled_level": p.level.String(), "sampled_message": p.message,
+	})
+	message := fmt.Sprintf("dropped %d logs", p.dropped)
+	entity := l.core.getEntity(l, WarnLevel, l.prefix+message, "")
+	defer l.core.putEntity(entity)
+
+	_ = l.emit(entity, message, false)
+}
@@ -0,0 +1,213 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogSampler(t *testing.T) {
+	s := newLogSampler(SamplerConfig{Initial: 2, Thereafter: 3})
+	if s.tick != DefaultSamplerTick {
+		t.Fatalf("newLogSampler(): tick %s", s.tick)
+	}
+	now := time.Now()
+	var got []bool
+	var suppressed []uint64
+	for i := 0; i < 8; i++ {
+		ok, n, summary := s.sample(nil, InfoLevel, "test", now)
+		if summary != nil {
+			t.Fatalf("logSampler.sample(): summary %+v", summary)
+		}
+		got = append(got, ok)
+		suppressed = append(suppressed, n)
+	}
+	want := []bool{true, true, false, false, true, false, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("logSampler.sample(): %v", got)
+		}
+	}
+	if suppressed[4] != 2 || suppressed[7] != 2 || suppressed[1] != 0 {
+		t.Fatalf("logSampler.sample(): %v", suppressed)
+	}
+
+	// The other messages and levels are sampled separately.
+	if ok, _, _ := s.sample(nil, InfoLevel, "other", now); !ok {
+		t.Fatal("logSampler.sample(): false")
+	}
+	if ok, _, _ := s.sample(nil, WarnLevel, "test", now); !ok {
+		t.Fatal("logSampler.sample(): false")
+	}
+
+	// The new window.
+	if ok, n, summary := s.sample(nil, InfoLevel, "test", now.Add(time.Second)); !ok || n != 0 || summary != nil {
+		t.Fatalf("logSampler.sample(): %v %d %+v", ok, n, summary)
+	}
+}
+
+func TestLogSampler_Summary(t *testing.T) {
+	s := newLogSampler(SamplerConfig{Initial: -1, Tick: time.Minute, Summary: true})
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := s.sample(nil, InfoLevel, "test", now); ok {
+			t.Fatal("logSampler.sample(): true")
+		}
+	}
+	ok, _, summary := s.sample(nil, InfoLevel, "test", now.Add(time.Minute))
+	if ok || summary == nil || summary.dropped != 3 || summary.level != InfoLevel || summary.message != "test" {
+		t.Fatalf("logSampler.sample(): %v %+v", ok, summary)
+	}
+	s.flush()
+	if s.states[samplerKey{level: InfoLevel, message: "test"}].dropped != 0 {
+		t.Fatal("logSampler.flush(): dropped logs not taken")
+	}
+}
+
+func TestLogger_SetSampler(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	now := time.Now()
+	o.SetNowFunc(func() time.Time { return now })
+
+	var messages []string
+	var fields []map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		messages = append(messages, s.Message())
+		fields = append(fields, s.Fields())
+		return nil
+	})
+
+	if o.SetSampler(SamplerConfig{Initial: 1, Thereafter: 2, Summary: true}) == nil {
+		t.Fatal("Logger.SetSampler(): nil")
+	}
+	for i := 0; i < 4; i++ {
+		o.Error("test")
+	}
+	if len(messages) != 2 || len(fields[0]) != 0 {
+		t.Fatalf("Logger.SetSampler(): %v %v", messages, fields)
+	}
+	if fields[1][SampledField] != true || fields[1][SuppressedField] != uint64(1) {
+		t.Fatalf("Logger.SetSampler(): %v", fields[1])
+	}
+
+	now = now.Add(time.Second)
+	o.Error("test")
+	if len(messages) != 4 || messages[2] != "dropped 2 logs" || messages[3] != "test" {
+		t.Fatalf("Logger.SetSampler(): %v", messages)
+	}
+	if fields[2]["sampled_level"] != "error" || fields[2]["sampled_message"] != "test" {
+		t.Fatalf("Logger.SetSampler(): %v", fields)
+	}
+	// The suppressed count is the number of the logs dropped since the previous surviving log.
+	if fields[3][SuppressedField] != uint64(1) {
+		t.Fatalf("Logger.SetSampler(): %v", fields)
+	}
+
+	// The FatalLevel and PanicLevel logs are never sampled.
+	o.SetExitFunc(nil)
+	o.SetSampler(SamplerConfig{Thereafter: 100})
+	o.Fatal("test")
+	o.Fatal("test")
+	if len(messages) != 6 {
+		t.Fatalf("Logger.SetSampler(): %v", messages)
+	}
+
+	o.SetSampler(SamplerConfig{})
+	messages = nil
+	for i := 0; i < 3; i++ {
+		o.Error("test")
+	}
+	if len(messages) != 3 {
+		t.Fatalf("Logger.SetSampler(): %v", messages)
+	}
+}
+
+func TestLogger_SetSampler_SummaryDisabled(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	o.SetLevel(ErrorLevel)
+	now := time.Now()
+	o.SetNowFunc(func() time.Time { return now })
+
+	var messages []string
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		messages = append(messages, s.Message())
+		return nil
+	})
+
+	o.SetSampler(SamplerConfig{Initial: 1, Summary: true})
+	o.Error("test")
+	o.Error("test")
+	now = now.Add(time.Second)
+	o.Error("test")
+	// The summary log of the WarnLevel is not recorded.
+	if len(messages) != 2 || messages[0] != "test" || messages[1] != "test" {
+		t.Fatalf("Logger.SetSampler(): %v", messages)
+	}
+}
+
+func TestLogger_SetSampler_SummaryExpired(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	ch := make(chan []string, 10)
+	o.AddHookFunc([]Level{WarnLevel}, func(s Summary) error {
+		ch <- []string{s.Message(), s.Fields()["sampled_message"].(string)}
+		return nil
+	})
+
+	o.SetSampler(SamplerConfig{Initial: 1, Tick: 20 * time.Millisecond, Summary: true})
+	for i := 0; i < 4; i++ {
+		o.Error("test")
+	}
+	// The summary log is recorded when the window ends, even if the log is not repeated.
+	select {
+	case got := <-ch:
+		if got[0] != "dropped 3 logs" || got[1] != "test" {
+			t.Fatalf("Logger.SetSampler(): %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Logger.SetSampler(): summary not recorded")
+	}
+	// The summary logs are not sampled.
+	if n := len(o.(*logger).core.sampler.states); n != 1 {
+		t.Fatalf("Logger.SetSampler(): %d keys", n)
+	}
+}
+
+func TestLogger_SetSampler_SummaryClose(t *testing.T) {
+	o := New("test")
+	o.SetOutput(new(testSyncBuffer))
+	var messages []string
+	o.AddHookFunc([]Level{WarnLevel}, func(s Summary) error {
+		messages = append(messages, s.Message())
+		return nil
+	})
+
+	o.SetSampler(SamplerConfig{Initial: 1, Tick: time.Hour, Summary: true})
+	o.Error("test")
+	o.Error("test")
+	o.Error("test")
+	if len(messages) != 0 {
+		t.Fatalf("Logger.SetSampler(): %v", messages)
+	}
+	if err := o.Close(); err != nil {
+		t.Fatalf("Logger.Close(): %s", err)
+	}
+	if len(messages) != 1 || messages[0] != "dropped 2 logs" {
+		t.Fatalf("Logger.Close(): %v", messages)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SchemaField defines a field contract of the structured event schema.
+type SchemaField struct {
+	// Name is the name of the log field.
+	Name string
+
+	// Kind is the expected kind of the field value, such as reflect.String.
+	// If it is reflect.Invalid, the field value can be of any type.
+	Kind reflect.Kind
+
+	// Optional indicates whether the field can be omitted.
+	Optional bool
+}
+
+// SchemaRegistryOptions defines the options of the schema registry.
+type SchemaRegistryOptions struct {
+	// KeyField is the name of the log field that stores the event key.
+	// If it is empty, the log message is used as the event key.
+	KeyField string
+
+	// Levels is the log levels validated by the schema registry.
+	// If it is empty, all log levels are validated.
+	Levels []Level
+}
+
+// SchemaRegistry interface defines the registry of the structured event schemas, which
+// validates the required fields and their types of the logs before they are written.
+// The schema registry is a log hook, the schema violations are reported by the internal
+// error handler and do not prevent the log from being written:
+//
+//	r := NewSchemaRegistry(SchemaRegistryOptions{})
+//	r.Register("user login", SchemaField{Name: "uid", Kind: reflect.Int})
+//	l.AddHook(r)
+type SchemaRegistry interface {
+	Hook
+
+	// Register registers the fields of the schema for the given event key.
+	// The previously registered schema of the event key is replaced.
+	Register(key string, fields ...SchemaField)
+
+	// Validate validates the given log summary by the schema of its event key.
+	// The log whose event key is not registered is always valid.
+	Validate(Summary) error
+}
+
+// NewSchemaRegistry creates and returns a new schema registry.
+func NewSchemaRegistry(opts SchemaRegistryOptions) SchemaRegistry {
+	if len(opts.Levels) == 0 {
+		opts.Levels = GetAllLevels()
+	}
+	return &schemaRegistry{opts: opts, schemas: make(map[string][]SchemaField)}
+}
+
+// The built-in schema registry.
+type schemaRegistry struct {
+	mu      sync.RWMutex
+	opts    SchemaRegistryOptions
+	schemas map[string][]SchemaField
+}
+
+// Levels returns the log levels associated with the current log hook.
+func (r *schemaRegistry) Levels() []Level {
+	return r.opts.Levels
+}
+
+// Fire receives the summary of the log and validates it.
+func (r *schemaRegistry) Fire(s Summary) error {
+	return r.Validate(s)
+}
+
+// Register registers the fields of the schema for the given event key.
+// The previously registered schema of the event key is replaced.
+func (r *schemaRegistry) Register(key string, fields ...SchemaField) {
+	r.mu.Lock()
+	r.schemas[key] = append([]SchemaField(nil), fields...)
+	r.mu.Unlock()
+}
+
+// Validate validates the given log summary by the schema of its event key.
+// The log whose event key is not registered is always valid.
+func (r *schemaRegistry) Validate(s Summary) error {
+	key := s.Message()
+	if r.opts.KeyField != "" {
+		v, found := s.Fields()[r.opts.KeyField]
+		if !found {
+			return nil
+		}
+		key = fmt.Sprint(v)
+	}
+
+	r.mu.RLock()
+	fields, found := r.schemas[key]
+	r.mu.RUnlock()
+	if !found {
+		return nil
+	}
+
+	values := s.Fields()
+	var violations []string
+	for i, j := 0, len(fields); i < j; i++ {
+		v, found := values[fields[i].Name]
+		if !found {
+			if !fields[i].Optional {
+				violations = append(violations, fmt.Sprintf("missing field %q", fields[i].Name))
+			}
+			continue
+		}
+		if fields[i].Kind != reflect.Invalid {
+			if kind := reflect.ValueOf(v).Kind(); kind != fields[i].Kind {
+				violations = append(violations, fmt.Sprintf(
+					"field %q must be %s, got %s", fields[i].Name, fields[i].Kind, kind,
+				))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("event %q violates schema: %s", key, strings.Join(violations, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+func TestNewSchemaRegistry(t *testing.T) {
+	r := NewSchemaRegistry(SchemaRegistryOptions{})
+	if r == nil {
+		t.Fatal("NewSchemaRegistry(): nil")
+	}
+	if got := len(r.Levels()); got != len(GetAllLevels()) {
+		t.Fatalf("SchemaRegistry.Levels(): %d", got)
+	}
+
+	r = NewSchemaRegistry(SchemaRegistryOptions{Levels: []Level{ErrorLevel}})
+	if got := r.Levels(); len(got) != 1 || got[0] != ErrorLevel {
+		t.Fatalf("SchemaRegistry.Levels(): %v", got)
+	}
+}
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	r := NewSchemaRegistry(SchemaRegistryOptions{})
+	r.Register("login",
+		SchemaField{Name: "uid", Kind: reflect.Int},
+		SchemaField{Name: "ip"},
+		SchemaField{Name: "agent", Kind: reflect.String, Optional: true},
+	)
+
+	items := []struct {
+		Message string
+		Fields  map[string]interface{}
+		Want    string
+	}{
+		{"logout", nil, ""},
+		{"login", map[string]interface{}{"uid": 1, "ip": "127.0.0.1"}, ""},
+		{"login", map[string]interface{}{"uid": 1, "ip": 1, "agent": "test"}, ""},
+		{"login", map[string]interface{}{"uid": 1}, `event "login" violates schema: missing field "ip"`},
+		{
+			"login", map[string]interface{}{"uid": "1", "ip": "", "agent": 1},
+			`event "login" violates schema: field "uid" must be int, got string; field "agent" must be string, got int`,
+		},
+	}
+
+	for _, item := range items {
+		err := r.Validate(&logEntity{message: item.Message, fields: item.Fields})
+		if item.Want == "" {
+			if err != nil {
+				t.Fatalf("SchemaRegistry.Validate(): error %s", err)
+			}
+		} else {
+			if err == nil || err.Error() != item.Want {
+				t.Fatalf("SchemaRegistry.Validate(): want %q, got %v", item.Want, err)
+			}
+		}
+	}
+}
+
+func TestSchemaRegistry_Validate_WithKeyField(t *testing.T) {
+	r := NewSchemaRegistry(SchemaRegistryOptions{KeyField: "event"})
+	r.Register("login", SchemaField{Name: "uid"})
+
+	if err := r.Validate(&logEntity{message: "login"}); err != nil {
+		t.Fatalf("SchemaRegistry.Validate(): error %s", err)
+	}
+	if err := r.Validate(&logEntity{fields: map[string]interface{}{"event": "login"}}); err == nil {
+		t.Fatal("SchemaRegistry.Validate(): nil error")
+	}
+	if err := r.Fire(&logEntity{fields: map[string]interface{}{"event": "login", "uid": 1}}); err != nil {
+		t.Fatalf("SchemaRegistry.Fire(): error %s", err)
+	}
+}
+
+func TestSchemaRegistry_Hook(t *testing.T) {
+	buf := new(bytes.Buffer)
+	internal.ErrorWriter = buf
+	defer func() { internal.ErrorWriter = os.Stderr }()
+
+	r := NewSchemaRegistry(SchemaRegistryOptions{})
+	r.Register("test", SchemaField{Name: "foo"})
+
+	l := New("test")
+	out := new(bytes.Buffer)
+	l.SetOutput(out)
+	l.AddHook(r)
+
+	l.Info("test")
+	if !strings.Contains(buf.String(), `missing field "foo"`) {
+		t.Fatalf("SchemaRegistry.Fire(): %q", buf.String())
+	}
+	if out.Len() == 0 {
+		t.Fatal("SchemaRegistry.Fire(): log not written")
+	}
+
+	buf.Reset()
+	l.WithField("foo", 1).Info("test")
+	if buf.Len() != 0 {
+		t.Fatalf("SchemaRegistry.Fire(): %q", buf.String())
+	}
+}
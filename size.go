@@ -0,0 +1,73 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The size units supported by ParseSize, all units are binary multiples.
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1 << 40,
+	"tib": 1 << 40,
+}
+
+// ParseSize parses the number of bytes from the given human-readable size string,
+// such as "4096", "512MB", "1.5G" and "2GiB". The units are case-insensitive and
+// are binary multiples, so "1KB" is 1024 bytes.
+func ParseSize(s string) (int64, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+	i := strings.IndexFunc(t, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(t)
+	}
+	unit, found := sizeUnits[strings.TrimSpace(t[i:])]
+	if !found || i == 0 {
+		return 0, fmt.Errorf("invalid size string %q", s)
+	}
+	n, err := strconv.ParseFloat(t[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size string %q", s)
+	}
+	if n *= float64(unit); n >= math.MaxInt64 {
+		return 0, fmt.Errorf("size string %q overflows", s)
+	}
+	return int64(n), nil
+}
+
+// MustParseSize parses the number of bytes from the given human-readable size string.
+// If the given string is invalid, it will panic.
+func MustParseSize(s string) int64 {
+	n, err := ParseSize(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
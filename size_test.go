@@ -0,0 +1,63 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	items := map[string]int64{
+		"0":       0,
+		"4096":    4096,
+		"10B":     10,
+		"1k":      1 << 10,
+		"1KB":     1 << 10,
+		"2KiB":    2 << 10,
+		"512MB":   512 << 20,
+		" 512 mb": 512 << 20,
+		"1.5G":    3 << 29,
+		"2GB":     2 << 30,
+		"4TiB":    4 << 40,
+	}
+	for s, want := range items {
+		got, err := ParseSize(s)
+		if err != nil {
+			t.Fatalf("ParseSize(): %q error: %s", s, err)
+		}
+		if got != want {
+			t.Fatalf("ParseSize(): %q got %d, want %d", s, got, want)
+		}
+	}
+
+	for _, s := range []string{"", "MB", "1.2.3MB", "10PB", "-1MB", "1 M B", "99999999999TB"} {
+		if _, err := ParseSize(s); err == nil {
+			t.Fatalf("ParseSize(): %q nil error", s)
+		}
+	}
+}
+
+func TestMustParseSize(t *testing.T) {
+	if got := MustParseSize("2GB"); got != 2<<30 {
+		t.Fatalf("MustParseSize(): %d", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustParseSize(): no panic")
+		}
+	}()
+	MustParseSize("invalid")
+}
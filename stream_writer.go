@@ -0,0 +1,102 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// This writer buffers the log data until its size exceeds the threshold, and then
+// writes the buffered data and all subsequent data to the log writer in chunks.
+// The log writer is locked from the first chunk until the writer is released, so the
+// chunks of the concurrent logs written to the same log writer are not interleaved.
+type streamWriter struct {
+	buffer    *bytes.Buffer
+	writer    io.Writer
+	threshold int
+	streamed  bool
+	err       error
+	locks     *sync.Map   // The stream locks of the log writers.
+	locker    sync.Locker // The lock of the log writer held by the streamed log.
+}
+
+// Write is the implementation of io.Writer interface.
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if !w.streamed {
+		if w.buffer.Len()+len(p) <= w.threshold {
+			return w.buffer.Write(p)
+		}
+		w.streamed = true
+		w.lock()
+		if w.buffer.Len() > 0 {
+			if w.err = w.writeChunks(w.buffer.Bytes()); w.err != nil {
+				return 0, w.err
+			}
+			w.buffer.Reset()
+		}
+	}
+	if w.err = w.writeChunks(p); w.err != nil {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+// Writes the given data to the log writer in chunks no larger than the threshold.
+func (w *streamWriter) writeChunks(p []byte) error {
+	for len(p) > 0 {
+		n := len(p)
+		if n > w.threshold {
+			n = w.threshold
+		}
+		k, err := w.writer.Write(p[:n])
+		if err == nil && k != n {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return err
+		}
+		p = p[n:]
+	}
+	return nil
+}
+
+// Locks the log writer until the streamed log is released. The mutex writer is locked by
+// its own locker, so that the other logs written to it are not interleaved with the chunks,
+// and the other writers are locked by the given stream locks.
+func (w *streamWriter) lock() {
+	if m, ok := w.writer.(*mutexWriter); ok {
+		w.writer, w.locker = m.w, m.mu
+	} else if w.locks != nil && w.writer != nil && reflect.TypeOf(w.writer).Comparable() {
+		v, _ := w.locks.LoadOrStore(w.writer, new(sync.Mutex))
+		w.locker = v.(sync.Locker)
+	}
+	if w.locker != nil {
+		w.locker.Lock()
+	}
+}
+
+// Releases the lock of the log writer held by the streamed log.
+func (w *streamWriter) release() {
+	if w.locker != nil {
+		w.locker.Unlock()
+		w.locker = nil
+	}
+}
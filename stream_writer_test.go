@@ -0,0 +1,149 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testChunkWriter struct {
+	chunks []string
+	err    error
+}
+
+func (w *testChunkWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.chunks = append(w.chunks, string(p))
+	return len(p), nil
+}
+
+func TestStreamWriter_Write(t *testing.T) {
+	b := new(bytes.Buffer)
+	cw := new(testChunkWriter)
+	w := &streamWriter{buffer: b, writer: cw, threshold: 4}
+
+	if n, err := w.Write([]byte("foo")); err != nil || n != 3 {
+		t.Fatalf("streamWriter.Write(): %d %v", n, err)
+	}
+	if w.streamed || b.String() != "foo" || len(cw.chunks) != 0 {
+		t.Fatalf("streamWriter.Write(): %v %q %v", w.streamed, b.String(), cw.chunks)
+	}
+	if n, err := w.Write([]byte("barbaz")); err != nil || n != 6 {
+		t.Fatalf("streamWriter.Write(): %d %v", n, err)
+	}
+	if !w.streamed || b.Len() != 0 {
+		t.Fatalf("streamWriter.Write(): %v %q", w.streamed, b.String())
+	}
+	if got := strings.Join(cw.chunks, "|"); got != "foo|barb|az" {
+		t.Fatalf("streamWriter.Write(): %s", got)
+	}
+}
+
+func TestStreamWriter_WriteError(t *testing.T) {
+	cw := &testChunkWriter{err: errors.New("test")}
+	w := &streamWriter{buffer: new(bytes.Buffer), writer: cw, threshold: 2}
+
+	if _, err := w.Write([]byte("foo")); err == nil {
+		t.Fatal("streamWriter.Write(): nil error")
+	}
+	if _, err := w.Write([]byte("f")); err == nil {
+		t.Fatal("streamWriter.Write(): nil error")
+	}
+}
+
+func TestLogger_SetStreamThreshold(t *testing.T) {
+	cw := new(testChunkWriter)
+	o := New("test").SetOutput(cw).SetFormatter(MustNewTextFormatter("{message}", false))
+
+	if o.SetStreamThreshold(8) == nil {
+		t.Fatal("Logger.SetStreamThreshold(): return nil.")
+	}
+
+	var size int
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		size = s.Size()
+		return nil
+	})
+
+	o.Info("foo")
+	if size != 4 || len(cw.chunks) != 1 || cw.chunks[0] != "foo\n" {
+		t.Fatalf("Logger.SetStreamThreshold(): %d %q", size, cw.chunks)
+	}
+
+	cw.chunks = nil
+	o.Info("foobarbaz")
+	if size != 0 || strings.Join(cw.chunks, "|") != "foobarba|z|\n" {
+		t.Fatalf("Logger.SetStreamThreshold(): %d %q", size, cw.chunks)
+	}
+
+	cw.chunks = nil
+	o.SetStreamThreshold(0)
+	o.Info("foobarbaz")
+	if size != 10 || len(cw.chunks) != 1 {
+		t.Fatalf("Logger.SetStreamThreshold(0): %d %q", size, cw.chunks)
+	}
+}
+
+type testYieldWriter struct {
+	testSyncBuffer
+}
+
+func (w *testYieldWriter) Write(p []byte) (int, error) {
+	n, err := w.testSyncBuffer.Write(p)
+	// Gives the other goroutines a chance to write between the chunks.
+	runtime.Gosched()
+	return n, err
+}
+
+func TestLogger_SetStreamThreshold_Concurrent(t *testing.T) {
+	for _, mutex := range []bool{false, true} {
+		b := new(testYieldWriter)
+		o := New("test").SetFormatter(MustNewTextFormatter("{message}", false)).SetStreamThreshold(4)
+		if mutex {
+			o.SetOutput(NewMutexWriter(b))
+		} else {
+			o.SetOutput(b)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(c string) {
+				defer wg.Done()
+				for k := 0; k < 50; k++ {
+					o.Info(strings.Repeat(c, 64))
+				}
+			}(string(rune('a' + i)))
+		}
+		wg.Wait()
+
+		lines := strings.Split(strings.TrimSuffix(string(b.Bytes()), "\n"), "\n")
+		if len(lines) != 400 {
+			t.Fatalf("Logger.SetStreamThreshold(): %d lines", len(lines))
+		}
+		for _, line := range lines {
+			if len(line) != 64 || strings.Count(line, line[:1]) != 64 {
+				t.Fatalf("Logger.SetStreamThreshold(): interleaved log %q", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// The stable serialization structure of the log summary.
+type summaryObject struct {
+	Name       string                 `json:"name,omitempty"`
+	Time       time.Time              `json:"time"`
+	TimeFormat string                 `json:"time_format,omitempty"`
+	Level      string                 `json:"level"`
+	Message    string                 `json:"message"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Caller     string                 `json:"caller,omitempty"`
+	Stack      []string               `json:"stack,omitempty"`
+	PID        int                    `json:"pid,omitempty"`
+	Hostname   string                 `json:"hostname,omitempty"`
+	Sequence   uint64                 `json:"sequence,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Content    []byte                 `json:"content,omitempty"`
+}
+
+// MarshalSummary serializes the given log summary into json, so that the log hooks can
+// forward the logs across process boundaries (such as sidecars and plugins).
+// The context of the log summary is not serialized, and the log fields are serialized by
+// the json encoder, so the field values are restored as the json types by UnmarshalSummary.
+func MarshalSummary(s Summary) ([]byte, error) {
+	return json.Marshal(newSummaryObject(s))
+}
+
+// Creates the serialization structure of the given log summary.
+func newSummaryObject(s Summary) summaryObject {
+	o := summaryObject{
+		Name: s.Name(), Time: s.Time(), Level: s.Level().String(), Message: s.Message(),
+		Caller: s.Caller(), Stack: s.Stack(), PID: s.PID(), Hostname: s.Hostname(),
+		Sequence: s.Sequence(), ID: s.ID(), Content: s.Bytes(),
+	}
+	if e, ok := s.(*logEntity); ok {
+		o.TimeFormat = e.timeFormat
+	}
+	if s.HasFields() {
+		o.Fields = internal.StandardiseFieldsForJSONEncoder(s.Fields())
+	}
+	return o
+}
+
+// UnmarshalSummary deserializes the log summary serialized by MarshalSummary.
+func UnmarshalSummary(data []byte) (Summary, error) {
+	var o summaryObject
+	d := json.NewDecoder(bytes.NewReader(data))
+	// Keep the original precision of the numeric fields.
+	d.UseNumber()
+	if err := d.Decode(&o); err != nil {
+		return nil, err
+	}
+	level, err := ParseLevel(o.Level)
+	if err != nil {
+		return nil, err
+	}
+	e := &logEntity{
+		name: o.Name, time: o.Time, timeFormat: o.TimeFormat, level: level, message: o.Message,
+		fields: o.Fields, caller: o.Caller, stack: o.Stack, pid: o.PID, hostname: o.Hostname,
+		sequence: o.Sequence, id: o.ID,
+	}
+	e.buffer.Write(o.Content)
+	return e, nil
+}
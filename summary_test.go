@@ -0,0 +1,114 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMarshalSummary(t *testing.T) {
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	e := &logEntity{
+		name: "test", time: tm, timeFormat: time.RFC3339, level: WarnLevel, message: "foo",
+		fields: map[string]interface{}{"a": 1, "b": errors.New("bar")}, caller: "test.go:1",
+		stack: []string{"a", "b"}, pid: 2, hostname: "host", sequence: 3, id: "id",
+	}
+	e.buffer.WriteString("content")
+
+	data, err := MarshalSummary(e)
+	if err != nil {
+		t.Fatalf("MarshalSummary(): error %s", err)
+	}
+	s, err := UnmarshalSummary(data)
+	if err != nil {
+		t.Fatalf("UnmarshalSummary(): error %s", err)
+	}
+
+	if s.Name() != "test" || !s.Time().Equal(tm) || s.TimeString() != "2023-01-02T03:04:05Z" ||
+		s.Level() != WarnLevel || s.Message() != "foo" || s.Caller() != "test.go:1" ||
+		len(s.Stack()) != 2 || s.PID() != 2 || s.Hostname() != "host" || s.Sequence() != 3 ||
+		s.ID() != "id" || s.String() != "content" {
+		t.Fatalf("UnmarshalSummary(): %+v", s)
+	}
+	if got := s.Fields()["a"]; got != json.Number("1") {
+		t.Fatalf("UnmarshalSummary(): field a %v", got)
+	}
+	if got := s.Fields()["b"]; got != "bar" {
+		t.Fatalf("UnmarshalSummary(): field b %v", got)
+	}
+}
+
+func TestUnmarshalSummary_WithError(t *testing.T) {
+	for _, data := range []string{"", "{", `{"level":"test"}`} {
+		if _, err := UnmarshalSummary([]byte(data)); err == nil {
+			t.Fatalf("UnmarshalSummary(%q): nil error", data)
+		}
+	}
+}
+
+func TestLogger_Replay(t *testing.T) {
+	var summaries []Summary
+	src := New("src")
+	src.SetOutput(new(bytes.Buffer))
+	src.SetExitFunc(nil)
+	src.AddHook(NewHookFromFunc(GetAllLevels(), func(s Summary) error {
+		summaries = append(summaries, s.Clone())
+		return nil
+	}))
+	src.SetNowFunc(func() time.Time { return time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC) })
+	src.EnableProcessMetadata(true)
+
+	src.WithField("a", 1).Info("foo")
+	src.Debug("debug")
+	src.Fatal("fatal")
+
+	dst := New("dst")
+	buf := new(bytes.Buffer)
+	dst.SetOutput(buf)
+	dst.SetLevel(InfoLevel)
+	dst.SetDefaultTimeFormat(time.RFC3339)
+	dst.SetExitFunc(func(int) { t.Fatal("Logger.Replay(): exit function called") })
+
+	for _, s := range summaries {
+		data, err := MarshalSummary(s)
+		if err != nil {
+			t.Fatalf("MarshalSummary(): error %s", err)
+		}
+		if s, err = UnmarshalSummary(data); err != nil {
+			t.Fatalf("UnmarshalSummary(): error %s", err)
+		}
+		if err = dst.Replay(s); err != nil {
+			t.Fatalf("Logger.Replay(): error %s", err)
+		}
+	}
+
+	want := `{"fields":{"a":1},"level":"info","message":"foo","name":"dst","time":"2023-01-02T03:04:05Z"}` + "\n" +
+		`{"level":"fatal","message":"fatal","name":"dst","time":"2023-01-02T03:04:05Z"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("Logger.Replay(): want %q, got %q", want, got)
+	}
+
+	if err := dst.Replay(&logEntity{}); err != nil {
+		t.Fatalf("Logger.Replay(): error %s", err)
+	}
+	dst.SetOutput(testErrorWriter("test"))
+	if err := dst.Replay(summaries[0]); err == nil {
+		t.Fatal("Logger.Replay(): nil error")
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFacility defines the syslog facility of the logs.
+type SyslogFacility int
+
+// The syslog facilities defined by RFC 5424.
+const (
+	SyslogKern SyslogFacility = iota
+	SyslogUser
+	SyslogMail
+	SyslogDaemon
+	SyslogAuth
+	SyslogSyslog
+	SyslogLPR
+	SyslogNews
+	SyslogUUCP
+	SyslogCron
+	SyslogAuthPriv
+	SyslogFTP
+	_
+	_
+	_
+	_
+	SyslogLocal0
+	SyslogLocal1
+	SyslogLocal2
+	SyslogLocal3
+	SyslogLocal4
+	SyslogLocal5
+	SyslogLocal6
+	SyslogLocal7
+)
+
+// SyslogProtocol defines the message format of the syslog protocol.
+type SyslogProtocol int
+
+const (
+	// SyslogRFC3164 indicates the BSD syslog message format, which is widely supported by
+	// the local syslog daemons: "<PRI>Jan  2 15:04:05 HOSTNAME TAG[PID]: MSG".
+	SyslogRFC3164 SyslogProtocol = iota
+
+	// SyslogRFC5424 indicates the IETF syslog message format:
+	// "<PRI>1 2006-01-02T15:04:05.000000Z07:00 HOSTNAME APP-NAME PROCID - - MSG".
+	SyslogRFC5424
+)
+
+// SyslogFormatterOptions defines the options of the syslog formatter.
+type SyslogFormatterOptions struct {
+	// Protocol is the message format of the syslog protocol, the default is SyslogRFC3164.
+	Protocol SyslogProtocol
+
+	// Facility is the syslog facility of the logs. Since the kernel facility can not be
+	// used by the user processes, the zero value SyslogKern means SyslogUser.
+	Facility SyslogFacility
+
+	// Tag is the tag (the APP-NAME of RFC 5424) of the logs, the default is the base name of
+	// the program.
+	Tag string
+
+	// Hostname is the host name of the logs, the default is the host name of the system.
+	Hostname string
+
+	// Formatter formats the message part of the syslog messages, the default formatter
+	// formats the log message and fields, such as "message key=value".
+	Formatter Formatter
+}
+
+// The default formatter of the message part of the syslog messages.
+var defaultSyslogMessageFormatter = MustNewTextFormatter("{message}{fields}", false)
+
+// NewSyslogFormatter creates and returns an instance of the syslog formatter, which formats
+// the logs into the syslog messages (RFC 3164 or RFC 5424), the log levels are mapped to the
+// syslog severities by the Level.Severity. It can be used with the writer returned by the
+// DialSyslog to send the logs to a local or remote syslog daemon.
+func NewSyslogFormatter(opts SyslogFormatterOptions) Formatter {
+	f := &syslogFormatter{
+		protocol: opts.Protocol, facility: opts.Facility, tag: opts.Tag, hostname: opts.Hostname,
+		formatter: opts.Formatter, pid: strconv.Itoa(os.Getpid()),
+	}
+	if f.facility == SyslogKern {
+		f.facility = SyslogUser
+	}
+	if f.tag == "" {
+		f.tag = filepath.Base(os.Args[0])
+	}
+	if f.hostname == "" {
+		if f.hostname, _ = os.Hostname(); f.hostname == "" {
+			f.hostname = "-"
+		}
+	}
+	if f.formatter == nil {
+		f.formatter = defaultSyslogMessageFormatter
+	}
+	return f
+}
+
+// The syslogFormatter type is the built-in syslog formatter.
+type syslogFormatter struct {
+	protocol  SyslogProtocol
+	facility  SyslogFacility
+	tag       string
+	hostname  string
+	formatter Formatter
+	pid       string
+}
+
+// Format formats the given log entity into character data and writes it to the given buffer.
+func (f *syslogFormatter) Format(e Entity, b *bytes.Buffer) error {
+	pri := int(f.facility)*8 + e.Level().Severity()
+	b.WriteString("<" + strconv.Itoa(pri) + ">")
+	if f.protocol == SyslogRFC5424 {
+		b.WriteString("1 " + e.Time().Format("2006-01-02T15:04:05.000000Z07:00") + " ")
+		b.WriteString(f.hostname + " " + f.tag + " " + f.pid + " - - ")
+	} else {
+		b.WriteString(e.Time().Format(time.Stamp) + " " + f.hostname + " " + f.tag + "[" + f.pid + "]: ")
+	}
+	return f.formatter.Format(e, b)
+}
+
+// The local syslog daemon sockets.
+var syslogLocalSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// DialSyslog connects to the syslog daemon of the given network and address, such as "udp"
+// and "127.0.0.1:514", and returns the writer of the syslog messages. If the given network
+// is empty, the local syslog daemon is connected by the unix socket of the given address,
+// or by the well-known unix sockets (such as "/dev/log") if the address is also empty.
+// If the writing fails, the writer reconnects to the syslog daemon and retries once.
+// The returned writer is safe for concurrent use.
+func DialSyslog(network, address string) (io.WriteCloser, error) {
+	w := &syslogWriter{network: network, address: address}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// The syslogWriter type is the built-in syslog writer.
+type syslogWriter struct {
+	mu      sync.Mutex
+	network string
+	address string
+	conn    net.Conn
+}
+
+// Write is the implementation of io.Writer interface.
+func (w *syslogWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if n, err = w.conn.Write(p); err == nil {
+			return
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	if err = w.connect(); err != nil {
+		return 0, err
+	}
+	return w.conn.Write(p)
+}
+
+// Close is the implementation of io.Closer interface.
+func (w *syslogWriter) Close() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+	return
+}
+
+// Connects to the syslog daemon.
+func (w *syslogWriter) connect() (err error) {
+	if w.network != "" {
+		w.conn, err = net.Dial(w.network, w.address)
+		return
+	}
+	sockets := syslogLocalSockets
+	if w.address != "" {
+		sockets = []string{w.address}
+	}
+	for _, socket := range sockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			if w.conn, err = net.Dial(network, socket); err == nil {
+				return nil
+			}
+		}
+	}
+	return errors.New("failed to connect to the local syslog daemon")
+}
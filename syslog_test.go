@@ -0,0 +1,126 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewSyslogFormatter(t *testing.T) {
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	e := &logEntity{level: ErrorLevel, message: "test", time: tm, fields: map[string]interface{}{"a": 1}}
+	pid := strconv.Itoa(os.Getpid())
+
+	items := []struct {
+		Options SyslogFormatterOptions
+		Want    string
+	}{
+		{
+			SyslogFormatterOptions{Tag: "app", Hostname: "host"},
+			"<11>Jan  2 03:04:05 host app[" + pid + "]: test a=1\n",
+		},
+		{
+			SyslogFormatterOptions{Protocol: SyslogRFC5424, Facility: SyslogLocal0, Tag: "app", Hostname: "host"},
+			"<131>1 2024-01-02T03:04:05.000006Z host app " + pid + " - - test a=1\n",
+		},
+		{
+			SyslogFormatterOptions{Tag: "app", Hostname: "host", Formatter: FormatterFunc(func(e Entity, b *bytes.Buffer) error {
+				b.WriteString(e.Message())
+				return nil
+			})},
+			"<11>Jan  2 03:04:05 host app[" + pid + "]: test",
+		},
+	}
+	for _, item := range items {
+		buf := new(bytes.Buffer)
+		if err := NewSyslogFormatter(item.Options).Format(e, buf); err != nil {
+			t.Fatalf("SyslogFormatter.Format(): %s", err)
+		}
+		if got := buf.String(); got != item.Want {
+			t.Fatalf("SyslogFormatter.Format(): want %q, got %q", item.Want, got)
+		}
+	}
+
+	f := NewSyslogFormatter(SyslogFormatterOptions{}).(*syslogFormatter)
+	if f.facility != SyslogUser || f.tag != filepath.Base(os.Args[0]) || f.hostname == "" {
+		t.Fatalf("NewSyslogFormatter(): %+v", f)
+	}
+}
+
+func TestDialSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	w, err := DialSyslog("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("DialSyslog(): %s", err)
+	}
+	o := New("test")
+	o.SetOutput(w)
+	o.SetFormatter(NewSyslogFormatter(SyslogFormatterOptions{Tag: "app", Hostname: "host"}))
+	o.Warn("test")
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("DialSyslog(): %s", err)
+	}
+	if got := string(buf[:n]); !bytes.HasPrefix(buf[:n], []byte("<12>")) || !bytes.HasSuffix(buf[:n], []byte("app["+strconv.Itoa(os.Getpid())+"]: test\n")) {
+		t.Fatalf("DialSyslog(): %q", got)
+	}
+
+	// The closed writer reconnects on the next write.
+	if err = w.Close(); err != nil {
+		t.Fatalf("SyslogWriter.Close(): %s", err)
+	}
+	if _, err = w.Write([]byte("test")); err != nil {
+		t.Fatalf("SyslogWriter.Write(): %s", err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("SyslogWriter.Close(): %s", err)
+	}
+}
+
+func TestDialSyslog_Local(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "log")
+	conn, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Skipf("unixgram: %s", err)
+	}
+	defer conn.Close()
+
+	w, err := DialSyslog("", socket)
+	if err != nil {
+		t.Fatalf("DialSyslog(): %s", err)
+	}
+	defer w.Close()
+	if _, err = w.Write([]byte("test")); err != nil {
+		t.Fatalf("SyslogWriter.Write(): %s", err)
+	}
+
+	if _, err = DialSyslog("", filepath.Join(t.TempDir(), "none")); err == nil {
+		t.Fatal("DialSyslog(): nil error")
+	}
+}
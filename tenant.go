@@ -0,0 +1,208 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"container/list"
+	"io"
+	"sync"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// DefaultTenantField is the default log field name of the tenant id.
+const DefaultTenantField = "tenant"
+
+// DefaultMaxTenants is the default maximum number of tenant loggers kept by the tenant loggers.
+const DefaultMaxTenants = 256
+
+// TenantLoggersOptions defines the options of the tenant loggers.
+type TenantLoggersOptions struct {
+	// Field is the log field name of the tenant id, by default, DefaultTenantField is used.
+	Field string
+
+	// MaxTenants is the maximum number of tenant loggers kept, the least recently used tenant
+	// logger is closed when the limit is exceeded, and it is created again when it is used.
+	// If it is less than or equal to 0, DefaultMaxTenants is used.
+	MaxTenants int
+
+	// Setup configures the created tenant logger, such as the formatter, the hooks and the
+	// shared output writer. If it is nil, the tenant logger is created with the defaults.
+	Setup func(tenant string, l Logger)
+
+	// Open opens the dedicated output writer of the tenant, which is owned and closed by the
+	// tenant logger. If it is nil, or it returns an error, the output set by Setup is used.
+	Open func(tenant string) (io.WriteCloser, error)
+}
+
+// TenantLoggers interface defines the loggers keyed by the tenant ids for the multi-tenant
+// services that must segregate the log streams. Each tenant has its own logger with its own
+// level, default fields and optional dedicated writer:
+//
+//	tl := NewTenantLoggers("app", TenantLoggersOptions{Open: openTenantFile})
+//	tl.SetLevel("acme", DebugLevel)
+//	tl.Get("acme").Info("Order created")
+type TenantLoggers interface {
+	io.Closer
+
+	// Get returns the log of the given tenant, which carries the tenant field and the
+	// default fields of the tenant. The tenant logger is created if necessary.
+	// The returned log should not be held for a long time, since the tenant logger may
+	// be closed when it is evicted.
+	Get(tenant string) Log
+
+	// SetLevel sets the level override of the given tenant.
+	// When the given log level is invalid, the level override of the tenant is removed.
+	SetLevel(tenant string, level Level)
+
+	// SetFields sets the default fields of the given tenant.
+	SetFields(tenant string, fields map[string]interface{})
+
+	// Len returns the number of the tenant loggers kept.
+	Len() int
+}
+
+// NewTenantLoggers creates and returns the tenant loggers, the given name is the name of
+// all the tenant loggers.
+func NewTenantLoggers(name string, opts TenantLoggersOptions) TenantLoggers {
+	if opts.Field == "" {
+		opts.Field = DefaultTenantField
+	}
+	if opts.MaxTenants <= 0 {
+		opts.MaxTenants = DefaultMaxTenants
+	}
+	return &tenantLoggers{
+		name: name, opts: opts,
+		tenants: make(map[string]*list.Element), lru: list.New(),
+		levels: make(map[string]Level), fields: make(map[string]map[string]interface{}),
+	}
+}
+
+// The built-in tenant loggers.
+type tenantLoggers struct {
+	mu      sync.Mutex
+	name    string
+	opts    TenantLoggersOptions
+	tenants map[string]*list.Element
+	lru     *list.List // The tenant loggers, the most recently used is at the front.
+	levels  map[string]Level
+	fields  map[string]map[string]interface{}
+}
+
+// The logger of a tenant.
+type tenantLogger struct {
+	tenant string
+	logger Logger
+	log    Log   // The log with the tenant field and the default fields.
+	level  Level // The logger level configured by the setup function.
+}
+
+// Get returns the log of the given tenant, which carries the tenant field and the
+// default fields of the tenant. The tenant logger is created if necessary.
+func (t *tenantLoggers) Get(tenant string) Log {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, found := t.tenants[tenant]; found {
+		t.lru.MoveToFront(e)
+		return e.Value.(*tenantLogger).log
+	}
+
+	l := New(t.name)
+	if t.opts.Setup != nil {
+		t.opts.Setup(tenant, l)
+	}
+	if t.opts.Open != nil {
+		if w, err := t.opts.Open(tenant); err != nil {
+			internal.EchoError("(%s) Failed to open the writer of tenant %q: %s", t.name, tenant, err)
+		} else {
+			l.SetOwnedOutput(w)
+		}
+	}
+	tl := &tenantLogger{tenant: tenant, logger: l, level: l.GetLevel()}
+	if level, found := t.levels[tenant]; found {
+		l.SetLevel(level)
+	}
+	t.update(tl)
+	t.tenants[tenant] = t.lru.PushFront(tl)
+
+	for t.lru.Len() > t.opts.MaxTenants {
+		evicted := t.lru.Remove(t.lru.Back()).(*tenantLogger)
+		delete(t.tenants, evicted.tenant)
+		if err := evicted.logger.Close(); err != nil {
+			internal.EchoError("(%s) Failed to close the logger of tenant %q: %s", t.name, evicted.tenant, err)
+		}
+	}
+	return tl.log
+}
+
+// Updates the log of the given tenant logger with the default fields.
+func (t *tenantLoggers) update(tl *tenantLogger) {
+	tl.log = tl.logger.AsLog().WithFields(t.fields[tl.tenant]).WithField(t.opts.Field, tl.tenant)
+}
+
+// SetLevel sets the level override of the given tenant.
+// When the given log level is invalid, the level override of the tenant is removed.
+func (t *tenantLoggers) SetLevel(tenant string, level Level) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if level.IsValid() {
+		t.levels[tenant] = level
+	} else {
+		delete(t.levels, tenant)
+	}
+	if e, found := t.tenants[tenant]; found {
+		tl := e.Value.(*tenantLogger)
+		if level.IsValid() {
+			tl.logger.SetLevel(level)
+		} else {
+			tl.logger.SetLevel(tl.level)
+		}
+	}
+}
+
+// SetFields sets the default fields of the given tenant.
+func (t *tenantLoggers) SetFields(tenant string, fields map[string]interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(fields) == 0 {
+		delete(t.fields, tenant)
+	} else {
+		t.fields[tenant] = internal.MakeFields(fields)
+	}
+	if e, found := t.tenants[tenant]; found {
+		t.update(e.Value.(*tenantLogger))
+	}
+}
+
+// Len returns the number of the tenant loggers kept.
+func (t *tenantLoggers) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lru.Len()
+}
+
+// Close closes all the tenant loggers and returns the first error encountered.
+func (t *tenantLoggers) Close() (err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for e := t.lru.Front(); e != nil; e = e.Next() {
+		if err2 := e.Value.(*tenantLogger).logger.Close(); err == nil {
+			err = err2
+		}
+	}
+	t.tenants = make(map[string]*list.Element)
+	t.lru.Init()
+	return
+}
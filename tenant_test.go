@@ -0,0 +1,104 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNewTenantLoggers(t *testing.T) {
+	tl := NewTenantLoggers("test", TenantLoggersOptions{})
+	if tl == nil {
+		t.Fatal("NewTenantLoggers(): nil")
+	}
+	if err := tl.Close(); err != nil {
+		t.Fatalf("TenantLoggers.Close(): error %s", err)
+	}
+}
+
+func TestTenantLoggers(t *testing.T) {
+	shared := new(bytes.Buffer)
+	writers := make(map[string]*testSyncBuffer)
+	tl := NewTenantLoggers("test", TenantLoggersOptions{
+		MaxTenants: 2,
+		Setup: func(tenant string, l Logger) {
+			l.SetOutput(shared).SetDefaultTimeFormat("test").SetLevel(InfoLevel)
+		},
+		Open: func(tenant string) (io.WriteCloser, error) {
+			if tenant == "c" {
+				return nil, errors.New("test")
+			}
+			w := new(testSyncBuffer)
+			writers[tenant] = w
+			return w, nil
+		},
+	})
+
+	tl.SetFields("a", map[string]interface{}{"plan": "pro"})
+	tl.SetLevel("a", DebugLevel)
+	tl.Get("a").Debug("foo")
+	tl.Get("b").Debug("foo")
+	tl.Get("b").Info("bar")
+
+	want := `{"fields":{"plan":"pro","tenant":"a"},"level":"debug","message":"foo","name":"test","time":"test"}` + "\n"
+	if got := string(writers["a"].Bytes()); got != want {
+		t.Fatalf("TenantLoggers.Get(): want %q, got %q", want, got)
+	}
+	want = `{"fields":{"tenant":"b"},"level":"info","message":"bar","name":"test","time":"test"}` + "\n"
+	if got := string(writers["b"].Bytes()); got != want {
+		t.Fatalf("TenantLoggers.Get(): want %q, got %q", want, got)
+	}
+	if got := tl.Len(); got != 2 {
+		t.Fatalf("TenantLoggers.Len(): %d", got)
+	}
+
+	// The tenant "a" is evicted, and the tenant "c" writes to the shared writer.
+	tl.Get("c").Info("baz")
+	if got := tl.Len(); got != 2 {
+		t.Fatalf("TenantLoggers.Len(): %d", got)
+	}
+	if !writers["a"].closed || writers["b"].closed {
+		t.Fatal("TenantLoggers.Get(): the least recently used tenant logger is not closed")
+	}
+	if got := shared.String(); !strings.Contains(got, `"tenant":"c"`) {
+		t.Fatalf("TenantLoggers.Get(): %q", got)
+	}
+
+	// The level override and the default fields of the tenant are kept after eviction.
+	tl.Get("a").Debug("foo")
+	if got := string(writers["a"].Bytes()); !strings.Contains(got, `"plan":"pro"`) {
+		t.Fatalf("TenantLoggers.Get(): %q", got)
+	}
+
+	tl.SetLevel("a", Level(0))
+	tl.SetFields("a", nil)
+	tl.Get("a").Debug("foo")
+	tl.Get("a").Info("bar")
+	want = `{"fields":{"tenant":"a"},"level":"info","message":"bar","name":"test","time":"test"}` + "\n"
+	if got := string(writers["a"].Bytes()); !strings.HasSuffix(got, want) || strings.Count(got, "\n") != 2 {
+		t.Fatalf("TenantLoggers.Get(): %q", got)
+	}
+
+	if err := tl.Close(); err != nil {
+		t.Fatalf("TenantLoggers.Close(): error %s", err)
+	}
+	if tl.Len() != 0 || !writers["a"].closed {
+		t.Fatal("TenantLoggers.Close(): not closed")
+	}
+}
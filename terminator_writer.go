@@ -0,0 +1,55 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+)
+
+// NewTerminatorWriter creates a writer that replaces the trailing newline of each written
+// log with the given terminator, such as "\r\n" or "\x00".
+// This writer is used to change the record terminator of a single log writer, if the record
+// terminator of all log writers needs to be changed, use Logger.SetRecordTerminator instead.
+func NewTerminatorWriter(w io.Writer, terminator string) io.Writer {
+	return &terminatorWriter{w: w, terminator: terminator}
+}
+
+// This writer replaces the trailing newline of each written log.
+type terminatorWriter struct {
+	w          io.Writer
+	terminator string
+}
+
+// Write is the implementation of io.Writer interface.
+// The returned number of bytes is relative to the given data.
+func (w *terminatorWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if n == 0 || p[n-1] != '\n' || w.terminator == "\n" {
+		return w.w.Write(p)
+	}
+	b := make([]byte, 0, n-1+len(w.terminator))
+	b = append(append(b, p[:n-1]...), w.terminator...)
+	k, err := w.w.Write(b)
+	if err == nil && k != len(b) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		if k > n-1 {
+			k = n - 1
+		}
+		return k, err
+	}
+	return n, nil
+}
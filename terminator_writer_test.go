@@ -0,0 +1,61 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewTerminatorWriter(t *testing.T) {
+	if NewTerminatorWriter(new(bytes.Buffer), "\r\n") == nil {
+		t.Fatal("NewTerminatorWriter(): return nil")
+	}
+}
+
+func TestTerminatorWriter_Write(t *testing.T) {
+	b := new(bytes.Buffer)
+	w := NewTerminatorWriter(b, "\r\n")
+
+	if n, err := w.Write([]byte("foo\n")); err != nil || n != 4 {
+		t.Fatalf("TerminatorWriter.Write(): %d %v", n, err)
+	}
+	if n, err := w.Write([]byte("bar")); err != nil || n != 3 {
+		t.Fatalf("TerminatorWriter.Write(): %d %v", n, err)
+	}
+	if got := b.String(); got != "foo\r\nbar" {
+		t.Fatalf("TerminatorWriter.Write(): %q", got)
+	}
+
+	w = NewTerminatorWriter(testErrorWriter("test"), "\x00")
+	if _, err := w.Write([]byte("foo\n")); err == nil {
+		t.Fatal("TerminatorWriter.Write(): nil error")
+	}
+}
+
+func TestLogger_SetRecordTerminator(t *testing.T) {
+	b := new(bytes.Buffer)
+	o := New("test").SetOutput(b).SetFormatter(MustNewTextFormatter("{message}", false))
+
+	if o.SetRecordTerminator("\x00") == nil {
+		t.Fatal("Logger.SetRecordTerminator(): return nil")
+	}
+	o.Info("foo")
+	o.SetRecordTerminator("\n")
+	o.Info("bar")
+	if got := b.String(); got != "foo\x00bar\n" {
+		t.Fatalf("Logger.SetRecordTerminator(): %q", got)
+	}
+}
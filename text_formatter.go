@@ -17,6 +17,7 @@ package logger
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -25,7 +26,7 @@ import (
 )
 
 // This regular expression is used to analyze placeholders in text formatter format.
-var formatRegexp = regexp.MustCompile(`{(name|time|level|message|caller|stack|fields)(?:@?([^{}]*)?)?}`)
+var formatRegexp = regexp.MustCompile(`{(name|time|level|message|caller|stack|fields|id)(?:@?([^{}]*)?)?}`)
 
 // The default text formatter.
 var defaultTextFormatter = MustNewTextFormatter("{name}:[{time}][{level@sc}] {message}{caller}{fields}{stack}", false)
@@ -55,6 +56,7 @@ func DefaultQuoteTextFormatter() Formatter {
 //     {message}   The message of this log.
 //     {fields}    The extended fields of this log. (if it exists)
 //     {stack}     The call stack of this log. (if it exists)
+//     {id}        The unique id of this log. (if enabled)
 // It is worth knowing:
 //     1. For the {time} parameter, we can specify time format, like this: {time@2006-01-02 15:04:05}.
 //     2. For the {level} parameter, we can specify level format, like this: {level@sc},
@@ -67,13 +69,75 @@ func DefaultQuoteTextFormatter() Formatter {
 //        If this behavior is not needed, use {caller@?} or {fields@?} or {stack@?} parameters.
 // The quote parameter is used to escape invisible characters in the log.
 func NewTextFormatter(format string, quote bool) (Formatter, error) {
+	return NewTextFormatterWithOptions(TextFormatterOptions{Format: format, Quote: quote})
+}
+
+// TextFormatterOptions defines the options of the text formatter.
+type TextFormatterOptions struct {
+	// Format is used to control the format of the log, see NewTextFormatter.
+	Format string
+
+	// Quote is used to escape invisible characters in the log.
+	Quote bool
+
+	// LevelLabels overrides the strings emitted for the log levels, such as "warning"
+	// instead of "warn", the level format parameters are ignored for the mapped levels.
+	// It does not change the semantics of ParseLevel.
+	LevelLabels map[Level]string
+
+	// QuotedPlaceholders is the names of the placeholders whose values are quoted as Go
+	// string literals, such as []string{"message", "fields"}, for the fields placeholder,
+	// each field value is quoted. Unlike Quote, the separators in the format are kept raw,
+	// so the log lines can still be split by the downstream parsers.
+	QuotedPlaceholders []string
+
+	// MultilineIndent is used to indent the continuation lines of the multi-line messages
+	// and field values, such as "    ", so the wrapped messages remain visually grouped and
+	// are not mistaken for separate logs. If it is empty, the messages are kept as is.
+	MultilineIndent string
+
+	// NestedFields determines how the map and struct field values are rendered, such as the
+	// nested key paths (a.b=c) or the inline JSON, the default is TextNestedFieldsString.
+	NestedFields TextNestedFields
+}
+
+// TextNestedFields defines how the text formatter renders the map and struct field values.
+type TextNestedFields int
+
+const (
+	// TextNestedFieldsString indicates that the nested field values are rendered by their
+	// default string forms (fmt.Sprint).
+	TextNestedFieldsString TextNestedFields = iota
+
+	// TextNestedFieldsPath indicates that the nested field values are expanded to the nested
+	// key paths, such as "a.b=c", and the struct values are expanded by their JSON encoding.
+	TextNestedFieldsPath
+
+	// TextNestedFieldsJSON indicates that the nested field values are rendered as the inline
+	// JSON, such as "a={"b":"c"}".
+	TextNestedFieldsJSON
+)
+
+// NewTextFormatterWithOptions creates and returns an instance of the log text formatter
+// with the given options.
+func NewTextFormatterWithOptions(opts TextFormatterOptions) (Formatter, error) {
+	format := opts.Format
 	sub := formatRegexp.FindAllStringSubmatch(format, -1)
 	if len(sub) == 0 {
 		return nil, fmt.Errorf("invalid text formatter format %q", format)
 	}
 	// If sub is not empty, then idx is definitely not empty.
 	idx := formatRegexp.FindAllStringIndex(format, -1)
-	f := &textFormatter{quote: quote, callerPrefix: " ", fieldsPrefix: " ", stackPrefix: " "}
+	f := &textFormatter{
+		quote: opts.Quote, labels: copyLevelLabels(opts.LevelLabels), nested: opts.NestedFields,
+		callerPrefix: " ", fieldsPrefix: " ", stackPrefix: " ",
+	}
+	for _, name := range opts.QuotedPlaceholders {
+		if f.quoted == nil {
+			f.quoted = make(map[string]bool)
+		}
+		f.quoted[name] = true
+	}
 
 	var parts []string
 	var start int
@@ -102,6 +166,8 @@ func NewTextFormatter(format string, quote bool) (Formatter, error) {
 			}
 		case "message":
 			f.encoders = append(f.encoders, f.encodeMessage)
+		case "id":
+			f.encoders = append(f.encoders, f.encodeID)
 		case "caller":
 			f.encoders = append(f.encoders, f.encodeCaller)
 			if args == "?" {
@@ -118,11 +184,17 @@ func NewTextFormatter(format string, quote bool) (Formatter, error) {
 				f.stackPrefix = ""
 			}
 		}
+		if opts.MultilineIndent != "" && (key == "message" || key == "fields") {
+			f.encoders[len(f.encoders)-1] = indentEncoder(opts.MultilineIndent, f.encoders[len(f.encoders)-1])
+		}
+		if f.quoted[key] && key != "fields" {
+			f.encoders[len(f.encoders)-1] = f.quoteEncoder(key, f.encoders[len(f.encoders)-1])
+		}
 		parts = append(parts, format[start:idx[i][0]])
 		start = idx[i][1]
 	}
 
-	f.format = strings.Join(append(parts, format[start:]), "%s")
+	f.parts = append(parts, format[start:])
 	return f, nil
 }
 
@@ -135,10 +207,23 @@ func MustNewTextFormatter(format string, quote bool) Formatter {
 	return f
 }
 
+// MustNewTextFormatterWithOptions is like NewTextFormatterWithOptions, but triggers a panic
+// when an error occurs.
+func MustNewTextFormatterWithOptions(opts TextFormatterOptions) Formatter {
+	f, err := NewTextFormatterWithOptions(opts)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
 // The built-in text formatter.
 type textFormatter struct {
-	format       string
+	parts        []string // The literal parts around the placeholders.
 	quote        bool
+	labels       map[Level]string
+	quoted       map[string]bool // The names of the quoted placeholders.
+	nested       TextNestedFields
 	encoders     []func(Entity) string
 	timeFormat   string
 	callerPrefix string
@@ -147,22 +232,70 @@ type textFormatter struct {
 }
 
 // Format formats the given log entity into character data and writes it to the given buffer.
-func (f *textFormatter) Format(e Entity, b *bytes.Buffer) (err error) {
-	args := make([]interface{}, len(f.encoders))
-	for i, j := 0, len(f.encoders); i < j; i++ {
-		args[i] = f.encoders[i](e)
-	}
-	if f.quote {
-		// The quoted[0] and quoted[len(s)-1] is '"', they need to be removed.
-		quoted := strconv.AppendQuote(nil, fmt.Sprintf(f.format, args...))
-		quoted[len(quoted)-1] = '\n'
-		_, err = b.Write(quoted[1:])
-	} else {
-		_, err = b.WriteString(fmt.Sprintf(f.format, args...) + "\n")
+func (f *textFormatter) Format(e Entity, b *bytes.Buffer) error {
+	return f.FormatTo(e, b)
+}
+
+// FormatTo formats the given log entity into character data and writes it to the given writer.
+// The literal parts and the placeholder values are written to the writer one by one, so the
+// log is not buffered in full.
+func (f *textFormatter) FormatTo(e Entity, w io.Writer) error {
+	var buf []byte
+	for i, j := 0, len(f.encoders); i <= j; i++ {
+		if err := f.writeString(w, f.parts[i], &buf); err != nil {
+			return err
+		}
+		if i < j {
+			if err := f.writeString(w, f.encoders[i](e), &buf); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Writes the given string to the given writer, the string is escaped as a part of the quoted
+// log line if the quote is enabled, the given buffer is reused to quote the strings.
+func (f *textFormatter) writeString(w io.Writer, s string, buf *[]byte) (err error) {
+	if s == "" {
+		return
+	}
+	if !f.quote {
+		_, err = io.WriteString(w, s)
+		return
 	}
+	// The quoted[0] and quoted[len(s)-1] is '"', they need to be removed.
+	*buf = strconv.AppendQuote((*buf)[:0], s)
+	_, err = w.Write((*buf)[1 : len(*buf)-1])
 	return
 }
 
+// Wraps the given encoder of the given placeholder to quote its value.
+func (f *textFormatter) quoteEncoder(key string, encoder func(Entity) string) func(Entity) string {
+	var prefix string
+	switch key {
+	case "caller":
+		prefix = f.callerPrefix
+	case "stack":
+		prefix = f.stackPrefix
+	default:
+		return func(e Entity) string { return strconv.Quote(encoder(e)) }
+	}
+	// The prefix of the optional placeholders is not quoted, and the empty value is not quoted.
+	return func(e Entity) string {
+		if s := encoder(e); s != "" {
+			return prefix + strconv.Quote(s[len(prefix):])
+		}
+		return ""
+	}
+}
+
+// Wraps the given encoder to indent the continuation lines of its value.
+func indentEncoder(indent string, encoder func(Entity) string) func(Entity) string {
+	return func(e Entity) string { return internal.IndentLines(encoder(e), indent) }
+}
+
 // Encode the name of the log.
 func (f *textFormatter) encodeName(e Entity) string {
 	return e.Name()
@@ -170,21 +303,33 @@ func (f *textFormatter) encodeName(e Entity) string {
 
 // Encode the level of the log.
 func (f *textFormatter) encodeLevel(e Entity) string {
+	if s, found := f.labels[e.Level()]; found {
+		return s
+	}
 	return e.Level().String()
 }
 
 // Encode the short capital level of the log.
 func (f *textFormatter) encodeShortCapitalLevel(e Entity) string {
+	if s, found := f.labels[e.Level()]; found {
+		return s
+	}
 	return e.Level().ShortCapitalString()
 }
 
 // Encode the short level of the log.
 func (f *textFormatter) encodeShortLevel(e Entity) string {
+	if s, found := f.labels[e.Level()]; found {
+		return s
+	}
 	return e.Level().ShortString()
 }
 
 // Encode the capital level of the log.
 func (f *textFormatter) encodeCapitalLevel(e Entity) string {
+	if s, found := f.labels[e.Level()]; found {
+		return s
+	}
 	return e.Level().CapitalString()
 }
 
@@ -206,6 +351,11 @@ func (f *textFormatter) encodeCaller(e Entity) string {
 	return ""
 }
 
+// Encode the id of the log.
+func (f *textFormatter) encodeID(e Entity) string {
+	return e.ID()
+}
+
 // Encode the message of the log.
 func (f *textFormatter) encodeMessage(e Entity) string {
 	return e.Message()
@@ -214,7 +364,16 @@ func (f *textFormatter) encodeMessage(e Entity) string {
 // Encode the fields of the log.
 func (f *textFormatter) encodeFields(e Entity) string {
 	if fields := e.Fields(); len(fields) > 0 {
-		return f.fieldsPrefix + internal.FormatFieldsToText(e.Fields())
+		switch f.nested {
+		case TextNestedFieldsPath:
+			fields = internal.ExpandNestedFields(fields)
+		case TextNestedFieldsJSON:
+			fields = internal.InlineNestedFields(fields)
+		}
+		if f.quoted["fields"] {
+			return f.fieldsPrefix + internal.FormatFieldsToQuotedText(fields)
+		}
+		return f.fieldsPrefix + internal.FormatFieldsToText(fields)
 	}
 	return ""
 }
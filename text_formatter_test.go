@@ -16,6 +16,7 @@ package logger
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -138,3 +139,173 @@ func TestTextFormatter_Format_WithStack(t *testing.T) {
 		t.Fatalf("TextFormatter.Format(): %s", buf.String())
 	}
 }
+
+func TestMustNewTextFormatterWithOptions(t *testing.T) {
+	if MustNewTextFormatterWithOptions(TextFormatterOptions{Format: "{message}"}) == nil {
+		t.Fatal("MustNewTextFormatterWithOptions(): nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustNewTextFormatterWithOptions(): no panic")
+		}
+	}()
+
+	MustNewTextFormatterWithOptions(TextFormatterOptions{Format: "test"})
+}
+
+func TestTextFormatter_Format_WithLevelLabels(t *testing.T) {
+	labels := map[Level]string{WarnLevel: "warning", ErrorLevel: ""}
+	items := []struct {
+		Format string
+		Level  Level
+		Want   string
+	}{
+		{"{level}", WarnLevel, "warning"},
+		{"{level@sc}", WarnLevel, "warning"},
+		{"{level@s}", WarnLevel, "warning"},
+		{"{level@c}", WarnLevel, "warning"},
+		{"{level}", ErrorLevel, "error"},
+		{"{level@c}", InfoLevel, "INFO"},
+	}
+
+	for _, item := range items {
+		f := MustNewTextFormatterWithOptions(TextFormatterOptions{Format: item.Format, LevelLabels: labels})
+		buf := new(bytes.Buffer)
+		if err := f.Format(&logEntity{level: item.Level}, buf); err != nil {
+			t.Fatalf("TextFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("TextFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+}
+
+func TestTextFormatter_Format_WithID(t *testing.T) {
+	f := MustNewTextFormatter("[{id}] {message}", false)
+	buf := new(bytes.Buffer)
+	if err := f.Format(&logEntity{message: "test", id: "foo"}, buf); err != nil {
+		t.Fatalf("TextFormatter.Format(): error %s", err)
+	}
+	if got, want := buf.String(), "[foo] test\n"; got != want {
+		t.Fatalf("TextFormatter.Format(): want %q, got %q", want, got)
+	}
+}
+
+func TestTextFormatter_Format_WithQuotedPlaceholders(t *testing.T) {
+	e := &logEntity{
+		name: "test", level: InfoLevel, message: "foo\tbar", caller: "a b.go:1",
+		fields: map[string]interface{}{"a": "x, y", "b": 1}, stack: []string{"s"},
+	}
+	items := []struct {
+		Format string
+		Quoted []string
+		Want   string
+	}{
+		{"{name}\t{message}{fields}", []string{"message"}, "test\t\"foo\\tbar\" a=x, y, b=1"},
+		{"{name}\t{message}{fields}", []string{"fields"}, "test\tfoo\tbar a=\"x, y\", b=\"1\""},
+		{"{message}{caller}{stack}", []string{"caller", "stack"}, "foo\tbar \"a b.go:1\" \"s\""},
+		{"{message}|{caller@?}", []string{"caller"}, "foo\tbar|\"a b.go:1\""},
+	}
+
+	for _, item := range items {
+		f := MustNewTextFormatterWithOptions(TextFormatterOptions{Format: item.Format, QuotedPlaceholders: item.Quoted})
+		buf := new(bytes.Buffer)
+		if err := f.Format(e, buf); err != nil {
+			t.Fatalf("TextFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want+"\n" {
+			t.Fatalf("TextFormatter.Format(): want %q, got %q", item.Want+"\n", got)
+		}
+	}
+
+	// The empty optional placeholders are not quoted.
+	f := MustNewTextFormatterWithOptions(TextFormatterOptions{Format: "{message}{caller}", QuotedPlaceholders: []string{"caller"}})
+	buf := new(bytes.Buffer)
+	if err := f.Format(&logEntity{message: "test"}, buf); err != nil {
+		t.Fatalf("TextFormatter.Format(): error %s", err)
+	}
+	if got := buf.String(); got != "test\n" {
+		t.Fatalf("TextFormatter.Format(): %q", got)
+	}
+}
+
+func TestTextFormatter_Format_WithMultilineIndent(t *testing.T) {
+	e := &logEntity{level: InfoLevel, message: "foo\nbar", fields: map[string]interface{}{"a": "x\ny"}}
+	f := MustNewTextFormatterWithOptions(TextFormatterOptions{Format: "[{level}] {message}{fields}", MultilineIndent: "  "})
+	buf := new(bytes.Buffer)
+	if err := f.Format(e, buf); err != nil {
+		t.Fatalf("TextFormatter.Format(): error %s", err)
+	}
+	if got, want := buf.String(), "[info] foo\n  bar a=x\n  y\n"; got != want {
+		t.Fatalf("TextFormatter.Format(): want %q, got %q", want, got)
+	}
+
+	f = MustNewTextFormatterWithOptions(TextFormatterOptions{
+		Format: "{message}", MultilineIndent: "  ", QuotedPlaceholders: []string{"message"},
+	})
+	buf.Reset()
+	if err := f.Format(e, buf); err != nil {
+		t.Fatalf("TextFormatter.Format(): error %s", err)
+	}
+	if got, want := buf.String(), "\"foo\\n  bar\"\n"; got != want {
+		t.Fatalf("TextFormatter.Format(): want %q, got %q", want, got)
+	}
+}
+
+func TestTextFormatter_Format_WithNestedFields(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	e := &logEntity{level: InfoLevel, message: "foo", fields: map[string]interface{}{
+		"a": map[string]interface{}{"b": "c"},
+		"p": point{X: 1, Y: 2},
+	}}
+	items := []struct {
+		Mode TextNestedFields
+		Want string
+	}{
+		{TextNestedFieldsString, "foo a=map[b:c], p={1 2}\n"},
+		{TextNestedFieldsPath, "foo a.b=c, p.x=1, p.y=2\n"},
+		{TextNestedFieldsJSON, "foo a={\"b\":\"c\"}, p={\"x\":1,\"y\":2}\n"},
+	}
+	for _, item := range items {
+		f := MustNewTextFormatterWithOptions(TextFormatterOptions{Format: "{message}{fields}", NestedFields: item.Mode})
+		buf := new(bytes.Buffer)
+		if err := f.Format(e, buf); err != nil {
+			t.Fatalf("TextFormatter.Format(): error %s", err)
+		}
+		if got := buf.String(); got != item.Want {
+			t.Fatalf("TextFormatter.Format(): want %q, got %q", item.Want, got)
+		}
+	}
+}
+
+func TestTextFormatter_FormatTo(t *testing.T) {
+	e := &logEntity{level: InfoLevel, message: "foo\n\"bar\"", fields: map[string]interface{}{"a": "中文"}}
+	for _, quote := range []bool{false, true} {
+		f := MustNewTextFormatter("[{level}] 100% {message}{fields}", quote)
+		want := new(bytes.Buffer)
+		if err := f.Format(e, want); err != nil {
+			t.Fatalf("TextFormatter.Format(): error %s", err)
+		}
+		cw := new(testChunkWriter)
+		if err := f.(StreamFormatter).FormatTo(e, cw); err != nil {
+			t.Fatalf("TextFormatter.FormatTo(): error %s", err)
+		}
+		if got := strings.Join(cw.chunks, ""); got != want.String() || len(cw.chunks) != 6 {
+			t.Fatalf("TextFormatter.FormatTo(): want %q, got %q", want.String(), cw.chunks)
+		}
+	}
+	want := "[info] 100% foo\\n\\\"bar\\\" a=中文\n"
+	buf := new(bytes.Buffer)
+	if err := MustNewTextFormatter("[{level}] 100% {message}{fields}", true).Format(e, buf); err != nil || buf.String() != want {
+		t.Fatalf("TextFormatter.Format(): want %q, got %q", want, buf.String())
+	}
+
+	cw := &testChunkWriter{err: errors.New("test")}
+	if err := MustNewTextFormatter("{message}", false).(StreamFormatter).FormatTo(e, cw); err == nil {
+		t.Fatal("TextFormatter.FormatTo(): nil error")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"time"
+)
+
+// Timer interface defines a stopwatch that records the elapsed time of an operation.
+// The timer is started when it is created, and a completion log with the "elapsed" field
+// is recorded when the Done method is called.
+// The timer instance is not safe for concurrent use.
+type Timer interface {
+	// WithLevel sets the level of the completion log, the default level is InfoLevel.
+	// If the given level is invalid, this method does nothing.
+	WithLevel(Level) Timer
+
+	// WithSlowThreshold sets the slow threshold of the operation, if the elapsed time
+	// exceeds the given threshold, the completion log is recorded at the given level.
+	// If the given threshold is less than or equal to 0 or the given level is invalid,
+	// the slow threshold is disabled.
+	WithSlowThreshold(time.Duration, Level) Timer
+
+	// Elapsed returns the elapsed time since the timer was started.
+	Elapsed() time.Duration
+
+	// Done records the completion log and returns the elapsed time.
+	Done() time.Duration
+}
+
+// The built-in timer.
+type logTimer struct {
+	log       *log
+	message   string
+	start     time.Time
+	level     Level
+	slow      time.Duration
+	slowLevel Level
+}
+
+// Creates a new timer bound to the given log.
+func newLogTimer(o *log, message string) *logTimer {
+	return &logTimer{log: o, message: message, start: o.core.nowFunc(), level: InfoLevel}
+}
+
+// WithLevel sets the level of the completion log, the default level is InfoLevel.
+// If the given level is invalid, this method does nothing.
+func (t *logTimer) WithLevel(level Level) Timer {
+	if level.IsValid() {
+		t.level = level
+	}
+	return t
+}
+
+// WithSlowThreshold sets the slow threshold of the operation, if the elapsed time
+// exceeds the given threshold, the completion log is recorded at the given level.
+// If the given threshold is less than or equal to 0 or the given level is invalid,
+// the slow threshold is disabled.
+func (t *logTimer) WithSlowThreshold(threshold time.Duration, level Level) Timer {
+	if threshold > 0 && level.IsValid() {
+		t.slow, t.slowLevel = threshold, level
+	} else {
+		t.slow, t.slowLevel = 0, 0
+	}
+	return t
+}
+
+// Elapsed returns the elapsed time since the timer was started.
+func (t *logTimer) Elapsed() time.Duration {
+	return t.log.core.nowFunc().Sub(t.start)
+}
+
+// Done records the completion log and returns the elapsed time.
+func (t *logTimer) Done() time.Duration {
+	d := t.Elapsed()
+	level := t.level
+	if t.slow > 0 && d > t.slow {
+		level = t.slowLevel
+	}
+	// Calling the internal log method directly ensures that the caller reported is the
+	// caller of the Done method.
+	t.log.withField("elapsed", d).log(level, t.message)
+	return d
+}
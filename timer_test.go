@@ -0,0 +1,70 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog_Timer(t *testing.T) {
+	now := time.Now()
+	o := New("test").SetOutput(io.Discard).SetNowFunc(func() time.Time { return now })
+
+	var level Level
+	var message, caller string
+	var elapsed interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		level, message, caller, elapsed = s.Level(), s.Message(), s.Caller(), s.Fields()["elapsed"]
+		return nil
+	})
+	o.EnableCaller()
+
+	tm := o.Timer("load users")
+	if tm == nil {
+		t.Fatal("Log.Timer(): nil")
+	}
+	now = now.Add(time.Second)
+	if d := tm.Elapsed(); d != time.Second {
+		t.Fatalf("Timer.Elapsed(): %s", d)
+	}
+	if d := tm.Done(); d != time.Second {
+		t.Fatalf("Timer.Done(): %s", d)
+	}
+	if level != InfoLevel || message != "load users" || elapsed != time.Second {
+		t.Fatalf("Timer.Done(): %s %s %v", level, message, elapsed)
+	}
+	if !strings.HasPrefix(caller, "timer_test.go:") {
+		t.Fatalf("Timer.Done(): caller %s", caller)
+	}
+
+	tm = o.Timer("slow").WithLevel(DebugLevel).WithLevel(0).WithSlowThreshold(time.Second, WarnLevel)
+	now = now.Add(time.Second)
+	tm.Done()
+	if level != DebugLevel {
+		t.Fatalf("Timer.Done(): %s", level)
+	}
+	now = now.Add(time.Second * 2)
+	tm.Done()
+	if level != WarnLevel || elapsed != time.Second*3 {
+		t.Fatalf("Timer.Done(): %s %v", level, elapsed)
+	}
+	tm.WithSlowThreshold(0, WarnLevel).Done()
+	if level != DebugLevel {
+		t.Fatalf("Timer.Done(): %s", level)
+	}
+}
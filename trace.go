@@ -0,0 +1,221 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TraceContext defines the distributed tracing information of the log.
+type TraceContext struct {
+	// TraceID is the id of the whole trace.
+	TraceID string
+
+	// ParentID is the id of the caller span (the parent-id of the W3C traceparent, or the
+	// span id of the B3 propagation).
+	ParentID string
+
+	// ParentSpanID is the parent id of the caller span, only the B3 propagation carries it.
+	ParentSpanID string
+
+	// Sampled indicates whether the trace is sampled by the caller.
+	Sampled bool
+}
+
+// Fields returns the log fields of the current trace context.
+func (tc TraceContext) Fields() map[string]interface{} {
+	if tc.ParentSpanID == "" {
+		return map[string]interface{}{"trace_id": tc.TraceID, "parent_id": tc.ParentID}
+	}
+	return map[string]interface{}{
+		"trace_id": tc.TraceID, "parent_id": tc.ParentID, "parent_span_id": tc.ParentSpanID,
+	}
+}
+
+// ParseTraceparent parses the given W3C traceparent header value.
+// The format of the traceparent is "VERSION-TRACEID-PARENTID-FLAGS", for example:
+//
+//	00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(s string) (TraceContext, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 {
+		return TraceContext{}, fmt.Errorf("invalid traceparent %q", s)
+	}
+	version := parts[0]
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent version %q", s)
+	}
+	if !isLowerHex(parts[1], 32) || isZeroHex(parts[1]) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent trace id %q", s)
+	}
+	if !isLowerHex(parts[2], 16) || isZeroHex(parts[2]) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent parent id %q", s)
+	}
+	if !isLowerHex(parts[3], 2) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent flags %q", s)
+	}
+	// The sampled flag is the lowest bit of the trace flags.
+	sampled := strings.IndexByte("13579bdf", parts[3][1]) >= 0
+	return TraceContext{TraceID: parts[1], ParentID: parts[2], Sampled: sampled}, nil
+}
+
+// ParseB3 parses the given Zipkin B3 single header value.
+// The format of the B3 single header is "TRACEID-SPANID[-SAMPLED[-PARENTSPANID]]", for example:
+//
+//	80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90
+//
+// The header that only carries the sampling state (such as "0") is considered invalid,
+// because it does not carry trace information.
+func ParseB3(s string) (TraceContext, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		return TraceContext{}, fmt.Errorf("invalid b3 %q", s)
+	}
+	tc := TraceContext{TraceID: parts[0], ParentID: parts[1]}
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "1", "d":
+			tc.Sampled = true
+		case "0":
+		default:
+			return TraceContext{}, fmt.Errorf("invalid b3 sampling state %q", s)
+		}
+	}
+	if len(parts) > 3 {
+		tc.ParentSpanID = parts[3]
+	}
+	if err := validateB3(tc); err != nil {
+		return TraceContext{}, fmt.Errorf("invalid b3 %q: %s", s, err)
+	}
+	return tc, nil
+}
+
+// ParseB3Headers parses the Zipkin B3 multiple headers (X-B3-TraceId, X-B3-SpanId,
+// X-B3-ParentSpanId, X-B3-Sampled and X-B3-Flags).
+// The given function is used to get the header value by the header name, such as http.Header.Get.
+func ParseB3Headers(get func(string) string) (TraceContext, error) {
+	tc := TraceContext{
+		TraceID:      strings.TrimSpace(get("X-B3-TraceId")),
+		ParentID:     strings.TrimSpace(get("X-B3-SpanId")),
+		ParentSpanID: strings.TrimSpace(get("X-B3-ParentSpanId")),
+	}
+	switch strings.TrimSpace(get("X-B3-Sampled")) {
+	case "1", "true":
+		tc.Sampled = true
+	}
+	// The debug flag implies an accept sampling decision.
+	if strings.TrimSpace(get("X-B3-Flags")) == "1" {
+		tc.Sampled = true
+	}
+	if err := validateB3(tc); err != nil {
+		return TraceContext{}, fmt.Errorf("invalid b3 headers: %s", err)
+	}
+	return tc, nil
+}
+
+// ParseTraceHeaders parses the trace context from the W3C traceparent header or the Zipkin B3
+// single or multiple headers, in that order of precedence.
+// The given function is used to get the header value by the header name, such as http.Header.Get.
+func ParseTraceHeaders(get func(string) string) (TraceContext, error) {
+	if s := get("traceparent"); s != "" {
+		return ParseTraceparent(s)
+	}
+	if s := get("b3"); s != "" {
+		return ParseB3(s)
+	}
+	if get("X-B3-TraceId") != "" {
+		return ParseB3Headers(get)
+	}
+	return TraceContext{}, fmt.Errorf("trace headers not found")
+}
+
+// Validates the B3 trace context.
+func validateB3(tc TraceContext) error {
+	if !isLowerHex(tc.TraceID, 32) && !isLowerHex(tc.TraceID, 16) || isZeroHex(tc.TraceID) {
+		return fmt.Errorf("invalid trace id %q", tc.TraceID)
+	}
+	if !isLowerHex(tc.ParentID, 16) || isZeroHex(tc.ParentID) {
+		return fmt.Errorf("invalid span id %q", tc.ParentID)
+	}
+	if tc.ParentSpanID != "" && (!isLowerHex(tc.ParentSpanID, 16) || isZeroHex(tc.ParentSpanID)) {
+		return fmt.Errorf("invalid parent span id %q", tc.ParentSpanID)
+	}
+	return nil
+}
+
+// The key type of the trace context stored in the context.
+type traceContextKey struct{}
+
+// WithTraceContext returns a copy of the given context that carries the given trace context.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// WithTraceparent parses the given W3C traceparent header value and returns a copy of the
+// given context that carries the parsed trace context.
+// If the given traceparent is invalid, the given context and the parsing error is returned.
+func WithTraceparent(ctx context.Context, traceparent string) (context.Context, error) {
+	tc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx, err
+	}
+	return WithTraceContext(ctx, tc), nil
+}
+
+// WithTraceHeaders parses the trace context from the given headers (see ParseTraceHeaders) and
+// returns a copy of the given context that carries the parsed trace context.
+// If the trace context can not be parsed, the given context and the parsing error is returned.
+func WithTraceHeaders(ctx context.Context, get func(string) string) (context.Context, error) {
+	tc, err := ParseTraceHeaders(get)
+	if err != nil {
+		return ctx, err
+	}
+	return WithTraceContext(ctx, tc), nil
+}
+
+// GetTraceContext returns the trace context carried by the given context.
+func GetTraceContext(ctx context.Context) (TraceContext, bool) {
+	if ctx != nil {
+		if tc, ok := ctx.Value(traceContextKey{}).(TraceContext); ok {
+			return tc, true
+		}
+	}
+	return TraceContext{}, false
+}
+
+// Determines whether the given string is a lowercase hex string of the given length.
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Determines whether the given hex string is all zeros.
+func isZeroHex(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,176 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("ParseTraceparent(): %s", err)
+	}
+	if tc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || tc.ParentID != "00f067aa0ba902b7" || !tc.Sampled {
+		t.Fatalf("ParseTraceparent(): %+v", tc)
+	}
+	if tc, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future"); err != nil {
+		t.Fatalf("ParseTraceparent(): %s", err)
+	}
+	if tc.Sampled {
+		t.Fatalf("ParseTraceparent(): %+v", tc)
+	}
+
+	items := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x",
+	}
+	for _, item := range items {
+		if _, err := ParseTraceparent(item); err == nil {
+			t.Fatalf("ParseTraceparent(%q): nil error", item)
+		}
+	}
+}
+
+func TestWithTraceparent(t *testing.T) {
+	ctx, err := WithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("WithTraceparent(): %s", err)
+	}
+	if tc, found := GetTraceContext(ctx); !found || tc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("GetTraceContext(): %+v %v", tc, found)
+	}
+	if _, err = WithTraceparent(ctx, "invalid"); err == nil {
+		t.Fatal("WithTraceparent(): nil error")
+	}
+	if _, found := GetTraceContext(context.Background()); found {
+		t.Fatal("GetTraceContext(): found")
+	}
+}
+
+func TestLogger_EnableTraceContext(t *testing.T) {
+	o := New("test").SetOutput(io.Discard)
+
+	if o.EnableTraceContext() == nil {
+		t.Fatal("Logger.EnableTraceContext(): nil")
+	}
+
+	var fields map[string]interface{}
+	o.AddHookFunc(GetAllLevels(), func(s Summary) error {
+		fields = s.Fields()
+		return nil
+	})
+
+	ctx := WithTraceContext(context.Background(), TraceContext{TraceID: "foo", ParentID: "bar"})
+	o.WithContext(ctx).Info("test")
+	if len(fields) != 2 || fields["trace_id"] != "foo" || fields["parent_id"] != "bar" {
+		t.Fatalf("Logger.EnableTraceContext(): %v", fields)
+	}
+}
+
+func TestParseB3(t *testing.T) {
+	tc, err := ParseB3("80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90")
+	if err != nil {
+		t.Fatalf("ParseB3(): %s", err)
+	}
+	want := TraceContext{
+		TraceID: "80f198ee56343ba864fe8b2a57d3eff7", ParentID: "e457b5a2e4d86bd1",
+		ParentSpanID: "05e3ac9a4f6e3b90", Sampled: true,
+	}
+	if tc != want {
+		t.Fatalf("ParseB3(): %+v", tc)
+	}
+	if fields := tc.Fields(); len(fields) != 3 || fields["parent_span_id"] != "05e3ac9a4f6e3b90" {
+		t.Fatalf("TraceContext.Fields(): %v", fields)
+	}
+	if tc, err = ParseB3("64fe8b2a57d3eff7-e457b5a2e4d86bd1"); err != nil || tc.Sampled {
+		t.Fatalf("ParseB3(): %+v %v", tc, err)
+	}
+
+	items := []string{
+		"0",
+		"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-x",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90-1",
+		"80f198ee56343ba864fe8b2a57d3eff-e457b5a2e4d86bd1",
+		"80f198ee56343ba864fe8b2a57d3eff7-0000000000000000",
+		"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f",
+	}
+	for _, item := range items {
+		if _, err := ParseB3(item); err == nil {
+			t.Fatalf("ParseB3(%q): nil error", item)
+		}
+	}
+}
+
+func TestParseB3Headers(t *testing.T) {
+	h := map[string]string{
+		"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+		"X-B3-SpanId":  "e457b5a2e4d86bd1",
+		"X-B3-Flags":   "1",
+	}
+	get := func(k string) string { return h[k] }
+	tc, err := ParseB3Headers(get)
+	if err != nil {
+		t.Fatalf("ParseB3Headers(): %s", err)
+	}
+	if tc.TraceID != h["X-B3-TraceId"] || tc.ParentID != h["X-B3-SpanId"] || !tc.Sampled {
+		t.Fatalf("ParseB3Headers(): %+v", tc)
+	}
+	delete(h, "X-B3-SpanId")
+	if _, err = ParseB3Headers(get); err == nil {
+		t.Fatal("ParseB3Headers(): nil error")
+	}
+}
+
+func TestParseTraceHeaders(t *testing.T) {
+	h := map[string]string{}
+	get := func(k string) string { return h[k] }
+
+	if _, err := ParseTraceHeaders(get); err == nil {
+		t.Fatal("ParseTraceHeaders(): nil error")
+	}
+	if _, err := WithTraceHeaders(context.Background(), get); err == nil {
+		t.Fatal("WithTraceHeaders(): nil error")
+	}
+
+	h["X-B3-TraceId"] = "80f198ee56343ba864fe8b2a57d3eff7"
+	h["X-B3-SpanId"] = "e457b5a2e4d86bd1"
+	h["X-B3-Sampled"] = "1"
+	if tc, err := ParseTraceHeaders(get); err != nil || tc.ParentID != "e457b5a2e4d86bd1" || !tc.Sampled {
+		t.Fatalf("ParseTraceHeaders(): %+v %v", tc, err)
+	}
+
+	h["b3"] = "64fe8b2a57d3eff7-05e3ac9a4f6e3b90"
+	if tc, err := ParseTraceHeaders(get); err != nil || tc.ParentID != "05e3ac9a4f6e3b90" {
+		t.Fatalf("ParseTraceHeaders(): %+v %v", tc, err)
+	}
+
+	h["traceparent"] = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx, err := WithTraceHeaders(context.Background(), get)
+	if err != nil {
+		t.Fatalf("WithTraceHeaders(): %s", err)
+	}
+	if tc, _ := GetTraceContext(ctx); tc.ParentID != "00f067aa0ba902b7" {
+		t.Fatalf("WithTraceHeaders(): %+v", tc)
+	}
+}
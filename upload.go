@@ -0,0 +1,93 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/edoger/zkits-logger/internal"
+)
+
+// Uploader interface defines the minimal object storage client used to upload the
+// rotated log files, it can be easily implemented by S3, GCS or MinIO clients.
+type Uploader interface {
+	// Upload uploads the content of the given reader as the object with the given key.
+	Upload(ctx context.Context, key string, r io.Reader, size int64) error
+}
+
+// UploaderOptions defines the options of the rotated log file uploader.
+type UploaderOptions struct {
+	// Prefix is prepended to the backup file name to build the object key, for example,
+	// "logs/app/" or "app-".
+	Prefix string
+
+	// KeepLocal indicates whether to keep the local backup file after the upload succeeds.
+	// By default, the uploaded backup files are deleted.
+	KeepLocal bool
+
+	// Timeout limits the duration of each upload, if it is less than or equal to 0, the
+	// upload duration is not limited.
+	Timeout time.Duration
+
+	// OnError is called when the upload fails, by default, the error is printed to the
+	// standard error output.
+	OnError func(backup string, err error)
+}
+
+// NewUploadRotateCallback creates and returns a rotation callback that uploads the backup
+// log files by the given uploader, it can be used by FileWriterOptions.OnRotate.
+// The backup file is kept if the upload fails.
+func NewUploadRotateCallback(u Uploader, opts UploaderOptions) func(backup string) {
+	return func(backup string) {
+		if err := uploadFile(u, opts, backup); err != nil {
+			if opts.OnError != nil {
+				opts.OnError(backup, err)
+			} else {
+				internal.EchoError("Failed to upload %s: %s.", backup, err)
+			}
+		}
+	}
+}
+
+// Uploads the given file by the given uploader.
+func uploadFile(u Uploader, opts UploaderOptions, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	err = u.Upload(ctx, opts.Prefix+filepath.Base(name), file, info.Size())
+	if err2 := file.Close(); err == nil {
+		err = err2
+	}
+	if err != nil || opts.KeepLocal {
+		return err
+	}
+	return os.Remove(name)
+}
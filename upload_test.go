@@ -0,0 +1,161 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testUploader struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (u *testUploader) Upload(ctx context.Context, key string, r io.Reader, size int64) error {
+	if u.err != nil {
+		return u.err
+	}
+	if _, found := ctx.Deadline(); !found {
+		return errors.New("no deadline")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	u.objects[key] = data
+	return nil
+}
+
+func TestNewUploadRotateCallback(t *testing.T) {
+	dir := t.TempDir()
+	backup := filepath.Join(dir, "test-backup.log")
+	if err := os.WriteFile(backup, []byte("test\n"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+	u := &testUploader{objects: make(map[string][]byte)}
+	NewUploadRotateCallback(u, UploaderOptions{Prefix: "logs/", Timeout: time.Second})(backup)
+
+	if got := u.objects["logs/test-backup.log"]; !bytes.Equal(got, []byte("test\n")) {
+		t.Fatalf("NewUploadRotateCallback(): uploaded %q", got)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Fatalf("NewUploadRotateCallback(): backup not removed: %v", err)
+	}
+}
+
+func TestNewUploadRotateCallback_KeepLocal(t *testing.T) {
+	backup := filepath.Join(t.TempDir(), "test-backup.log")
+	if err := os.WriteFile(backup, []byte("test\n"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+	u := &testUploader{objects: make(map[string][]byte)}
+	NewUploadRotateCallback(u, UploaderOptions{KeepLocal: true, Timeout: time.Second})(backup)
+
+	if _, found := u.objects["test-backup.log"]; !found {
+		t.Fatal("NewUploadRotateCallback(): not uploaded")
+	}
+	if _, err := os.Stat(backup); err != nil {
+		t.Fatalf("NewUploadRotateCallback(): backup removed: %s", err)
+	}
+}
+
+func TestNewUploadRotateCallback_Error(t *testing.T) {
+	backup := filepath.Join(t.TempDir(), "test-backup.log")
+	if err := os.WriteFile(backup, []byte("test\n"), filePerm); err != nil {
+		t.Fatal(err)
+	}
+	var got error
+	u := &testUploader{err: errors.New("test")}
+	NewUploadRotateCallback(u, UploaderOptions{
+		OnError: func(name string, err error) {
+			if name == backup {
+				got = err
+			}
+		},
+	})(backup)
+
+	if got == nil || got.Error() != "test" {
+		t.Fatalf("NewUploadRotateCallback(): error %v", got)
+	}
+	if _, err := os.Stat(backup); err != nil {
+		t.Fatalf("NewUploadRotateCallback(): backup removed: %s", err)
+	}
+
+	// The missing backup files are reported.
+	got = nil
+	NewUploadRotateCallback(u, UploaderOptions{
+		OnError: func(_ string, err error) { got = err },
+	})(backup + ".missing")
+	if got == nil {
+		t.Fatal("NewUploadRotateCallback(): nil error")
+	}
+	// The default error handler prints the error.
+	NewUploadRotateCallback(u, UploaderOptions{})(backup)
+}
+
+func TestNewUploadRotateCallback_FileWriter(t *testing.T) {
+	dir := t.TempDir()
+	u := &testUploader{objects: make(map[string][]byte)}
+	var errs []error
+	done := make(chan string, 1)
+	w := MustNewFileWriterWithOptions(filepath.Join(dir, "test.log"), FileWriterOptions{
+		MaxSize: 10,
+		OnRotate: []func(string){
+			// Gives the sweeper of the default backup limit time to run first.
+			func(string) { time.Sleep(50 * time.Millisecond) },
+			NewUploadRotateCallback(u, UploaderOptions{
+				KeepLocal: true,
+				Timeout:   time.Second,
+				OnError:   func(_ string, err error) { errs = append(errs, err) },
+			}),
+			func(backup string) { done <- backup },
+		},
+	})
+	defer func() { _ = w.Close() }()
+
+	if _, err := w.Write([]byte("0123456789\n")); err != nil {
+		t.Fatal(err)
+	}
+	var backup string
+	select {
+	case backup = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("NewUploadRotateCallback(): callback not called")
+	}
+	if len(errs) > 0 {
+		t.Fatalf("NewUploadRotateCallback(): errors %v", errs)
+	}
+	if got := u.objects[filepath.Base(backup)]; string(got) != "0123456789\n" {
+		t.Fatalf("NewUploadRotateCallback(): uploaded %q", got)
+	}
+	// The backup file is removed by the backup limit after the callbacks are finished.
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("NewUploadRotateCallback(): backup not removed")
+}
@@ -0,0 +1,23 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+// WriterFunc type is an adapter to allow the use of ordinary functions as the log writers.
+type WriterFunc func([]byte) (int, error)
+
+// Write is an implementation of the io.Writer interface, it calls f(p).
+func (f WriterFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
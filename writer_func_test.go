@@ -0,0 +1,33 @@
+// Copyright 2023 The ZKits Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"testing"
+)
+
+func TestWriterFunc(t *testing.T) {
+	var got string
+	w := WriterFunc(func(p []byte) (int, error) {
+		got = string(p)
+		return len(p), nil
+	})
+	if n, err := w.Write([]byte("test")); err != nil || n != 4 {
+		t.Fatalf("WriterFunc.Write(): %d, %v", n, err)
+	}
+	if got != "test" {
+		t.Fatalf("WriterFunc.Write(): %q", got)
+	}
+}